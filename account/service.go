@@ -0,0 +1,168 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"practice-run/auth"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidUsername  = errors.New("username must be 3 to 32 letters, digits or underscores")
+	ErrInvalidPassword  = errors.New("password must be 8 to 72 bytes long")
+	ErrUsernameReserved = errors.New("username reserved")
+)
+
+var usernameRegex = regexp.MustCompile(`^\w{3,32}$`)
+
+type userStore interface {
+	Get(username string) (*User, error)
+	Create(user *User) error
+}
+
+type Session struct {
+	Token     string
+	Username  string
+	ExpiresAt time.Time
+}
+
+// Service registers users and logs them in, issuing session tokens that it
+// then accepts as an auth.Authenticator.
+type Service struct {
+	store      userStore
+	sessionTTL time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+	// reserved holds the lowercased usernames that can't be registered
+	reserved map[string]bool
+}
+
+func NewService(store userStore, sessionTTL time.Duration) *Service {
+	return &Service{
+		store:      store,
+		sessionTTL: sessionTTL,
+		sessions:   make(map[string]*Session),
+		reserved:   make(map[string]bool),
+	}
+}
+
+// Reserve keeps usernames from being registered, regardless of case, for
+// users authenticated otherwise, such as by JWT or API key, or granted
+// permissions by name, not to be impersonated by registering their name.
+func (s *Service) Reserve(usernames ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, username := range usernames {
+		s.reserved[strings.ToLower(username)] = true
+	}
+}
+
+func (s *Service) Register(ctx context.Context, username, password string) error {
+	if !usernameRegex.MatchString(username) {
+		return ErrInvalidUsername
+	}
+
+	if len(password) < 8 || len(password) > 72 {
+		return ErrInvalidPassword
+	}
+
+	s.mu.Lock()
+	reserved := s.reserved[strings.ToLower(username)]
+	s.mu.Unlock()
+
+	if reserved {
+		return ErrUsernameReserved
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return s.store.Create(&User{
+		Username:     username,
+		PasswordHash: hash,
+		CreatedAt:    time.Now(),
+	})
+}
+
+func (s *Service) Login(ctx context.Context, username, password string) (*Session, error) {
+	user, err := s.store.Get(username)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password)) != nil {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate session token: %w", err)
+	}
+
+	session := &Session{
+		Token:     base64.RawURLEncoding.EncodeToString(raw),
+		Username:  user.Username,
+		ExpiresAt: time.Now().Add(s.sessionTTL),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeExpired(time.Now())
+	s.sessions[session.Token] = session
+
+	return session, nil
+}
+
+// removeExpired removes the expired sessions, which are otherwise only
+// removed when presented again. It's called with the lock held.
+func (s *Service) removeExpired(now time.Time) {
+	for token, session := range s.sessions {
+		if now.After(session.ExpiresAt) {
+			delete(s.sessions, token)
+		}
+	}
+}
+
+func (s *Service) Authenticate(ctx context.Context, token string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[token]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown session", auth.ErrInvalidCredentials)
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		delete(s.sessions, token)
+		return "", fmt.Errorf("%w: session expired", auth.ErrInvalidCredentials)
+	}
+
+	return session.Username, nil
+}
+
+// Groups returns the groups of a registered user, implementing
+// chat.GroupResolver.
+func (s *Service) Groups(username string) []string {
+	user, err := s.store.Get(username)
+	if err != nil {
+		return nil
+	}
+
+	return user.Groups
+}
@@ -0,0 +1,147 @@
+package account_test
+
+import (
+	"context"
+	"path/filepath"
+	"practice-run/account"
+	"practice-run/auth"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type Suite struct {
+	suite.Suite
+	path  string
+	store *account.FileStore
+	svc   *account.Service
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSubTest() {
+	s.path = filepath.Join(s.T().TempDir(), "accounts.json")
+
+	store, err := account.OpenFileStore(s.path)
+	s.Require().NoError(err)
+
+	s.store = store
+	s.svc = account.NewService(s.store, time.Minute)
+}
+
+func (s *Suite) TestRegister() {
+	s.Run("ok", func() {
+		// When
+		err := s.svc.Register(context.Background(), "user_1", "password")
+
+		// Then
+		s.NoError(err)
+		user, _ := s.store.Get("user_1")
+		s.NotEqual([]byte("password"), user.PasswordHash)
+	})
+
+	s.Run("invalid username", func() {
+		// When
+		err := s.svc.Register(context.Background(), "user 1", "password")
+
+		// Then
+		s.ErrorIs(err, account.ErrInvalidUsername)
+	})
+
+	s.Run("invalid password", func() {
+		// When
+		err := s.svc.Register(context.Background(), "user_1", "short")
+
+		// Then
+		s.ErrorIs(err, account.ErrInvalidPassword)
+	})
+
+	s.Run("username taken regardless of case", func() {
+		// Given
+		_ = s.svc.Register(context.Background(), "user_1", "password")
+
+		// When
+		err := s.svc.Register(context.Background(), "USER_1", "password")
+
+		// Then
+		s.ErrorIs(err, account.ErrUserAlreadyExists)
+	})
+
+	s.Run("username reserved regardless of case", func() {
+		// Given
+		s.svc.Reserve("admin")
+
+		// When
+		err := s.svc.Register(context.Background(), "Admin", "password")
+
+		// Then
+		s.ErrorIs(err, account.ErrUsernameReserved)
+	})
+}
+
+func (s *Suite) TestLogin() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		_ = s.svc.Register(ctx, "user_1", "password")
+
+		// When
+		session, err := s.svc.Login(ctx, "user_1", "password")
+
+		// Then
+		s.NoError(err)
+		s.NotEmpty(session.Token)
+		username, err := s.svc.Authenticate(ctx, session.Token)
+		s.NoError(err)
+		s.Equal("user_1", username)
+	})
+
+	s.Run("wrong password", func() {
+		// Given
+		ctx := context.Background()
+		_ = s.svc.Register(ctx, "user_1", "password")
+
+		// When
+		session, err := s.svc.Login(ctx, "user_1", "wrong password")
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+		s.Nil(session)
+	})
+
+	s.Run("unknown user", func() {
+		// When
+		session, err := s.svc.Login(context.Background(), "user_1", "password")
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+		s.Nil(session)
+	})
+}
+
+func (s *Suite) TestAuthenticate() {
+	s.Run("unknown session", func() {
+		// When
+		_, err := s.svc.Authenticate(context.Background(), "token")
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+	})
+
+	s.Run("expired session", func() {
+		// Given
+		ctx := context.Background()
+		svc := account.NewService(s.store, -time.Minute)
+		_ = svc.Register(ctx, "user_1", "password")
+		session, _ := svc.Login(ctx, "user_1", "password")
+
+		// When
+		_, err := svc.Authenticate(ctx, session.Token)
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+	})
+}
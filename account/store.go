@@ -0,0 +1,121 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrUserAlreadyExists = errors.New("username already taken")
+)
+
+type User struct {
+	Username     string    `json:"username"`
+	PasswordHash []byte    `json:"password_hash"`
+	Groups       []string  `json:"groups,omitempty"` // edited by operators, referred to by room ACLs
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// FileStore keeps users in a JSON file, rewritten on every change.
+// Usernames are unique regardless of case.
+type FileStore struct {
+	mu    sync.Mutex
+	path  string
+	users map[string]*User // keyed by lowercase username
+}
+
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, users: make(map[string]*User)}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user store: %w", err)
+	}
+
+	var users []*User
+	if err := json.Unmarshal(raw, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode user store: %w", err)
+	}
+
+	for _, user := range users {
+		s.users[strings.ToLower(user.Username)] = user
+	}
+
+	return s, nil
+}
+
+func (s *FileStore) Get(username string) (*User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[strings.ToLower(username)]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (s *FileStore) Create(user *User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(user.Username)
+	if _, ok := s.users[key]; ok {
+		return ErrUserAlreadyExists
+	}
+
+	s.users[key] = user
+
+	if err := s.save(); err != nil {
+		delete(s.users, key)
+		return err
+	}
+
+	return nil
+}
+
+// save writes the users to a temporary file and renames it over the store,
+// so that a crash never leaves a truncated file behind.
+func (s *FileStore) save() error {
+	users := make([]*User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+
+	raw, err := json.MarshalIndent(users, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode user store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write user store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write user store: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write user store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write user store: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package account_test
+
+import (
+	"practice-run/account"
+	"time"
+)
+
+func (s *Suite) TestFileStore() {
+	s.Run("persist users", func() {
+		// Given
+		user := &account.User{Username: "user_1", PasswordHash: []byte("hash"), CreatedAt: time.Now().UTC()}
+		s.Require().NoError(s.store.Create(user))
+
+		// When
+		reopened, err := account.OpenFileStore(s.path)
+
+		// Then
+		s.Require().NoError(err)
+		got, err := reopened.Get("user_1")
+		s.NoError(err)
+		s.Equal(user.PasswordHash, got.PasswordHash)
+		s.True(user.CreatedAt.Equal(got.CreatedAt))
+	})
+
+	s.Run("user not found", func() {
+		// When
+		_, err := s.store.Get("user_1")
+
+		// Then
+		s.ErrorIs(err, account.ErrUserNotFound)
+	})
+}
@@ -0,0 +1,187 @@
+// Package admin implements the HTTP API used by operators to manage a live
+// server.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"practice-run/auth"
+	"practice-run/chat"
+	"time"
+)
+
+type chatService interface {
+	ListConnections(ctx context.Context) []chat.ConnectionInfo
+	ListRooms(ctx context.Context) []chat.RoomInfo
+	DisconnectUser(ctx context.Context, username string, reason string) error
+	RemoveUser(ctx context.Context, roomName string, username string) error
+	DeleteRoom(ctx context.Context, roomName string) error
+	Broadcast(ctx context.Context, event chat.Event) error
+}
+
+type admins interface {
+	IsAdmin(username string) bool
+}
+
+// Handler serves the admin API to authenticated administrators:
+//
+//	GET    /connections                          list connections
+//	DELETE /connections/{username}               disconnect a user
+//	GET    /rooms                                list rooms
+//	DELETE /rooms/{room}                         delete a room
+//	DELETE /rooms/{room}/members/{username}      remove a member from a room
+//	POST   /announcements                        broadcast an announcement
+type Handler struct {
+	mux           *http.ServeMux
+	chatService   chatService
+	authenticator auth.Authenticator
+	admins        admins
+	logger        *slog.Logger
+}
+
+func NewHandler(chatService chatService, authenticator auth.Authenticator, admins admins, logger *slog.Logger) *Handler {
+	h := &Handler{
+		mux:           http.NewServeMux(),
+		chatService:   chatService,
+		authenticator: authenticator,
+		admins:        admins,
+		logger:        logger,
+	}
+
+	h.mux.HandleFunc("GET /connections", h.listConnections)
+	h.mux.HandleFunc("DELETE /connections/{username}", h.disconnectUser)
+	h.mux.HandleFunc("GET /rooms", h.listRooms)
+	h.mux.HandleFunc("DELETE /rooms/{room}", h.deleteRoom)
+	h.mux.HandleFunc("DELETE /rooms/{room}/members/{username}", h.removeMember)
+	h.mux.HandleFunc("POST /announcements", h.announce)
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, _, err := auth.TokenFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	username, err := h.authenticator.Authenticate(r.Context(), token)
+	if err != nil {
+		http.Error(w, auth.ErrInvalidCredentials.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if !h.admins.IsAdmin(username) {
+		h.logger.Info("rejected admin request", slog.String("username", username), slog.String("method", r.Method), slog.String("path", r.URL.Path))
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	h.logger.Info("admin request", slog.String("username", username), slog.String("method", r.Method), slog.String("path", r.URL.Path))
+
+	h.mux.ServeHTTP(w, r)
+}
+
+type connection struct {
+	Username    string    `json:"username"`
+	RemoteAddr  string    `json:"remote_addr"`
+	ConnectedAt time.Time `json:"connected_at"`
+	Rooms       []string  `json:"rooms"`
+}
+
+func (h *Handler) listConnections(w http.ResponseWriter, r *http.Request) {
+	connections := []connection{}
+	for _, c := range h.chatService.ListConnections(r.Context()) {
+		connections = append(connections, connection(c))
+	}
+
+	h.writeJSON(w, http.StatusOK, connections)
+}
+
+type disconnectRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h *Handler) disconnectUser(w http.ResponseWriter, r *http.Request) {
+	var req disconnectRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request: failed to parse body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := h.chatService.DisconnectUser(r.Context(), r.PathValue("username"), req.Reason)
+	h.writeResult(w, err)
+}
+
+type room struct {
+	Name    string `json:"name"`
+	Members int    `json:"members"`
+}
+
+func (h *Handler) listRooms(w http.ResponseWriter, r *http.Request) {
+	rooms := []room{}
+	for _, info := range h.chatService.ListRooms(r.Context()) {
+		rooms = append(rooms, room(info))
+	}
+
+	h.writeJSON(w, http.StatusOK, rooms)
+}
+
+func (h *Handler) deleteRoom(w http.ResponseWriter, r *http.Request) {
+	err := h.chatService.DeleteRoom(r.Context(), r.PathValue("room"))
+	h.writeResult(w, err)
+}
+
+func (h *Handler) removeMember(w http.ResponseWriter, r *http.Request) {
+	err := h.chatService.RemoveUser(r.Context(), r.PathValue("room"), r.PathValue("username"))
+	h.writeResult(w, err)
+}
+
+type announcementRequest struct {
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+func (h *Handler) announce(w http.ResponseWriter, r *http.Request) {
+	var req announcementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Message == "" {
+		http.Error(w, "bad request: expected a message", http.StatusBadRequest)
+		return
+	}
+
+	severity, err := chat.ParseSeverity(req.Severity)
+	if err != nil {
+		http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.chatService.Broadcast(r.Context(), &chat.SystemAnnouncementEvent{Message: req.Message, Severity: severity})
+	h.writeResult(w, err)
+}
+
+// writeResult responds with 204 on success, or the status matching err.
+func (h *Handler) writeResult(w http.ResponseWriter, err error) {
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, chat.ErrRoomNotFound), errors.Is(err, chat.ErrNotConnected), errors.Is(err, chat.ErrNotRoomMember):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Error("admin request failed", slog.Any("error", err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to write response", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,240 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"practice-run/admin"
+	"practice-run/auth"
+	"practice-run/chat"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type Suite struct {
+	suite.Suite
+	chatService   *chat.Service
+	authenticator *auth.JWTAuthenticator
+	server        *httptest.Server
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSubTest() {
+	s.chatService = chat.NewService()
+	s.authenticator = auth.NewJWTAuthenticator([]byte("secret"))
+	s.server = httptest.NewServer(admin.NewHandler(s.chatService, s.authenticator, auth.NewAdmins([]string{"admin"}, nil, nil), slog.Default()))
+}
+
+func (s *Suite) TearDownSubTest() {
+	s.server.Close()
+}
+
+type MockMember struct {
+	mu       sync.Mutex
+	username string
+	events   []chat.Event
+}
+
+func (m *MockMember) Username() string {
+	return m.username
+}
+
+func (m *MockMember) Notify(event chat.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, event)
+}
+
+func (m *MockMember) lastEvent() chat.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.events) == 0 {
+		return nil
+	}
+
+	return m.events[len(m.events)-1]
+}
+
+func (s *Suite) TestAuthentication() {
+	s.Run("reject unauthenticated requests", func() {
+		// When
+		res := s.request(http.MethodGet, "/rooms", "", "")
+
+		// Then
+		s.Equal(http.StatusUnauthorized, res.StatusCode)
+	})
+
+	s.Run("reject non-administrators", func() {
+		// When
+		res := s.request(http.MethodGet, "/rooms", "user_1", "")
+
+		// Then
+		s.Equal(http.StatusForbidden, res.StatusCode)
+	})
+}
+
+func (s *Suite) TestConnections() {
+	s.Run("list connections", func() {
+		// Given
+		ctx := context.Background()
+		member := &MockMember{username: "user_1"}
+		_ = s.chatService.Connect(ctx, member)
+		_, _ = s.chatService.CreateRoom(ctx, "room_1", "user_1")
+		_ = s.chatService.AddMember(ctx, "room_1", member)
+
+		// When
+		res := s.request(http.MethodGet, "/connections", "admin", "")
+
+		// Then
+		s.Equal(http.StatusOK, res.StatusCode)
+
+		var body []map[string]any
+		s.Require().NoError(json.NewDecoder(res.Body).Decode(&body))
+		s.Require().Len(body, 1)
+		s.Equal("user_1", body[0]["username"])
+		s.Equal([]any{"room_1"}, body[0]["rooms"])
+		s.NotEmpty(body[0]["connected_at"])
+	})
+
+	s.Run("disconnect a user", func() {
+		// Given
+		member := &MockMember{username: "user_1"}
+		_ = s.chatService.Connect(context.Background(), member)
+
+		// When
+		res := s.request(http.MethodDelete, "/connections/user_1", "admin", `{"reason": "spam"}`)
+
+		// Then
+		s.Equal(http.StatusNoContent, res.StatusCode)
+		s.Equal(&chat.DisconnectedEvent{Reason: "spam"}, member.lastEvent())
+		s.False(s.chatService.IsConnected(context.Background(), "user_1"))
+	})
+
+	s.Run("disconnect a user that isn't connected", func() {
+		// When
+		res := s.request(http.MethodDelete, "/connections/user_1", "admin", "")
+
+		// Then
+		s.Equal(http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func (s *Suite) TestRooms() {
+	s.Run("list rooms", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.chatService.CreateRoom(ctx, "room_1", "user_1")
+		_ = s.chatService.AddMember(ctx, "room_1", &MockMember{username: "user_1"})
+
+		// When
+		res := s.request(http.MethodGet, "/rooms", "admin", "")
+
+		// Then
+		s.Equal(http.StatusOK, res.StatusCode)
+
+		var body []map[string]any
+		s.Require().NoError(json.NewDecoder(res.Body).Decode(&body))
+		s.Equal([]map[string]any{{"name": "room_1", "members": float64(1)}}, body)
+	})
+
+	s.Run("delete a room", func() {
+		// Given
+		ctx := context.Background()
+		member := &MockMember{username: "user_1"}
+		_, _ = s.chatService.CreateRoom(ctx, "room_1", "user_1")
+		_ = s.chatService.AddMember(ctx, "room_1", member)
+
+		// When
+		res := s.request(http.MethodDelete, "/rooms/room_1", "admin", "")
+
+		// Then
+		s.Equal(http.StatusNoContent, res.StatusCode)
+		s.Equal(&chat.RoomDeletedEvent{RoomName: "room_1"}, member.lastEvent())
+		s.Empty(s.chatService.ListRooms(ctx))
+	})
+
+	s.Run("remove a member", func() {
+		// Given
+		ctx := context.Background()
+		member := &MockMember{username: "user_1"}
+		_, _ = s.chatService.CreateRoom(ctx, "room_1", "user_1")
+		_ = s.chatService.AddMember(ctx, "room_1", member)
+
+		// When
+		res := s.request(http.MethodDelete, "/rooms/room_1/members/user_1", "admin", "")
+
+		// Then
+		s.Equal(http.StatusNoContent, res.StatusCode)
+		s.Equal(&chat.RemovedEvent{RoomName: "room_1"}, member.lastEvent())
+	})
+
+	s.Run("room not found", func() {
+		// When
+		res := s.request(http.MethodDelete, "/rooms/room_1", "admin", "")
+
+		// Then
+		s.Equal(http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func (s *Suite) TestAnnouncements() {
+	s.Run("broadcast an announcement", func() {
+		// Given
+		member := &MockMember{username: "user_1"}
+		_ = s.chatService.Connect(context.Background(), member)
+
+		// When
+		res := s.request(http.MethodPost, "/announcements", "admin", `{"message": "maintenance at noon", "severity": "warning"}`)
+
+		// Then
+		s.Equal(http.StatusNoContent, res.StatusCode)
+		s.Equal(&chat.SystemAnnouncementEvent{Message: "maintenance at noon", Severity: chat.SeverityWarning}, member.lastEvent())
+	})
+
+	s.Run("invalid severity", func() {
+		// When
+		res := s.request(http.MethodPost, "/announcements", "admin", `{"message": "maintenance at noon", "severity": "urgent"}`)
+
+		// Then
+		s.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+
+	s.Run("missing message", func() {
+		// When
+		res := s.request(http.MethodPost, "/announcements", "admin", `{}`)
+
+		// Then
+		s.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+// request sends a request authenticated as username, unless empty.
+func (s *Suite) request(method, path, username, body string) *http.Response {
+	req, err := http.NewRequest(method, s.server.URL+path, strings.NewReader(body))
+	s.Require().NoError(err)
+
+	if username != "" {
+		token, err := s.authenticator.Sign(username, time.Minute)
+		s.Require().NoError(err)
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = res.Body.Close()
+	})
+
+	return res
+}
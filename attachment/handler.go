@@ -0,0 +1,244 @@
+package attachment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"practice-run/auth"
+)
+
+// uploadField is the multipart form field of uploaded files.
+const uploadField = "file"
+
+type chatService interface {
+	CanDownload(ctx context.Context, username string, id string) (bool, error)
+}
+
+// Handler serves attachments to authenticated users:
+//
+//	POST /attachments                   upload the file of the "file" multipart form field
+//	GET  /attachments/{id}              download a file
+//	GET  /attachments/{id}/thumbnail    download the thumbnail of an image
+//
+// Files may be downloaded by their uploader and the members of the rooms they
+// have been sent to.
+type Handler struct {
+	mux           *http.ServeMux
+	store         *Store
+	chatService   chatService
+	authenticator auth.Authenticator
+	logger        *slog.Logger
+}
+
+func NewHandler(store *Store, chatService chatService, authenticator auth.Authenticator, logger *slog.Logger) *Handler {
+	h := &Handler{
+		mux:           http.NewServeMux(),
+		store:         store,
+		chatService:   chatService,
+		authenticator: authenticator,
+		logger:        logger,
+	}
+
+	h.mux.HandleFunc("POST /attachments", h.upload)
+	h.mux.HandleFunc("GET /attachments/{id}", h.download)
+	h.mux.HandleFunc("GET /attachments/{id}/thumbnail", h.downloadThumbnail)
+
+	return h
+}
+
+type usernameKey struct{}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, _, err := auth.TokenFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	username, err := h.authenticator.Authenticate(r.Context(), token)
+	if err != nil {
+		http.Error(w, auth.ErrInvalidCredentials.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	h.mux.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), usernameKey{}, username)))
+}
+
+type upload struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Size      int64      `json:"size"`
+	Type      string     `json:"type"`
+	URL       string     `json:"url"`
+	Width     int        `json:"width,omitempty"`
+	Height    int        `json:"height,omitempty"`
+	Thumbnail *thumbnail `json:"thumbnail,omitempty"`
+}
+
+type thumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+func (h *Handler) upload(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value(usernameKey{}).(string)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "bad request: expected a multipart form", http.StatusBadRequest)
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			http.Error(w, "bad request: failed to read multipart form", http.StatusBadRequest)
+			return
+		}
+
+		if part.FormName() != uploadField || part.FileName() == "" {
+			continue
+		}
+
+		attachment, err := h.store.Put(part.FileName(), username, part)
+		switch {
+		case errors.Is(err, ErrTooLarge):
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		case errors.Is(err, ErrTypeNotAllowed):
+			http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+			return
+		case err != nil:
+			h.logger.Error("failed to store attachment", slog.String("username", username), slog.Any("error", err))
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		h.logger.Info("attachment uploaded",
+			slog.String("username", username),
+			slog.String("attachment", attachment.ID),
+			slog.String("type", attachment.Type),
+			slog.Int64("size", attachment.Size),
+		)
+
+		res := upload{
+			ID:     attachment.ID,
+			Name:   attachment.Name,
+			Size:   attachment.Size,
+			Type:   attachment.Type,
+			URL:    URL(attachment.ID),
+			Width:  attachment.Width,
+			Height: attachment.Height,
+		}
+		if attachment.Thumbnail != nil {
+			res.Thumbnail = &thumbnail{
+				URL:    ThumbnailURL(attachment.ID),
+				Width:  attachment.Thumbnail.Width,
+				Height: attachment.Thumbnail.Height,
+			}
+		}
+
+		h.writeJSON(w, http.StatusCreated, res)
+
+		return
+	}
+
+	http.Error(w, "bad request: expected a file in the "+uploadField+" field", http.StatusBadRequest)
+}
+
+func (h *Handler) download(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !h.authorize(w, r, id) {
+		return
+	}
+
+	attachment, f, err := h.store.Open(id)
+	if err != nil {
+		h.logger.Error("failed to open attachment", slog.String("attachment", id), slog.Any("error", err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", attachment.Type)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(w, r, "", attachment.UploadedAt, f)
+}
+
+func (h *Handler) downloadThumbnail(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !h.authorize(w, r, id) {
+		return
+	}
+
+	attachment, f, err := h.store.OpenThumbnail(id)
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "thumbnail not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to open thumbnail", slog.String("attachment", id), slog.Any("error", err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", attachment.Thumbnail.Type)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(w, r, "", attachment.UploadedAt, f)
+}
+
+// authorize lets the uploader of the attachment id and the members of the
+// rooms it has been sent to download it. It's not found for anyone else.
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request, id string) bool {
+	username := r.Context().Value(usernameKey{}).(string)
+
+	attachment, err := h.store.Get(id)
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return false
+	}
+	if err != nil {
+		h.logger.Error("failed to read attachment", slog.String("attachment", id), slog.Any("error", err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return false
+	}
+
+	if attachment.Uploader == username {
+		return true
+	}
+
+	allowed, err := h.chatService.CanDownload(r.Context(), username, id)
+	if err != nil {
+		h.logger.Error("failed to authorize download", slog.String("attachment", id), slog.Any("error", err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return false
+	}
+
+	if !allowed {
+		http.Error(w, ErrNotFound.Error(), http.StatusNotFound)
+		return false
+	}
+
+	return true
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to write response", slog.Any("error", err))
+	}
+}
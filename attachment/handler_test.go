@@ -0,0 +1,232 @@
+package attachment_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"practice-run/attachment"
+	"practice-run/auth"
+	"practice-run/chat"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HandlerSuite struct {
+	suite.Suite
+	store         *attachment.Store
+	chatService   *chat.Service
+	authenticator *auth.JWTAuthenticator
+	server        *httptest.Server
+}
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(HandlerSuite))
+}
+
+func (s *HandlerSuite) SetupSubTest() {
+	store, err := attachment.NewStore(s.T().TempDir(), 1024, []string{"text/*", "image/png"})
+	s.Require().NoError(err)
+
+	s.store = store
+	s.chatService = chat.NewService(chat.WithAttachmentResolver(resolver{store: store}))
+	s.authenticator = auth.NewJWTAuthenticator([]byte("secret"))
+	s.server = httptest.NewServer(attachment.NewHandler(store, s.chatService, s.authenticator, slog.Default()))
+}
+
+func (s *HandlerSuite) TearDownSubTest() {
+	s.server.Close()
+}
+
+type resolver struct {
+	store *attachment.Store
+}
+
+func (r resolver) Attachment(_ context.Context, username string, id string) (*chat.Attachment, bool, error) {
+	a, err := r.store.Get(id)
+	if err != nil {
+		return nil, false, chat.ErrAttachmentNotFound
+	}
+
+	return &chat.Attachment{ID: a.ID, Name: a.Name, Size: a.Size, Type: a.Type, URL: attachment.URL(a.ID)}, a.Uploader == username, nil
+}
+
+type MockMember struct {
+	username string
+}
+
+func (m *MockMember) Username() string {
+	return m.username
+}
+
+func (m *MockMember) Notify(chat.Event) {}
+
+func (s *HandlerSuite) TestUpload() {
+	s.Run("ok", func() {
+		// When
+		res := s.upload("user_1", "server.log", "connection refused")
+
+		// Then
+		s.Equal(http.StatusCreated, res.StatusCode)
+		var body map[string]any
+		s.NoError(json.NewDecoder(res.Body).Decode(&body))
+		s.Equal("server.log", body["name"])
+		s.Equal(float64(18), body["size"])
+		s.Equal("text/plain; charset=utf-8", body["type"])
+		s.Equal("/attachments/"+body["id"].(string), body["url"])
+	})
+
+	s.Run("unauthenticated", func() {
+		// When
+		res := s.upload("", "server.log", "connection refused")
+
+		// Then
+		s.Equal(http.StatusUnauthorized, res.StatusCode)
+	})
+
+	s.Run("too large", func() {
+		// When
+		res := s.upload("user_1", "server.log", string(bytes.Repeat([]byte("a"), 2048)))
+
+		// Then
+		s.Equal(http.StatusRequestEntityTooLarge, res.StatusCode)
+	})
+
+	s.Run("type not allowed", func() {
+		// When
+		res := s.upload("user_1", "report.pdf", "%PDF-1.7")
+
+		// Then
+		s.Equal(http.StatusUnsupportedMediaType, res.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestDownload() {
+	s.Run("by the uploader", func() {
+		// Given
+		stored, _ := s.store.Put("server.log", "user_1", bytes.NewReader([]byte("connection refused")))
+
+		// When
+		res := s.request(http.MethodGet, attachment.URL(stored.ID), "user_1")
+
+		// Then
+		s.Equal(http.StatusOK, res.StatusCode)
+		s.Equal("text/plain; charset=utf-8", res.Header.Get("Content-Type"))
+		s.Equal("attachment; filename=server.log", res.Header.Get("Content-Disposition"))
+		content, _ := io.ReadAll(res.Body)
+		s.Equal("connection refused", string(content))
+	})
+
+	s.Run("by members of a room it was sent to", func() {
+		// Given
+		ctx := context.Background()
+		stored, _ := s.store.Put("server.log", "user_1", bytes.NewReader([]byte("connection refused")))
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_, _ = s.chatService.CreateRoom(ctx, "test_room", "user_1")
+		_ = s.chatService.AddMember(ctx, "test_room", member1)
+		_ = s.chatService.AddMember(ctx, "test_room", member2)
+		_, err := s.chatService.SendMessage(ctx, "test_room", member1, "logs", stored.ID)
+		s.Require().NoError(err)
+
+		// When
+		res := s.request(http.MethodGet, attachment.URL(stored.ID), "user_2")
+
+		// Then
+		s.Equal(http.StatusOK, res.StatusCode)
+	})
+
+	s.Run("not by others", func() {
+		// Given
+		stored, _ := s.store.Put("server.log", "user_1", bytes.NewReader([]byte("connection refused")))
+
+		// When
+		res := s.request(http.MethodGet, attachment.URL(stored.ID), "user_2")
+
+		// Then
+		s.Equal(http.StatusNotFound, res.StatusCode)
+	})
+
+	s.Run("thumbnail", func() {
+		// Given
+		var content bytes.Buffer
+		_ = png.Encode(&content, image.NewRGBA(image.Rect(0, 0, 16, 8)))
+		stored, _ := s.store.Put("screenshot.png", "user_1", &content)
+
+		// When
+		res := s.request(http.MethodGet, attachment.ThumbnailURL(stored.ID), "user_1")
+
+		// Then
+		s.Equal(http.StatusOK, res.StatusCode)
+		s.Equal("image/png", res.Header.Get("Content-Type"))
+		config, err := png.DecodeConfig(res.Body)
+		s.NoError(err)
+		s.Equal(16, config.Width)
+		s.Equal(8, config.Height)
+	})
+
+	s.Run("no thumbnail", func() {
+		// Given
+		stored, _ := s.store.Put("server.log", "user_1", bytes.NewReader([]byte("connection refused")))
+
+		// When
+		res := s.request(http.MethodGet, attachment.ThumbnailURL(stored.ID), "user_1")
+
+		// Then
+		s.Equal(http.StatusNotFound, res.StatusCode)
+	})
+
+	s.Run("unknown attachment", func() {
+		// When
+		res := s.request(http.MethodGet, attachment.URL("0123456789abcdef01234567"), "user_1")
+
+		// Then
+		s.Equal(http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) upload(username, name, content string) *http.Response {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", name)
+	s.Require().NoError(err)
+	_, _ = part.Write([]byte(content))
+	s.Require().NoError(form.Close())
+
+	req, err := http.NewRequest(http.MethodPost, s.server.URL+"/attachments", &body)
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	return s.do(req, username)
+}
+
+func (s *HandlerSuite) request(method, path, username string) *http.Response {
+	req, err := http.NewRequest(method, s.server.URL+path, nil)
+	s.Require().NoError(err)
+
+	return s.do(req, username)
+}
+
+func (s *HandlerSuite) do(req *http.Request, username string) *http.Response {
+	if username != "" {
+		token, err := s.authenticator.Sign(username, time.Minute)
+		s.Require().NoError(err)
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = res.Body.Close()
+	})
+
+	return res
+}
@@ -0,0 +1,271 @@
+// Package attachment stores the files users upload to send along with their
+// messages, and serves them over HTTP.
+package attachment
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound       = errors.New("attachment not found")
+	ErrTooLarge       = errors.New("attachment too large")
+	ErrTypeNotAllowed = errors.New("attachment type not allowed")
+)
+
+// sniffLength is the number of bytes the type of a file is detected from.
+const sniffLength = 512
+
+var idRegex = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// Attachment describes an uploaded file.
+type Attachment struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	Type string `json:"type"`
+	// Hash is the SHA-256 hash of the content, which it's stored under
+	Hash       string    `json:"hash"`
+	Uploader   string    `json:"uploader"`
+	UploadedAt time.Time `json:"uploaded_at"`
+	// Width and Height are the dimensions of images, which have a Thumbnail
+	// when they're PNG, JPEG or GIF images
+	Width     int        `json:"width,omitempty"`
+	Height    int        `json:"height,omitempty"`
+	Thumbnail *Thumbnail `json:"thumbnail,omitempty"`
+}
+
+// URL returns the path attachments are downloaded from.
+func URL(id string) string {
+	return "/attachments/" + id
+}
+
+// Store keeps attachments in a directory: their description in
+// attachments/<id>.json, and their content in blobs/<hash>, stored once for
+// every attachment with the same content along with the thumbnail of images
+// in blobs/<hash>.thumbnail.
+type Store struct {
+	dir     string
+	maxSize int64
+	// allowedTypes are MIME types, or type/* wildcards. Any type is allowed
+	// when empty.
+	allowedTypes []string
+}
+
+func NewStore(dir string, maxSize int64, allowedTypes []string) (*Store, error) {
+	for _, sub := range []string{"attachments", "blobs"} {
+		err := os.MkdirAll(filepath.Join(dir, sub), 0o750)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create attachment directory: %w", err)
+		}
+	}
+
+	return &Store{dir: dir, maxSize: maxSize, allowedTypes: allowedTypes}, nil
+}
+
+// Put stores the content of the file name uploaded by uploader. Its type is
+// detected from its content, or its extension when the content is binary.
+// Allowed types are checked against the type detected from the content only,
+// for renaming a file not to bypass them.
+func (s *Store) Put(name string, uploader string, content io.Reader) (*Attachment, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	head = head[:n]
+
+	sniffed := http.DetectContentType(head)
+	if !s.allowed(sniffed) {
+		return nil, fmt.Errorf("%w: %s", ErrTypeNotAllowed, sniffed)
+	}
+
+	attachment := &Attachment{
+		Name:       sanitizeName(name),
+		Type:       detectType(name, sniffed),
+		Uploader:   uploader,
+		UploadedAt: time.Now().UTC(),
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	w := io.MultiWriter(tmp, hash)
+
+	_, err = w.Write(head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write attachment: %w", err)
+	}
+
+	// Read one byte past the limit to tell files of the maximum size from
+	// larger ones
+	copied, err := io.Copy(w, io.LimitReader(content, s.maxSize-int64(n)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to write attachment: %w", err)
+	}
+
+	attachment.Size = int64(n) + copied
+	if attachment.Size > s.maxSize {
+		return nil, fmt.Errorf("%w: maximum size is %d bytes", ErrTooLarge, s.maxSize)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write attachment: %w", err)
+	}
+
+	attachment.Hash = hex.EncodeToString(hash.Sum(nil))
+
+	blob := s.blobPath(attachment.Hash)
+	if _, err := os.Stat(blob); errors.Is(err, os.ErrNotExist) {
+		err = os.Rename(tmp.Name(), blob)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store attachment: %w", err)
+		}
+	}
+
+	err = s.thumbnail(attachment)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.ID = newID()
+
+	raw, err := json.Marshal(attachment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode attachment: %w", err)
+	}
+
+	err = writeFile(s.attachmentPath(attachment.ID), raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (s *Store) Get(id string) (*Attachment, error) {
+	if !idRegex.MatchString(id) {
+		return nil, ErrNotFound
+	}
+
+	raw, err := os.ReadFile(s.attachmentPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	var attachment Attachment
+	err = json.Unmarshal(raw, &attachment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode attachment: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// Open returns the attachment id and its content, which the caller must
+// close.
+func (s *Store) Open(id string) (*Attachment, *os.File, error) {
+	attachment, err := s.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(s.blobPath(attachment.Hash))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	return attachment, f, nil
+}
+
+func (s *Store) allowed(contentType string) bool {
+	if len(s.allowedTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range s.allowedTypes {
+		if matched, _ := path.Match(allowed, mediaType); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Store) attachmentPath(id string) string {
+	return filepath.Join(s.dir, "attachments", id+".json")
+}
+
+func (s *Store) blobPath(hash string) string {
+	return filepath.Join(s.dir, "blobs", hash)
+}
+
+// detectType returns the MIME type of a file, detected from the first bytes
+// of its content, falling back to its extension for binary content.
+func detectType(name string, detected string) string {
+	if detected != "application/octet-stream" {
+		return detected
+	}
+
+	if byExtension := mime.TypeByExtension(filepath.Ext(name)); byExtension != "" {
+		return byExtension
+	}
+
+	return detected
+}
+
+func sanitizeName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" {
+		return "attachment"
+	}
+
+	return name
+}
+
+func newID() string {
+	id := make([]byte, 12)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+// writeFile writes data to a temporary file renamed to path, for readers to
+// never see it partially written.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+
+	err := os.WriteFile(tmp, data, 0o640)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,109 @@
+package attachment_test
+
+import (
+	"bytes"
+	"io"
+	"practice-run/attachment"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StoreSuite struct {
+	suite.Suite
+	store *attachment.Store
+}
+
+func TestStore(t *testing.T) {
+	suite.Run(t, new(StoreSuite))
+}
+
+func (s *StoreSuite) SetupSubTest() {
+	store, err := attachment.NewStore(s.T().TempDir(), 1024, []string{"image/*", "text/plain"})
+	s.Require().NoError(err)
+
+	s.store = store
+}
+
+func (s *StoreSuite) TestPut() {
+	s.Run("store the file and its description", func() {
+		// When
+		stored, err := s.store.Put("../logs/server.log", "user_1", strings.NewReader("connection refused"))
+
+		// Then
+		s.NoError(err)
+		s.Equal("server.log", stored.Name)
+		s.Equal(int64(18), stored.Size)
+		s.Equal("text/plain; charset=utf-8", stored.Type)
+		s.Equal("user_1", stored.Uploader)
+		got, f, err := s.store.Open(stored.ID)
+		s.Require().NoError(err)
+		defer f.Close()
+		content, _ := io.ReadAll(f)
+		s.Equal(stored, got)
+		s.Equal("connection refused", string(content))
+	})
+
+	s.Run("share the content of identical files", func() {
+		// When
+		first, err1 := s.store.Put("a.txt", "user_1", strings.NewReader("same"))
+		second, err2 := s.store.Put("b.txt", "user_2", strings.NewReader("same"))
+
+		// Then
+		s.NoError(err1)
+		s.NoError(err2)
+		s.NotEqual(first.ID, second.ID)
+		s.Equal(first.Hash, second.Hash)
+	})
+
+	s.Run("reject files too large", func() {
+		// When
+		_, err := s.store.Put("big.txt", "user_1", bytes.NewReader(bytes.Repeat([]byte("a"), 1025)))
+
+		// Then
+		s.ErrorIs(err, attachment.ErrTooLarge)
+	})
+
+	s.Run("accept files of the maximum size", func() {
+		// When
+		_, err := s.store.Put("big.txt", "user_1", bytes.NewReader(bytes.Repeat([]byte("a"), 1024)))
+
+		// Then
+		s.NoError(err)
+	})
+
+	s.Run("reject types not allowed", func() {
+		// When
+		_, err := s.store.Put("report.pdf", "user_1", strings.NewReader("%PDF-1.7"))
+
+		// Then
+		s.ErrorIs(err, attachment.ErrTypeNotAllowed)
+	})
+
+	s.Run("reject binary files renamed to an allowed type", func() {
+		// When
+		_, err := s.store.Put("payload.png", "user_1", bytes.NewReader([]byte("\x7fELF\x02\x01\x01\x00")))
+
+		// Then
+		s.ErrorIs(err, attachment.ErrTypeNotAllowed)
+	})
+}
+
+func (s *StoreSuite) TestGet() {
+	s.Run("unknown id", func() {
+		// When
+		_, err := s.store.Get("0123456789abcdef01234567")
+
+		// Then
+		s.ErrorIs(err, attachment.ErrNotFound)
+	})
+
+	s.Run("invalid id", func() {
+		// When
+		_, err := s.store.Get("../../accounts")
+
+		// Then
+		s.ErrorIs(err, attachment.ErrNotFound)
+	})
+}
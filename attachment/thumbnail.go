@@ -0,0 +1,194 @@
+package attachment
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+)
+
+const (
+	// thumbnailSize bounds the width and height of thumbnails.
+	thumbnailSize = 256
+	// maxImagePixels bounds the size of the images thumbnails are generated
+	// for, which are decoded in memory.
+	maxImagePixels = 50_000_000
+)
+
+// Thumbnail is a preview of an image attachment.
+type Thumbnail struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Type   string `json:"type"`
+}
+
+// ThumbnailURL returns the path thumbnails are downloaded from.
+func ThumbnailURL(id string) string {
+	return URL(id) + "/thumbnail"
+}
+
+// thumbnailTypes are the image types thumbnails are generated for, and the
+// type of their thumbnails.
+var thumbnailTypes = map[string]string{
+	"image/png":  "image/png",
+	"image/gif":  "image/png",
+	"image/jpeg": "image/jpeg",
+}
+
+// OpenThumbnail returns the attachment id and the content of its thumbnail,
+// which the caller must close.
+func (s *Store) OpenThumbnail(id string) (*Attachment, *os.File, error) {
+	attachment, err := s.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment.Thumbnail == nil {
+		return nil, nil, ErrNotFound
+	}
+
+	f, err := os.Open(s.thumbnailPath(attachment.Hash))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open thumbnail: %w", err)
+	}
+
+	return attachment, f, nil
+}
+
+// thumbnail records the dimensions of an image attachment, and generates its
+// thumbnail unless one has been generated for the same content. Images which
+// can't be decoded are stored without thumbnail.
+func (s *Store) thumbnail(attachment *Attachment) error {
+	thumbnailType, ok := thumbnailTypes[attachment.Type]
+	if !ok {
+		return nil
+	}
+
+	f, err := os.Open(s.blobPath(attachment.Hash))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return nil
+	}
+
+	attachment.Width, attachment.Height = config.Width, config.Height
+
+	if config.Width*config.Height > maxImagePixels {
+		return nil
+	}
+
+	path := s.thumbnailPath(attachment.Hash)
+
+	if existing, err := os.Open(path); err == nil {
+		defer existing.Close()
+
+		config, _, err := image.DecodeConfig(existing)
+		if err != nil {
+			return fmt.Errorf("failed to read thumbnail: %w", err)
+		}
+
+		attachment.Thumbnail = &Thumbnail{Width: config.Width, Height: config.Height, Type: thumbnailType}
+
+		return nil
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil
+	}
+
+	thumbnail := scale(img, thumbnailSize)
+
+	tmp, err := os.CreateTemp(s.dir, "thumbnail-*")
+	if err != nil {
+		return fmt.Errorf("failed to create thumbnail: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if thumbnailType == "image/jpeg" {
+		err = jpeg.Encode(tmp, thumbnail, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(tmp, thumbnail)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write thumbnail: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("failed to store thumbnail: %w", err)
+	}
+
+	bounds := thumbnail.Bounds()
+	attachment.Thumbnail = &Thumbnail{Width: bounds.Dx(), Height: bounds.Dy(), Type: thumbnailType}
+
+	return nil
+}
+
+func (s *Store) thumbnailPath(hash string) string {
+	return s.blobPath(hash) + ".thumbnail"
+}
+
+// scale shrinks img to fit in a size x size square, keeping its aspect ratio,
+// each pixel being the average of the pixels it covers. Smaller images are
+// kept as they are.
+func scale(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= size && height <= size {
+		return img
+	}
+
+	dstWidth, dstHeight := size, max(1, height*size/width)
+	if height > width {
+		dstWidth, dstHeight = max(1, width*size/height), size
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		y0, y1 := bounds.Min.Y+y*height/dstHeight, bounds.Min.Y+(y+1)*height/dstHeight
+
+		for x := 0; x < dstWidth; x++ {
+			x0, x1 := bounds.Min.X+x*width/dstWidth, bounds.Min.X+(x+1)*width/dstWidth
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					sr, sg, sb, sa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(sr), g+uint64(sg), b+uint64(sb), a+uint64(sa)
+					n++
+				}
+			}
+
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
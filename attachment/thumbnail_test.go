@@ -0,0 +1,131 @@
+package attachment_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"practice-run/attachment"
+	"strings"
+)
+
+func (s *StoreSuite) TestThumbnail() {
+	s.Run("png", func() {
+		// Given
+		var content bytes.Buffer
+		_ = png.Encode(&content, filled(1024, 512))
+
+		// When
+		stored, err := s.putImage("screenshot.png", content.Bytes())
+
+		// Then
+		s.NoError(err)
+		s.Equal("image/png", stored.Type)
+		s.Equal(1024, stored.Width)
+		s.Equal(512, stored.Height)
+		s.Require().NotNil(stored.Thumbnail)
+		s.Equal(256, stored.Thumbnail.Width)
+		s.Equal(128, stored.Thumbnail.Height)
+		s.Equal("image/png", stored.Thumbnail.Type)
+		_, f, err := s.store.OpenThumbnail(stored.ID)
+		s.Require().NoError(err)
+		defer f.Close()
+		thumbnail, format, err := image.Decode(f)
+		s.NoError(err)
+		s.Equal("png", format)
+		s.Equal(image.Rect(0, 0, 256, 128), thumbnail.Bounds())
+		r, g, b, _ := thumbnail.At(10, 10).RGBA()
+		s.Equal([]uint32{0xffff, 0, 0}, []uint32{r, g, b})
+	})
+
+	s.Run("jpeg", func() {
+		// Given
+		var content bytes.Buffer
+		_ = jpeg.Encode(&content, filled(300, 600), nil)
+
+		// When
+		stored, err := s.putImage("photo.jpg", content.Bytes())
+
+		// Then
+		s.NoError(err)
+		s.Require().NotNil(stored.Thumbnail)
+		s.Equal(128, stored.Thumbnail.Width)
+		s.Equal(256, stored.Thumbnail.Height)
+		s.Equal("image/jpeg", stored.Thumbnail.Type)
+	})
+
+	s.Run("gif", func() {
+		// Given
+		var content bytes.Buffer
+		_ = gif.Encode(&content, filled(64, 32), nil)
+
+		// When
+		stored, err := s.putImage("animation.gif", content.Bytes())
+
+		// Then
+		s.NoError(err)
+		s.Require().NotNil(stored.Thumbnail)
+		s.Equal(64, stored.Thumbnail.Width)
+		s.Equal(32, stored.Thumbnail.Height)
+		s.Equal("image/png", stored.Thumbnail.Type)
+	})
+
+	s.Run("reuse the thumbnail of identical images", func() {
+		// Given
+		var content bytes.Buffer
+		_ = png.Encode(&content, filled(512, 512))
+		first, _ := s.putImage("a.png", content.Bytes())
+
+		// When
+		second, err := s.store.Put("b.png", "user_2", bytes.NewReader(content.Bytes()))
+
+		// Then
+		s.NoError(err)
+		s.Equal(first.Thumbnail, second.Thumbnail)
+	})
+
+	s.Run("no thumbnail of invalid images", func() {
+		// When
+		stored, err := s.store.Put("broken.png", "user_1", strings.NewReader("\x89PNG\r\n\x1a\nbroken"))
+
+		// Then
+		s.NoError(err)
+		s.Nil(stored.Thumbnail)
+		_, _, err = s.store.OpenThumbnail(stored.ID)
+		s.Error(err)
+	})
+
+	s.Run("no thumbnail of other files", func() {
+		// When
+		stored, err := s.store.Put("notes.txt", "user_1", strings.NewReader("notes"))
+
+		// Then
+		s.NoError(err)
+		s.Nil(stored.Thumbnail)
+		s.Zero(stored.Width)
+	})
+}
+
+// putImage stores the image content, in a store accepting images larger than
+// the default one.
+func (s *StoreSuite) putImage(name string, content []byte) (*attachment.Attachment, error) {
+	store, err := attachment.NewStore(s.T().TempDir(), 10*1024*1024, []string{"image/*"})
+	s.Require().NoError(err)
+
+	s.store = store
+
+	return store.Put(name, "user_1", bytes.NewReader(content))
+}
+
+func filled(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+		}
+	}
+
+	return img
+}
@@ -0,0 +1,36 @@
+package auth
+
+import "slices"
+
+// GroupResolver returns the groups a user belongs to.
+type GroupResolver interface {
+	Groups(username string) []string
+}
+
+// Admins decides which users are administrators: those listed by username
+// and the members of the listed groups.
+type Admins struct {
+	users    []string
+	groups   []string
+	resolver GroupResolver
+}
+
+// NewAdmins returns the administrators among users and the members of groups,
+// as resolved by resolver, which may be nil if groups is empty.
+func NewAdmins(users, groups []string, resolver GroupResolver) *Admins {
+	return &Admins{users: users, groups: groups, resolver: resolver}
+}
+
+func (a *Admins) IsAdmin(username string) bool {
+	if slices.Contains(a.users, username) {
+		return true
+	}
+
+	if a.resolver == nil || len(a.groups) == 0 {
+		return false
+	}
+
+	return slices.ContainsFunc(a.resolver.Groups(username), func(group string) bool {
+		return slices.Contains(a.groups, group)
+	})
+}
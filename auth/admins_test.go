@@ -0,0 +1,30 @@
+package auth_test
+
+import "practice-run/auth"
+
+type mockGroups map[string][]string
+
+func (g mockGroups) Groups(username string) []string {
+	return g[username]
+}
+
+func (s *Suite) TestAdmins() {
+	admins := auth.NewAdmins([]string{"alice"}, []string{"ops"}, mockGroups{"bob": {"dev", "ops"}, "carol": {"dev"}})
+
+	s.Run("listed user", func() {
+		s.True(admins.IsAdmin("alice"))
+	})
+
+	s.Run("member of a listed group", func() {
+		s.True(admins.IsAdmin("bob"))
+	})
+
+	s.Run("other users", func() {
+		s.False(admins.IsAdmin("carol"))
+		s.False(admins.IsAdmin("dave"))
+	})
+
+	s.Run("without group resolver", func() {
+		s.False(auth.NewAdmins(nil, []string{"ops"}, nil).IsAdmin("bob"))
+	})
+}
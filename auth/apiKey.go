@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// APIKeyAuthenticator accepts static API keys, each bound to a username.
+type APIKeyAuthenticator struct {
+	users map[[sha256.Size]byte]string // keyed by the key's hash, so lookups don't leak key prefixes through timing
+}
+
+// LoadAPIKeys reads an API key file. Each non-empty line holds a key and the
+// username it authenticates, separated by whitespace; lines starting with #
+// are ignored.
+func LoadAPIKeys(path string) (*APIKeyAuthenticator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open api key file: %w", err)
+	}
+	defer f.Close()
+
+	users := make(map[[sha256.Size]byte]string)
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid api key file: line %d: expected <key> <username>", line)
+		}
+
+		users[sha256.Sum256([]byte(fields[0]))] = fields[1]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read api key file: %w", err)
+	}
+
+	return &APIKeyAuthenticator{users: users}, nil
+}
+
+// Usernames returns the usernames the keys authenticate.
+func (a *APIKeyAuthenticator) Usernames() []string {
+	usernames := make([]string, 0, len(a.users))
+	for _, username := range a.users {
+		usernames = append(usernames, username)
+	}
+
+	return usernames
+}
+
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	username, ok := a.users[sha256.Sum256([]byte(token))]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown api key", ErrInvalidCredentials)
+	}
+
+	return username, nil
+}
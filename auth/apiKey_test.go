@@ -0,0 +1,57 @@
+package auth_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"practice-run/auth"
+)
+
+func (s *Suite) TestAPIKeyAuthenticator() {
+	s.Run("ok", func() {
+		// Given
+		authenticator, err := auth.LoadAPIKeys(s.writeFile("# release automation\nkey_1 release_bot\n\nkey_2 user_2\n"))
+		s.Require().NoError(err)
+
+		// When
+		username, err := authenticator.Authenticate(context.Background(), "key_2")
+
+		// Then
+		s.NoError(err)
+		s.Equal("user_2", username)
+	})
+
+	s.Run("unknown key", func() {
+		// Given
+		authenticator, err := auth.LoadAPIKeys(s.writeFile("key_1 release_bot\n"))
+		s.Require().NoError(err)
+
+		// When
+		_, err = authenticator.Authenticate(context.Background(), "key_2")
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+	})
+
+	s.Run("invalid file", func() {
+		// When
+		_, err := auth.LoadAPIKeys(s.writeFile("key_1\n"))
+
+		// Then
+		s.Error(err)
+	})
+
+	s.Run("missing file", func() {
+		// When
+		_, err := auth.LoadAPIKeys(filepath.Join(s.T().TempDir(), "missing"))
+
+		// Then
+		s.Error(err)
+	})
+}
+
+func (s *Suite) writeFile(content string) string {
+	path := filepath.Join(s.T().TempDir(), "api_keys")
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// BearerSubprotocol is the WebSocket subprotocol used by browser clients,
+// which can't set headers on upgrade requests, to pass a bearer token:
+// they offer "bearer" followed by the token itself as a second subprotocol.
+const BearerSubprotocol = "bearer"
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+type Authenticator interface {
+	// Authenticate returns the username the token belongs to.
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+// Chain accepts a token if any of its authenticators does.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(ctx context.Context, token string) (string, error) {
+	for _, authenticator := range c {
+		username, err := authenticator.Authenticate(ctx, token)
+		if err == nil {
+			return username, nil
+		}
+	}
+
+	return "", ErrInvalidCredentials
+}
+
+// TokenFromRequest extracts a bearer token from the Authorization header or,
+// failing that, from the Sec-WebSocket-Protocol header. The returned
+// subprotocol must be echoed back to the client when it is not empty.
+func TokenFromRequest(r *http.Request) (token string, subprotocol string, err error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		token, err = ParseBearer(header)
+		return token, "", err
+	}
+
+	protocols := websocketSubprotocols(r)
+	for i, protocol := range protocols {
+		if protocol == BearerSubprotocol && i+1 < len(protocols) {
+			return protocols[i+1], BearerSubprotocol, nil
+		}
+	}
+
+	return "", "", ErrMissingCredentials
+}
+
+// ParseBearer extracts the token from an Authorization header value.
+func ParseBearer(header string) (string, error) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", fmt.Errorf("%w: expected a bearer token", ErrInvalidCredentials)
+	}
+
+	return strings.TrimSpace(token), nil
+}
+
+func websocketSubprotocols(r *http.Request) []string {
+	var protocols []string
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			protocols = append(protocols, strings.TrimSpace(protocol))
+		}
+	}
+
+	return protocols
+}
@@ -0,0 +1,99 @@
+package auth_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"practice-run/auth"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type Suite struct {
+	suite.Suite
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) TestTokenFromRequest() {
+	s.Run("authorization header", func() {
+		// Given
+		r := httptest.NewRequest("GET", "/ws", nil)
+		r.Header.Set("Authorization", "Bearer abc")
+
+		// When
+		token, subprotocol, err := auth.TokenFromRequest(r)
+
+		// Then
+		s.NoError(err)
+		s.Equal("abc", token)
+		s.Empty(subprotocol)
+	})
+
+	s.Run("websocket subprotocol", func() {
+		// Given
+		r := httptest.NewRequest("GET", "/ws", nil)
+		r.Header.Set("Sec-WebSocket-Protocol", "bearer, abc")
+
+		// When
+		token, subprotocol, err := auth.TokenFromRequest(r)
+
+		// Then
+		s.NoError(err)
+		s.Equal("abc", token)
+		s.Equal(auth.BearerSubprotocol, subprotocol)
+	})
+
+	s.Run("unsupported scheme", func() {
+		// Given
+		r := httptest.NewRequest("GET", "/ws", nil)
+		r.Header.Set("Authorization", "Basic abc")
+
+		// When
+		_, _, err := auth.TokenFromRequest(r)
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+	})
+
+	s.Run("missing credentials", func() {
+		// Given
+		r := httptest.NewRequest("GET", "/ws?username=user_1", nil)
+
+		// When
+		_, _, err := auth.TokenFromRequest(r)
+
+		// Then
+		s.ErrorIs(err, auth.ErrMissingCredentials)
+	})
+}
+
+func (s *Suite) TestChain() {
+	s.Run("accept if any authenticator accepts", func() {
+		// Given
+		first := auth.NewJWTAuthenticator([]byte("first"))
+		second := auth.NewJWTAuthenticator([]byte("second"))
+		token, _ := second.Sign("user_1", time.Minute)
+
+		// When
+		username, err := auth.Chain{first, second}.Authenticate(context.Background(), token)
+
+		// Then
+		s.NoError(err)
+		s.Equal("user_1", username)
+	})
+
+	s.Run("reject if no authenticator accepts", func() {
+		// Given
+		first := auth.NewJWTAuthenticator([]byte("first"))
+
+		// When
+		_, err := auth.Chain{first}.Authenticate(context.Background(), "invalid")
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+	})
+}
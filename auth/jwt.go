@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JWTAuthenticator accepts HS256-signed JSON Web Tokens whose subject is the
+// username. Tokens must carry an expiry.
+type JWTAuthenticator struct {
+	secret []byte
+}
+
+func NewJWTAuthenticator(secret []byte) *JWTAuthenticator {
+	return &JWTAuthenticator{secret: secret}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	NotBefore int64  `json:"nbf,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("%w: malformed token", ErrInvalidCredentials)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", err
+	}
+
+	if header.Alg != "HS256" {
+		return "", fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidCredentials, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed signature", ErrInvalidCredentials)
+	}
+
+	if !hmac.Equal(signature, a.sign(parts[0]+"."+parts[1])) {
+		return "", fmt.Errorf("%w: bad signature", ErrInvalidCredentials)
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", err
+	}
+
+	now := time.Now().Unix()
+
+	switch {
+	case claims.Subject == "":
+		return "", fmt.Errorf("%w: missing subject", ErrInvalidCredentials)
+	case claims.ExpiresAt == 0:
+		return "", fmt.Errorf("%w: missing expiry", ErrInvalidCredentials)
+	case now >= claims.ExpiresAt:
+		return "", fmt.Errorf("%w: token expired", ErrInvalidCredentials)
+	case now < claims.NotBefore:
+		return "", fmt.Errorf("%w: token not valid yet", ErrInvalidCredentials)
+	}
+
+	return claims.Subject, nil
+}
+
+// Sign issues a token for username, valid for ttl.
+func (a *JWTAuthenticator) Sign(username string, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	header, err := encodeSegment(jwtHeader{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := encodeSegment(jwtClaims{
+		Subject:   username,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + claims
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(a.sign(unsigned)), nil
+}
+
+func (a *JWTAuthenticator) sign(unsigned string) []byte {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
+
+func encodeSegment(v any) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed token", ErrInvalidCredentials)
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("%w: malformed token", ErrInvalidCredentials)
+	}
+
+	return nil
+}
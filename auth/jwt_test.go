@@ -0,0 +1,73 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"practice-run/auth"
+	"strings"
+	"time"
+)
+
+func (s *Suite) TestJWTAuthenticator() {
+	s.Run("ok", func() {
+		// Given
+		authenticator := auth.NewJWTAuthenticator([]byte("secret"))
+		token, _ := authenticator.Sign("user_1", time.Minute)
+
+		// When
+		username, err := authenticator.Authenticate(context.Background(), token)
+
+		// Then
+		s.NoError(err)
+		s.Equal("user_1", username)
+	})
+
+	s.Run("bad signature", func() {
+		// Given
+		authenticator := auth.NewJWTAuthenticator([]byte("secret"))
+		token, _ := auth.NewJWTAuthenticator([]byte("other")).Sign("user_1", time.Minute)
+
+		// When
+		_, err := authenticator.Authenticate(context.Background(), token)
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+	})
+
+	s.Run("expired", func() {
+		// Given
+		authenticator := auth.NewJWTAuthenticator([]byte("secret"))
+		token, _ := authenticator.Sign("user_1", -time.Minute)
+
+		// When
+		_, err := authenticator.Authenticate(context.Background(), token)
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+	})
+
+	s.Run("unsigned", func() {
+		// Given
+		authenticator := auth.NewJWTAuthenticator([]byte("secret"))
+		token, _ := authenticator.Sign("user_1", time.Minute)
+		parts := strings.Split(token, ".")
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+
+		// When
+		_, err := authenticator.Authenticate(context.Background(), header+"."+parts[1]+".")
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+	})
+
+	s.Run("malformed", func() {
+		// Given
+		authenticator := auth.NewJWTAuthenticator([]byte("secret"))
+
+		// When
+		_, err := authenticator.Authenticate(context.Background(), "not-a-token")
+
+		// Then
+		s.ErrorIs(err, auth.ErrInvalidCredentials)
+	})
+}
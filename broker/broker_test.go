@@ -0,0 +1,129 @@
+package broker_test
+
+import (
+	"context"
+	"practice-run/broker"
+	"practice-run/chat"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite runs the same tests against every broker implementation.
+type Suite struct {
+	suite.Suite
+	newBroker func() chat.Broker
+	broker    chat.Broker
+}
+
+func TestInProcess(t *testing.T) {
+	suite.Run(t, &Suite{newBroker: func() chat.Broker {
+		return broker.NewInProcess()
+	}})
+}
+
+func TestRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	suite.Run(t, &Suite{newBroker: func() chat.Broker {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() {
+			_ = client.Close()
+		})
+
+		return broker.NewRedis(client)
+	}})
+}
+
+func (s *Suite) SetupSubTest() {
+	s.broker = s.newBroker()
+}
+
+func (s *Suite) TestPublish() {
+	s.Run("deliver in order to every subscriber", func() {
+		// Given
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		subscriber1 := &recorder{}
+		subscriber2 := &recorder{}
+		s.Require().NoError(s.broker.Subscribe(ctx, "topic", subscriber1.handle))
+		s.Require().NoError(s.broker.Subscribe(ctx, "topic", subscriber2.handle))
+
+		// When
+		for _, payload := range []string{"1", "2", "3"} {
+			s.Require().NoError(s.broker.Publish(ctx, "topic", []byte(payload)))
+		}
+
+		// Then
+		for _, subscriber := range []*recorder{subscriber1, subscriber2} {
+			s.Eventually(func() bool {
+				return len(subscriber.received()) == 3
+			}, time.Second, 10*time.Millisecond)
+			s.Equal([]string{"1", "2", "3"}, subscriber.received())
+		}
+	})
+
+	s.Run("only deliver to the subscribers of the topic", func() {
+		// Given
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		subscriber1 := &recorder{}
+		subscriber2 := &recorder{}
+		s.Require().NoError(s.broker.Subscribe(ctx, "topic_1", subscriber1.handle))
+		s.Require().NoError(s.broker.Subscribe(ctx, "topic_2", subscriber2.handle))
+
+		// When
+		s.Require().NoError(s.broker.Publish(ctx, "topic_1", []byte("1")))
+		s.Require().NoError(s.broker.Publish(ctx, "topic_2", []byte("2")))
+
+		// Then
+		s.Eventually(func() bool {
+			return len(subscriber1.received()) == 1 && len(subscriber2.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+		s.Equal([]string{"1"}, subscriber1.received())
+		s.Equal([]string{"2"}, subscriber2.received())
+	})
+
+	s.Run("stop delivering once unsubscribed", func() {
+		// Given
+		ctx, cancel := context.WithCancel(context.Background())
+		subscriber := &recorder{}
+		s.Require().NoError(s.broker.Subscribe(ctx, "topic", subscriber.handle))
+		s.Require().NoError(s.broker.Publish(context.Background(), "topic", []byte("1")))
+		s.Eventually(func() bool {
+			return len(subscriber.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		// When
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+		_ = s.broker.Publish(context.Background(), "topic", []byte("2"))
+
+		// Then
+		time.Sleep(50 * time.Millisecond)
+		s.Equal([]string{"1"}, subscriber.received())
+	})
+}
+
+type recorder struct {
+	mu       sync.Mutex
+	payloads []string
+}
+
+func (r *recorder) handle(payload []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.payloads = append(r.payloads, string(payload))
+}
+
+func (r *recorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.payloads...)
+}
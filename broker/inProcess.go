@@ -0,0 +1,108 @@
+// Package broker implements the publish/subscribe brokers carrying messages
+// between the nodes of a cluster.
+package broker
+
+import (
+	"context"
+	"sync"
+)
+
+// InProcess is a broker whose subscribers live in the same process, used in
+// single-node mode. Each subscriber receives messages in publication order,
+// from its own goroutine, so that publishers never wait on subscribers.
+type InProcess struct {
+	mu            sync.Mutex
+	subscriptions map[string][]*subscription
+}
+
+func NewInProcess() *InProcess {
+	return &InProcess{subscriptions: make(map[string][]*subscription)}
+}
+
+func (b *InProcess) Publish(ctx context.Context, topic string, payload []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subscription := range b.subscriptions[topic] {
+		subscription.push(payload)
+	}
+
+	return nil
+}
+
+func (b *InProcess) Subscribe(ctx context.Context, topic string, handle func(payload []byte)) error {
+	s := &subscription{handle: handle}
+	s.cond = sync.NewCond(&s.mu)
+
+	b.mu.Lock()
+	b.subscriptions[topic] = append(b.subscriptions[topic], s)
+	b.mu.Unlock()
+
+	go s.run()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		subscriptions := b.subscriptions[topic]
+		for i := range subscriptions {
+			if subscriptions[i] == s {
+				b.subscriptions[topic] = append(subscriptions[:i:i], subscriptions[i+1:]...)
+				break
+			}
+		}
+
+		s.close()
+	}()
+
+	return nil
+}
+
+// subscription queues the messages of a subscriber until its goroutine
+// handles them.
+type subscription struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  [][]byte
+	closed bool
+
+	handle func(payload []byte)
+}
+
+func (s *subscription) push(payload []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queue = append(s.queue, payload)
+	s.cond.Signal()
+}
+
+func (s *subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	s.cond.Signal()
+}
+
+func (s *subscription) run() {
+	for {
+		s.mu.Lock()
+		for len(s.queue) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+
+		if s.closed {
+			s.mu.Unlock()
+			return
+		}
+
+		payload := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		s.handle(payload)
+	}
+}
@@ -0,0 +1,60 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a broker backed by Redis pub/sub, through which the nodes of a
+// cluster exchange messages. Messages published while a node is disconnected
+// from Redis are lost to it.
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (b *Redis) Publish(ctx context.Context, topic string, payload []byte) error {
+	err := b.client.Publish(ctx, topic, payload).Err()
+	if err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topic, err)
+	}
+
+	return nil
+}
+
+// Subscribe returns once the subscription is active, so that no message
+// published afterwards is missed.
+func (b *Redis) Subscribe(ctx context.Context, topic string, handle func(payload []byte)) error {
+	pubSub := b.client.Subscribe(ctx, topic)
+
+	_, err := pubSub.Receive(ctx)
+	if err != nil {
+		_ = pubSub.Close()
+		return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+
+	messages := pubSub.Channel()
+
+	go func() {
+		defer pubSub.Close()
+
+		for {
+			select {
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				handle([]byte(message.Payload))
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
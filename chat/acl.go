@@ -0,0 +1,145 @@
+package chat
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type Permission string
+
+const (
+	PermissionJoin     Permission = "join"
+	PermissionPost     Permission = "post"
+	PermissionInvite   Permission = "invite"
+	PermissionManage   Permission = "manage"   // view and edit the ACL
+	PermissionModerate Permission = "moderate" // delete the messages of others
+)
+
+var permissions = []Permission{PermissionJoin, PermissionPost, PermissionInvite, PermissionManage, PermissionModerate}
+
+type Effect string
+
+const (
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+// Everyone is the subject matching every user.
+const Everyone = "*"
+
+var subjectRegex = regexp.MustCompile(`^(\*|@\w+|%\w+)$`)
+
+// Rule allows or denies a permission to a subject, which is either a user
+// (@name), a group (%name) or Everyone.
+type Rule struct {
+	Effect     Effect     `json:"effect"`
+	Permission Permission `json:"permission"`
+	Subject    string     `json:"subject"`
+}
+
+// ParseRule parses rules formatted as "<allow|deny> <permission> <subject>".
+func ParseRule(s string) (Rule, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 3 {
+		return Rule{}, fmt.Errorf("%w: expected <allow|deny> <permission> <subject>", ErrInvalidRule)
+	}
+
+	rule := Rule{Effect: Effect(fields[0]), Permission: Permission(fields[1]), Subject: fields[2]}
+
+	if rule.Effect != EffectAllow && rule.Effect != EffectDeny {
+		return Rule{}, fmt.Errorf("%w: unknown effect %s", ErrInvalidRule, fields[0])
+	}
+
+	if !slices.Contains(permissions, rule.Permission) {
+		return Rule{}, fmt.Errorf("%w: unknown permission %s", ErrInvalidRule, fields[1])
+	}
+
+	if !subjectRegex.MatchString(rule.Subject) {
+		return Rule{}, fmt.Errorf("%w: subject must be @user, %%group or *", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%s %s %s", r.Effect, r.Permission, r.Subject)
+}
+
+// specificity ranks subjects so that user rules override group rules, which
+// override rules for everyone.
+func (r Rule) specificity(username string, groups []string) int {
+	switch {
+	case r.Subject == "@"+username:
+		return 3
+	case strings.HasPrefix(r.Subject, "%") && slices.Contains(groups, r.Subject[1:]):
+		return 2
+	case r.Subject == Everyone:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ACL holds the rules of a room. Among the rules matching a user, the most
+// specific ones apply, and deny wins over allow at equal specificity. Without
+// a matching rule, everything but managing the ACL and moderating is allowed.
+type ACL struct {
+	rules []Rule
+}
+
+func (a *ACL) Rules() []Rule {
+	return slices.Clone(a.rules)
+}
+
+func (a *ACL) Allows(username string, groups []string, permission Permission) bool {
+	allowed, _ := a.decide(username, groups, permission)
+
+	return allowed
+}
+
+// decide returns whether permission is allowed to username, and the
+// specificity of the rules deciding it, 0 if none matches.
+func (a *ACL) decide(username string, groups []string, permission Permission) (bool, int) {
+	best := 0
+	allowed := permission != PermissionManage && permission != PermissionModerate
+
+	for _, rule := range a.rules {
+		if rule.Permission != permission {
+			continue
+		}
+
+		specificity := rule.specificity(username, groups)
+		if specificity == 0 || specificity < best {
+			continue
+		}
+
+		if specificity > best {
+			best = specificity
+			allowed = rule.Effect == EffectAllow
+			continue
+		}
+
+		allowed = allowed && rule.Effect == EffectAllow
+	}
+
+	return allowed, best
+}
+
+func (a *ACL) add(rule Rule) {
+	if !slices.Contains(a.rules, rule) {
+		a.rules = append(a.rules, rule)
+	}
+}
+
+func (a *ACL) remove(rule Rule) bool {
+	i := slices.Index(a.rules, rule)
+	if i == -1 {
+		return false
+	}
+
+	a.rules = slices.Delete(a.rules, i, i+1)
+
+	return true
+}
@@ -0,0 +1,144 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+)
+
+type MockGroups map[string][]string
+
+func (g MockGroups) Groups(username string) []string {
+	return g[username]
+}
+
+func (s *Suite) TestParseRule() {
+	s.Run("ok", func() {
+		// When
+		rule, err := chat.ParseRule("deny post %interns")
+
+		// Then
+		s.NoError(err)
+		s.Equal(chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionPost, Subject: "%interns"}, rule)
+	})
+
+	s.Run("invalid rules", func() {
+		for _, raw := range []string{"deny post", "block post *", "deny shout *", "deny post user_1"} {
+			// When
+			_, err := chat.ParseRule(raw)
+
+			// Then
+			s.ErrorIs(err, chat.ErrInvalidRule, raw)
+		}
+	})
+}
+
+func (s *Suite) TestACL() {
+	s.Run("read-only room", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "releases"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		owner := &MockMember{username: "owner"}
+		bot := &MockMember{username: "release_bot"}
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, roomName, bot)
+		_ = s.svc.AddMember(ctx, roomName, member)
+
+		// When
+		s.Require().NoError(s.svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionPost, Subject: chat.Everyone}))
+		s.Require().NoError(s.svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectAllow, Permission: chat.PermissionPost, Subject: "@release_bot"}))
+
+		// Then
+		_, err := s.svc.SendMessage(ctx, roomName, bot, "v1.2.3 released")
+		s.NoError(err)
+		_, err = s.svc.SendMessage(ctx, roomName, member, "hello")
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+	})
+
+	s.Run("user rules override group rules", func() {
+		// Given
+		ctx := context.Background()
+		svc := chat.NewService(chat.WithGroupResolver(MockGroups{"user_1": {"interns"}, "user_2": {"interns"}}))
+		roomName := "test_room"
+		_, _ = svc.CreateRoom(ctx, roomName, "owner")
+		owner := &MockMember{username: "owner"}
+		_ = svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionJoin, Subject: "%interns"})
+		_ = svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectAllow, Permission: chat.PermissionJoin, Subject: "@user_2"})
+
+		// When
+		err1 := svc.AddMember(ctx, roomName, &MockMember{username: "user_1"})
+		err2 := svc.AddMember(ctx, roomName, &MockMember{username: "user_2"})
+		err3 := svc.AddMember(ctx, roomName, &MockMember{username: "user_3"})
+
+		// Then
+		s.ErrorIs(err1, chat.ErrPermissionDenied)
+		s.NoError(err2)
+		s.NoError(err3)
+	})
+
+	s.Run("deny wins at equal specificity", func() {
+		// Given
+		ctx := context.Background()
+		svc := chat.NewService(chat.WithGroupResolver(MockGroups{"user_1": {"staff", "contractors"}}))
+		roomName := "test_room"
+		_, _ = svc.CreateRoom(ctx, roomName, "owner")
+		owner := &MockMember{username: "owner"}
+		_ = svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectAllow, Permission: chat.PermissionJoin, Subject: "%staff"})
+		_ = svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionJoin, Subject: "%contractors"})
+
+		// When
+		err := svc.AddMember(ctx, roomName, &MockMember{username: "user_1"})
+
+		// Then
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+	})
+
+	s.Run("only managers edit the acl", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		member := &MockMember{username: "user_1"}
+		rule := chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionPost, Subject: chat.Everyone}
+
+		// When
+		err := s.svc.AddACLRule(ctx, roomName, member, rule)
+
+		// Then
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+	})
+
+	s.Run("view and remove rules", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		owner := &MockMember{username: "owner"}
+		rule := chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionPost, Subject: chat.Everyone}
+		_ = s.svc.AddACLRule(ctx, roomName, owner, rule)
+
+		// When
+		err := s.svc.RemoveACLRule(ctx, roomName, owner, rule)
+
+		// Then
+		s.NoError(err)
+		rules, err := s.svc.GetACL(ctx, roomName, owner)
+		s.NoError(err)
+		s.Equal([]chat.Rule{{Effect: chat.EffectAllow, Permission: chat.PermissionManage, Subject: "@owner"}}, rules)
+		s.ErrorIs(s.svc.RemoveACLRule(ctx, roomName, owner, rule), chat.ErrRuleNotFound)
+	})
+
+	s.Run("non members can't view the acl", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+
+		// When
+		rules, err := s.svc.GetACL(ctx, roomName, &MockMember{username: "user_1"})
+
+		// Then
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+		s.Nil(rules)
+	})
+}
@@ -5,16 +5,29 @@ import (
 	"fmt"
 )
 
-func (r *Service) AddMember(ctx context.Context, roomName string, member Member) error {
+func (r *Service) AddMember(ctx context.Context, roomName string, member Member) (err error) {
+	ctx, span := startSpan(ctx, "AddMember", roomAttribute(roomName), usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		_, err = r.forward(ctx, node, peerCall{Method: peerAddMember, Room: roomName, Username: member.Username()})
+		if err == nil {
+			r.joinedRemote(member.Username(), roomName, true)
+		}
+		return err
+	}
+
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
-	room, ok := r.rooms[roomName]
-	if !ok {
-		return fmt.Errorf("room not found")
+	room, err := r.room(roomName)
+	if err != nil {
+		return err
 	}
 
-	err := room.addMember(member)
+	err = room.addMember(ctx, member)
 	if err != nil {
 		return fmt.Errorf("failed to add member to room: %w", err)
 	}
@@ -11,7 +11,7 @@ func (s *Suite) TestAddMember() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member := &MockMember{username: "user_1"}
 
 		// When
@@ -40,7 +40,7 @@ func (s *Suite) TestAddMember() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member := &MockMember{username: "user_1"}
 
 		// When
@@ -62,7 +62,7 @@ func (s *Suite) TestAddMember() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member1 := &MockMember{username: "user_1"}
 		member2 := &MockMember{username: "user_2"}
 		member3 := &MockMember{username: "user_3"}
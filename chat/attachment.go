@@ -0,0 +1,130 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+)
+
+// Attachment is a file uploaded to the server, sent along with a message.
+type Attachment struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	Type string `json:"type"`
+	// URL is where room members download the file from
+	URL string `json:"url"`
+	// Width and Height are the dimensions of images, which may have a
+	// Thumbnail
+	Width     int        `json:"width,omitempty"`
+	Height    int        `json:"height,omitempty"`
+	Thumbnail *Thumbnail `json:"thumbnail,omitempty"`
+}
+
+// Thumbnail is a preview of an image attachment.
+type Thumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// AttachmentResolver returns the attachments messages refer to by ID, and
+// whether username uploaded them.
+type AttachmentResolver interface {
+	Attachment(ctx context.Context, username string, id string) (_ *Attachment, uploaded bool, err error)
+}
+
+type noAttachments struct{}
+
+func (noAttachments) Attachment(context.Context, string, string) (*Attachment, bool, error) {
+	return nil, false, ErrAttachmentNotFound
+}
+
+func WithAttachmentResolver(attachments AttachmentResolver) Option {
+	return func(s *Service) {
+		s.attachments = attachments
+	}
+}
+
+// resolveAttachments returns the attachments of the given IDs for sender to
+// send. Attachments sender didn't upload are only sent again if sender may
+// download them, for their ID not to give access to them.
+func (r *Service) resolveAttachments(ctx context.Context, sender string, ids []string) ([]Attachment, error) {
+	var attachments []Attachment
+	for _, id := range ids {
+		attachment, uploaded, err := r.attachments.Attachment(ctx, sender, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve attachment %s: %w", id, err)
+		}
+
+		if !uploaded {
+			allowed, err := r.CanDownload(ctx, sender, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve attachment %s: %w", id, err)
+			}
+
+			if !allowed {
+				return nil, fmt.Errorf("failed to resolve attachment %s: %w", id, ErrAttachmentNotFound)
+			}
+		}
+
+		attachments = append(attachments, *attachment)
+	}
+
+	return attachments, nil
+}
+
+// CanDownload reports whether the attachment id has been sent to one of the
+// rooms username is in.
+func (r *Service) CanDownload(ctx context.Context, username string, id string) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "CanDownload", usernameAttribute(username))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	r.mtx.Lock()
+
+	found := false
+	for _, room := range r.rooms {
+		if _, ok := room.members[username]; ok && room.hasAttachment(id) {
+			found = true
+			break
+		}
+	}
+
+	owners := r.joinedOwners(ctx, username)
+
+	r.mtx.Unlock()
+
+	if found {
+		return true, nil
+	}
+
+	for _, node := range owners {
+		result, err := r.forward(ctx, node, peerCall{Method: peerCanDownload, Username: username, Target: id})
+		if err != nil {
+			return false, fmt.Errorf("failed to check rooms of %s: %w", node, err)
+		}
+
+		if result.Allowed {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// hasAttachment reports whether a message of the room has the attachment id.
+func (r *Room) hasAttachment(id string) bool {
+	return r.attachments[id] > 0
+}
+
+// countAttachments adds delta to the count of the messages sending each
+// attachment of message.
+func (r *Room) countAttachments(message *Message, delta int) {
+	for _, attachment := range message.Attachments {
+		r.attachments[attachment.ID] += delta
+		if r.attachments[attachment.ID] <= 0 {
+			delete(r.attachments, attachment.ID)
+		}
+	}
+}
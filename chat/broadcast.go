@@ -0,0 +1,61 @@
+package chat
+
+import (
+	"context"
+	"practice-run/metrics"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Broadcast notifies every connected member of event, regardless of the rooms
+// they're in, including the members connected to other nodes.
+func (r *Service) Broadcast(ctx context.Context, event Event) (err error) {
+	_, span := startSpan(ctx, "Broadcast", attribute.String("chat.event", event.Name()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	span.SetAttributes(attribute.Int("chat.recipients", len(r.members)))
+
+	start := time.Now()
+	defer func() {
+		metrics.BroadcastDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	r.broadcastLocal(event)
+
+	r.cluster.publishBroadcast(event)
+
+	if r.sharding != nil {
+		encoded, err := encodeEvent(event)
+		if err != nil {
+			return err
+		}
+
+		for _, node := range r.sharding.others() {
+			r.sharding.send(node, peerCall{Method: peerBroadcast, Event: encoded})
+		}
+	}
+
+	return nil
+}
+
+// BroadcastLocal notifies every member connected to this node of event.
+func (r *Service) BroadcastLocal(ctx context.Context, event Event) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.broadcastLocal(event)
+}
+
+func (r *Service) broadcastLocal(event Event) {
+	for _, member := range r.members {
+		if !isRemote(member) {
+			member.Notify(event)
+		}
+	}
+}
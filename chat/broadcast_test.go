@@ -0,0 +1,28 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+)
+
+func (s *Suite) TestBroadcast() {
+	s.Run("notify every connected member", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.Connect(ctx, member1)
+		_ = s.svc.Connect(ctx, member2)
+		_ = s.svc.AddMember(ctx, roomName, member1)
+
+		// When
+		err := s.svc.Broadcast(ctx, &chat.ServerShutdownEvent{})
+
+		// Then
+		s.NoError(err)
+		s.Equal(&chat.ServerShutdownEvent{}, member1.lastNotification)
+		s.Equal(&chat.ServerShutdownEvent{}, member2.lastNotification)
+	})
+}
@@ -0,0 +1,26 @@
+package chat
+
+import "context"
+
+// Broker carries messages between the nodes of a cluster. Subscribe returns
+// once subscribed, after which every message published on topic is handed to
+// handle, in publication order, until ctx is done.
+type Broker interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+	Subscribe(ctx context.Context, topic string, handle func(payload []byte)) error
+}
+
+// WithBroker makes the service a node, identified by node, of the cluster of
+// services sharing broker. Nodes share the rooms, their ACLs and members, and
+// the connected users, each member being notified by the node it's connected
+// to. The service must be started with Start.
+func WithBroker(broker Broker, node string) Option {
+	return func(s *Service) {
+		s.cluster = &cluster{
+			broker: broker,
+			node:   node,
+			outbox: make(chan []byte, outboxSize),
+			done:   make(chan struct{}),
+		}
+	}
+}
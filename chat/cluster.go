@@ -0,0 +1,655 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+)
+
+// clusterTopic is the broker topic the nodes of a cluster exchange
+// clusterMessages on.
+const clusterTopic = "practice-run.chat"
+
+// outboxSize is the number of messages a node may have waiting to be
+// published before dropping them.
+const outboxSize = 1024
+
+type messageType string
+
+const (
+	// State changes, applied by every other node without notifying anyone:
+	// the events they cause are published separately
+	messageRoomCreated  messageType = "room_created"
+	messageRoomDeleted  messageType = "room_deleted"
+	messageMemberJoined messageType = "member_joined"
+	messageMemberLeft   messageType = "member_left"
+	messageRuleAdded    messageType = "acl_rule_added"
+	messageRuleRemoved  messageType = "acl_rule_removed"
+	messageConnected    messageType = "connected"
+	messageDisconnected messageType = "disconnected"
+	messageMessageSent  messageType = "message_sent"
+	// messageMessageUpdated replaces a message edited or deleted
+	messageMessageUpdated messageType = "message_updated"
+	// messageReactionAdded and messageReactionRemoved change the reactions of
+	// Username to a message, not to overwrite those of other nodes
+	messageReactionAdded   messageType = "reaction_added"
+	messageReactionRemoved messageType = "reaction_removed"
+	// messageMarkedRead moves the read position of Username forward to Seq
+	messageMarkedRead messageType = "marked_read"
+
+	// messageDisconnectUser asks Node to disconnect one of its members
+	messageDisconnectUser messageType = "disconnect_user"
+	// messageEvent notifies Recipients of Event
+	messageEvent messageType = "event"
+	// messageBroadcast notifies every member of Event
+	messageBroadcast messageType = "broadcast"
+
+	// messageSyncRequest asks every node to publish its state in a messageSync
+	messageSyncRequest messageType = "sync_request"
+	messageSync        messageType = "sync"
+	// messageNodeLeft tells the origin node has left the cluster
+	messageNodeLeft messageType = "node_left"
+)
+
+// clusterMessage is published by the Origin node on every change of the
+// shared state, and to notify the members connected to other nodes.
+type clusterMessage struct {
+	Origin      string        `json:"origin"`
+	Type        messageType   `json:"type"`
+	Room        string        `json:"room,omitempty"`
+	Username    string        `json:"username,omitempty"`
+	Owner       string        `json:"owner,omitempty"`
+	Rule        *Rule         `json:"rule,omitempty"`
+	RemoteAddr  string        `json:"remote_addr,omitempty"`
+	ConnectedAt time.Time     `json:"connected_at"`
+	Node        string        `json:"node,omitempty"`
+	Reason      string        `json:"reason,omitempty"`
+	Event       *encodedEvent `json:"event,omitempty"`
+	Recipients  []string      `json:"recipients,omitempty"`
+	Snapshot    *snapshot     `json:"snapshot,omitempty"`
+	Message     *Message      `json:"message,omitempty"`
+	Reaction    *reaction     `json:"reaction,omitempty"`
+	Seq         uint64        `json:"seq,omitempty"`
+	// Away is set on messageMemberLeft for members leaving by disconnecting
+	Away bool `json:"away,omitempty"`
+}
+
+// snapshot is the part of the shared state a node is authoritative for: the
+// members connected to it, and the rooms they're in. Rooms are included with
+// their ACL and messages, even when empty, for them to be known by new nodes.
+type snapshot struct {
+	Rooms       []roomSnapshot       `json:"rooms"`
+	Connections []connectionSnapshot `json:"connections"`
+}
+
+type roomSnapshot struct {
+	Name     string     `json:"name"`
+	Rules    []Rule     `json:"rules"`
+	Members  []string   `json:"members"`
+	Messages []*Message `json:"messages,omitempty"`
+	// Read holds the read positions of every member, past ones included,
+	// and Away the users who were in the room when disconnecting
+	Read map[string]uint64 `json:"read,omitempty"`
+	Away []string          `json:"away,omitempty"`
+}
+
+type connectionSnapshot struct {
+	Username    string    `json:"username"`
+	RemoteAddr  string    `json:"remote_addr"`
+	ConnectedAt time.Time `json:"connected_at"`
+}
+
+type cluster struct {
+	broker Broker
+	node   string
+
+	// outbox queues the messages published under the service lock, in order,
+	// for them to be sent without holding it
+	outbox chan []byte
+	// stopped is protected by the service lock
+	stopped bool
+	done    chan struct{}
+
+	logger *slog.Logger
+}
+
+// publish queues message for publication. It's a no-op outside of a cluster.
+// Messages are dropped when too many are waiting, rather than blocking the
+// service on a slow or unreachable broker.
+func (c *cluster) publish(message clusterMessage) {
+	if c == nil || c.stopped {
+		return
+	}
+
+	message.Origin = c.node
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		c.logger.Error("failed to encode cluster message", slog.String("type", string(message.Type)), slog.Any("error", err))
+		return
+	}
+
+	select {
+	case c.outbox <- payload:
+	default:
+		c.logger.Error("failed to publish cluster message: too many messages waiting", slog.String("type", string(message.Type)))
+	}
+}
+
+// publishEvent notifies the recipients, connected to other nodes, of event.
+func (c *cluster) publishEvent(event Event, recipients []string) {
+	if c == nil || len(recipients) == 0 {
+		return
+	}
+
+	c.publishWithEvent(clusterMessage{Type: messageEvent, Recipients: recipients}, event)
+}
+
+// publishBroadcast notifies every member connected to other nodes of event.
+func (c *cluster) publishBroadcast(event Event) {
+	if c == nil {
+		return
+	}
+
+	c.publishWithEvent(clusterMessage{Type: messageBroadcast}, event)
+}
+
+func (c *cluster) publishWithEvent(message clusterMessage, event Event) {
+	encoded, err := encodeEvent(event)
+	if err != nil {
+		c.logger.Error("failed to encode event", slog.String("event", event.Name()), slog.Any("error", err))
+		return
+	}
+
+	message.Event = encoded
+	c.publish(message)
+}
+
+func (c *cluster) run() {
+	defer close(c.done)
+
+	for payload := range c.outbox {
+		err := c.broker.Publish(context.Background(), clusterTopic, payload)
+		if err != nil {
+			c.logger.Error("failed to publish cluster message", slog.Any("error", err))
+		}
+	}
+}
+
+// remoteMember stands for a member connected to another node. Notifying it
+// publishes the event for its node to notify the actual member.
+type remoteMember struct {
+	username   string
+	node       string
+	remoteAddr string
+	cluster    *cluster
+}
+
+func (m *remoteMember) Username() string {
+	return m.username
+}
+
+func (m *remoteMember) Notify(event Event) {
+	m.cluster.publishEvent(event, []string{m.username})
+}
+
+func (m *remoteMember) RemoteAddr() string {
+	return m.remoteAddr
+}
+
+func isRemote(member Member) bool {
+	_, ok := member.(*remoteMember)
+	return ok
+}
+
+// Start rebuilds the state recorded in the journal given by WithJournal, if
+// any, then joins the cluster of services sharing the broker given by
+// WithBroker, if any, and retrieves the state of the other nodes. The service
+// stops receiving from the cluster when ctx is done.
+func (r *Service) Start(ctx context.Context) error {
+	err := r.replay()
+	if err != nil {
+		return err
+	}
+
+	if r.cluster == nil {
+		return nil
+	}
+
+	go r.cluster.run()
+
+	err = r.cluster.broker.Subscribe(ctx, clusterTopic, r.receive)
+	if err != nil {
+		return fmt.Errorf("failed to join cluster: %w", err)
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.cluster.publish(clusterMessage{Type: messageSyncRequest})
+
+	r.logger.Info("joined cluster", slog.String("node", r.cluster.node))
+
+	return nil
+}
+
+// Stop tells the other nodes this node is leaving the cluster, and waits for
+// the messages it published to be sent or for ctx to be done. The journal is
+// then compacted and closed.
+func (r *Service) Stop(ctx context.Context) error {
+	return errors.Join(r.leaveCluster(ctx), r.closeJournal())
+}
+
+func (r *Service) leaveCluster(ctx context.Context) error {
+	if r.cluster == nil {
+		return nil
+	}
+
+	r.mtx.Lock()
+	if !r.cluster.stopped {
+		r.cluster.publish(clusterMessage{Type: messageNodeLeft})
+		r.cluster.stopped = true
+		close(r.cluster.outbox)
+	}
+	r.mtx.Unlock()
+
+	select {
+	case <-r.cluster.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to publish cluster messages: %w", ctx.Err())
+	}
+}
+
+func (r *Service) receive(payload []byte) {
+	var message clusterMessage
+	err := json.Unmarshal(payload, &message)
+	if err != nil {
+		r.logger.Warn("failed to decode cluster message", slog.Any("error", err))
+		return
+	}
+
+	if message.Origin == r.cluster.node {
+		return
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	err = r.apply(context.Background(), message)
+	if err != nil {
+		r.logger.Warn("failed to apply cluster message",
+			slog.String("type", string(message.Type)),
+			slog.String("origin", message.Origin),
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (r *Service) apply(ctx context.Context, message clusterMessage) error {
+	switch message.Type {
+	case messageMemberJoined:
+		room, ok := r.rooms[message.Room]
+		if !ok {
+			return ErrRoomNotFound
+		}
+
+		member, ok := r.members[message.Username]
+		if !ok {
+			member = r.connectRemote(message.Username, message.Origin, "", time.Now())
+		}
+
+		room.members[message.Username] = member
+		room.startReading(message.Username)
+		room.setAway(message.Username, false)
+
+		r.journal.append(message)
+
+	case messageMemberLeft:
+		if room, ok := r.rooms[message.Room]; ok {
+			delete(room.members, message.Username)
+			room.setAway(message.Username, message.Away)
+		}
+
+		r.journal.append(message)
+
+	case messageConnected:
+		r.connectRemote(message.Username, message.Origin, message.RemoteAddr, message.ConnectedAt)
+
+	case messageDisconnected:
+		member, ok := r.members[message.Username].(*remoteMember)
+		if ok && member.node == message.Origin {
+			r.disconnectRemote(member)
+		}
+
+	case messageDisconnectUser:
+		member, ok := r.members[message.Username]
+		if message.Node == r.cluster.node && ok && !isRemote(member) {
+			r.disconnectUser(ctx, member, message.Reason)
+		}
+
+	case messageEvent, messageBroadcast:
+		event, err := decodeEvent(message.Event)
+		if err != nil {
+			return err
+		}
+
+		if message.Type == messageBroadcast {
+			r.broadcastLocal(event)
+			break
+		}
+
+		for _, username := range message.Recipients {
+			if member, ok := r.members[username]; ok && !isRemote(member) {
+				member.Notify(event)
+			}
+		}
+
+	case messageSyncRequest:
+		r.cluster.publish(clusterMessage{Type: messageSync, Snapshot: r.snapshot()})
+
+	case messageSync:
+		if message.Snapshot == nil {
+			return fmt.Errorf("missing snapshot")
+		}
+
+		r.applySnapshot(message.Origin, message.Snapshot)
+		r.journal.compact()
+
+	case messageNodeLeft:
+		for _, member := range r.members {
+			if member, ok := member.(*remoteMember); ok && member.node == message.Origin {
+				r.disconnectRemote(member)
+			}
+		}
+
+	default:
+		err := r.applyChange(message)
+		if err != nil {
+			return err
+		}
+
+		r.journal.append(message)
+	}
+
+	return nil
+}
+
+// applyChange applies a change of the rooms, their ACL or messages, made by
+// another node or replayed from the journal.
+func (r *Service) applyChange(message clusterMessage) error {
+	switch message.Type {
+	case messageRoomCreated:
+		if _, ok := r.rooms[message.Room]; !ok {
+			r.rooms[message.Room] = r.newRoom(message.Room, message.Owner)
+		}
+
+	case messageRoomDeleted:
+		delete(r.rooms, message.Room)
+
+	case messageRuleAdded, messageRuleRemoved:
+		room, ok := r.rooms[message.Room]
+		if !ok {
+			return ErrRoomNotFound
+		}
+
+		if message.Rule == nil {
+			return ErrInvalidRule
+		}
+
+		if message.Type == messageRuleAdded {
+			room.acl.add(*message.Rule)
+		} else {
+			room.acl.remove(*message.Rule)
+		}
+
+	case messageMessageSent:
+		room, ok := r.rooms[message.Room]
+		if !ok {
+			return ErrRoomNotFound
+		}
+
+		if message.Message == nil {
+			return fmt.Errorf("missing message")
+		}
+
+		room.store(message.Message)
+
+	case messageMessageUpdated:
+		room, ok := r.rooms[message.Room]
+		if !ok {
+			return ErrRoomNotFound
+		}
+
+		if message.Message == nil {
+			return fmt.Errorf("missing message")
+		}
+
+		room.replace(message.Message)
+
+	case messageReactionAdded, messageReactionRemoved:
+		room, ok := r.rooms[message.Room]
+		if !ok {
+			return ErrRoomNotFound
+		}
+
+		if message.Reaction == nil {
+			return fmt.Errorf("missing reaction")
+		}
+
+		_, err := room.setReaction(message.Reaction.MessageID, message.Username, message.Reaction.Emoji, message.Type == messageReactionAdded)
+		if err != nil && !errors.Is(err, ErrAlreadyReacted) && !errors.Is(err, ErrReactionNotFound) {
+			return err
+		}
+
+	case messageMarkedRead:
+		room, ok := r.rooms[message.Room]
+		if !ok {
+			return ErrRoomNotFound
+		}
+
+		room.setRead(message.Username, message.Seq)
+
+	default:
+		return fmt.Errorf("unknown message type %q", message.Type)
+	}
+
+	return nil
+}
+
+// connectRemote registers the user username as connected to node, replacing
+// any member connected under the same username in the rooms it's in. A
+// member connected to this node is only replaced by a more recent
+// connection, and is then notified with a SessionTakenOverEvent.
+func (r *Service) connectRemote(username, node, remoteAddr string, connectedAt time.Time) Member {
+	previous, ok := r.members[username]
+	if ok && !isRemote(previous) {
+		local := r.connectedAt[username]
+		if local.After(connectedAt) || local.Equal(connectedAt) && r.cluster.node > node {
+			return previous
+		}
+
+		previous.Notify(&SessionTakenOverEvent{})
+
+		r.logger.Info("session taken over", slog.String("username", username), slog.String("node", node))
+	}
+
+	member := &remoteMember{username: username, node: node, remoteAddr: remoteAddr, cluster: r.cluster}
+
+	r.members[username] = member
+	r.connectedAt[username] = connectedAt
+
+	if ok {
+		for _, room := range r.rooms {
+			room.replaceMember(previous, member)
+		}
+	}
+
+	return member
+}
+
+// disconnectRemote unregisters a member connected to another node and
+// removes it from every room it's in. Only the members of these rooms
+// connected to this node are notified, other nodes doing the same.
+func (r *Service) disconnectRemote(member *remoteMember) {
+	delete(r.members, member.username)
+	delete(r.connectedAt, member.username)
+
+	for _, room := range r.rooms {
+		if room.members[member.username] != member {
+			continue
+		}
+
+		delete(room.members, member.username)
+		room.setAway(member.username, true)
+
+		room.notifyLocal(&MemberLeftEvent{RoomName: room.Name(), MemberName: member.username})
+	}
+}
+
+func (r *Service) snapshot() *snapshot {
+	s := &snapshot{Rooms: []roomSnapshot{}, Connections: []connectionSnapshot{}}
+
+	for username, member := range r.members {
+		if isRemote(member) {
+			continue
+		}
+
+		connection := connectionSnapshot{Username: username, ConnectedAt: r.connectedAt[username]}
+		if member, ok := member.(RemoteAddressable); ok {
+			connection.RemoteAddr = member.RemoteAddr()
+		}
+
+		s.Connections = append(s.Connections, connection)
+	}
+
+	for _, room := range r.rooms {
+		roomSnapshot := room.snapshot()
+		for username, member := range room.members {
+			if !isRemote(member) {
+				roomSnapshot.Members = append(roomSnapshot.Members, username)
+			}
+		}
+
+		s.Rooms = append(s.Rooms, roomSnapshot)
+	}
+
+	return s
+}
+
+func (r *Service) applySnapshot(node string, s *snapshot) {
+	for _, connection := range s.Connections {
+		r.connectRemote(connection.Username, node, connection.RemoteAddr, connection.ConnectedAt)
+	}
+
+	for _, roomSnapshot := range s.Rooms {
+		room, ok := r.rooms[roomSnapshot.Name]
+		if !ok {
+			room = r.newRoom(roomSnapshot.Name, "")
+			for _, rule := range roomSnapshot.Rules {
+				room.acl.add(rule)
+			}
+
+			r.rooms[roomSnapshot.Name] = room
+		}
+
+		for _, username := range roomSnapshot.Members {
+			if member, ok := r.members[username]; ok {
+				room.members[username] = member
+			}
+		}
+
+		room.restore(roomSnapshot)
+	}
+}
+
+// snapshot returns the rules, messages, read positions and away users of the
+// room, without its members.
+func (r *Room) snapshot() roomSnapshot {
+	s := roomSnapshot{Name: r.Name(), Rules: r.acl.Rules(), Members: []string{}, Read: maps.Clone(r.read)}
+	for _, message := range r.messages {
+		s.Messages = append(s.Messages, message)
+	}
+
+	for username := range r.away {
+		s.Away = append(s.Away, username)
+	}
+
+	return s
+}
+
+// restore adds the messages, read positions and away users of s to the room.
+func (r *Room) restore(s roomSnapshot) {
+	r.restoreMessages(s.Messages)
+
+	for username, seq := range s.Read {
+		r.setRead(username, seq)
+	}
+
+	for _, username := range s.Away {
+		if _, ok := r.members[username]; !ok {
+			r.setAway(username, true)
+		}
+	}
+}
+
+// encodedEvent is an event as published to other nodes.
+type encodedEvent struct {
+	Name    string          `json:"name"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// eventTypes returns an empty event of every name, for events published to
+// other nodes to be decoded. Every event must be registered here.
+var eventTypes = map[string]func() Event{
+	MessageReceivedEventName:    func() Event { return &MessageReceivedEvent{} },
+	MessageEditedEventName:      func() Event { return &MessageEditedEvent{} },
+	MentionEventName:            func() Event { return &MentionEvent{} },
+	TypingEventName:             func() Event { return &TypingEvent{} },
+	MessageDeletedEventName:     func() Event { return &MessageDeletedEvent{} },
+	ReactionAddedEventName:      func() Event { return &ReactionAddedEvent{} },
+	ReactionRemovedEventName:    func() Event { return &ReactionRemovedEvent{} },
+	ReadReceiptEventName:        func() Event { return &ReadReceiptEvent{} },
+	MemberJoinedEventName:       func() Event { return &MemberJoinedEvent{} },
+	MemberLeftEventName:         func() Event { return &MemberLeftEvent{} },
+	InvitedEventName:            func() Event { return &InvitedEvent{} },
+	SessionTakenOverEventName:   func() Event { return &SessionTakenOverEvent{} },
+	ServerShutdownEventName:     func() Event { return &ServerShutdownEvent{} },
+	SystemAnnouncementEventName: func() Event { return &SystemAnnouncementEvent{} },
+	DisconnectedEventName:       func() Event { return &DisconnectedEvent{} },
+	RemovedEventName:            func() Event { return &RemovedEvent{} },
+	RoomDeletedEventName:        func() Event { return &RoomDeletedEvent{} },
+}
+
+func encodeEvent(event Event) (*encodedEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encodedEvent{Name: event.Name(), Payload: payload}, nil
+}
+
+func decodeEvent(encoded *encodedEvent) (Event, error) {
+	if encoded == nil {
+		return nil, fmt.Errorf("missing event")
+	}
+
+	newEvent, ok := eventTypes[encoded.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", encoded.Name)
+	}
+
+	event := newEvent()
+
+	err := json.Unmarshal(encoded.Payload, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", encoded.Name, err)
+	}
+
+	return event, nil
+}
@@ -0,0 +1,279 @@
+package chat_test
+
+import (
+	"context"
+	"fmt"
+	"practice-run/broker"
+	"practice-run/chat"
+	"sync"
+	"time"
+)
+
+func (s *Suite) TestCluster() {
+	s.Run("share rooms and members between nodes", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, "test_room", "user_1")
+		s.Eventually(func() bool {
+			return len(node2.ListRooms(ctx)) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		// When
+		err1 := node2.AddMember(ctx, "test_room", member2)
+		s.Eventually(func() bool {
+			return node1.ListRooms(ctx)[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+		err2 := node1.AddMember(ctx, "test_room", member1)
+		sent, err3 := node1.SendMessage(ctx, "test_room", member1, "hello")
+
+		// Then
+		s.NoError(err1)
+		s.NoError(err2)
+		s.NoError(err3)
+		s.Eventually(func() bool {
+			return len(member2.received()) == 2
+		}, time.Second, 10*time.Millisecond)
+		s.Equal([]chat.Event{
+			&chat.MemberJoinedEvent{RoomName: "test_room", MemberName: "user_1"},
+			&chat.MessageReceivedEvent{RoomName: "test_room", MessageID: sent.ID, SenderName: "user_1", Message: "hello"},
+		}, member2.received())
+		s.Empty(member1.received())
+		s.Equal([]chat.RoomInfo{{Name: "test_room", Members: 2}}, node2.ListRooms(ctx))
+	})
+
+	s.Run("reject users connected to another node", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		_ = node1.Connect(ctx, &RecordingMember{username: "user_1"})
+		s.Eventually(func() bool {
+			return node2.IsConnected(ctx, "user_1")
+		}, time.Second, 10*time.Millisecond)
+
+		// When
+		err := node2.Connect(ctx, &RecordingMember{username: "user_1"})
+
+		// Then
+		s.ErrorIs(err, chat.ErrAlreadyConnected)
+	})
+
+	s.Run("take over sessions of another node", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		previous := &RecordingMember{username: "user_1"}
+		_ = node1.Connect(ctx, previous)
+		s.Eventually(func() bool {
+			return node2.IsConnected(ctx, "user_1")
+		}, time.Second, 10*time.Millisecond)
+
+		// When
+		_, err := node2.Takeover(ctx, &RecordingMember{username: "user_1"})
+
+		// Then
+		s.NoError(err)
+		s.Eventually(func() bool {
+			return len(previous.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+		s.Equal([]chat.Event{&chat.SessionTakenOverEvent{}}, previous.received())
+		s.Empty(node1.ConnectedMembers(ctx))
+	})
+
+	s.Run("sync state to new nodes", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		member := &RecordingMember{username: "user_1"}
+		_ = node1.Connect(ctx, member)
+		_, _ = node1.CreateRoom(ctx, "test_room", "user_1")
+		_ = node1.AddMember(ctx, "test_room", member)
+
+		// When
+		node2 := s.startNode(b, "node_2")
+
+		// Then
+		s.Eventually(func() bool {
+			rooms := node2.ListRooms(ctx)
+			return len(rooms) == 1 && rooms[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+		s.True(node2.IsConnected(ctx, "user_1"))
+		rules, err := node2.GetACL(ctx, "test_room", member)
+		s.NoError(err)
+		s.Equal([]chat.Rule{{Effect: chat.EffectAllow, Permission: chat.PermissionManage, Subject: "@user_1"}}, rules)
+	})
+
+	s.Run("remove the members of nodes leaving", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		_ = node1.Connect(ctx, member1)
+		_, _ = node1.CreateRoom(ctx, "test_room", "user_1")
+		_ = node1.AddMember(ctx, "test_room", member1)
+		s.Eventually(func() bool {
+			return len(node2.ListRooms(ctx)) == 1
+		}, time.Second, 10*time.Millisecond)
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node2.Connect(ctx, member2)
+		_ = node2.AddMember(ctx, "test_room", member2)
+		s.Eventually(func() bool {
+			return len(member1.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		// When
+		err := node2.Stop(ctx)
+
+		// Then
+		s.NoError(err)
+		s.Eventually(func() bool {
+			return len(member1.received()) == 2
+		}, time.Second, 10*time.Millisecond)
+		s.Equal(&chat.MemberLeftEvent{RoomName: "test_room", MemberName: "user_2"}, member1.received()[1])
+		s.False(node1.IsConnected(ctx, "user_2"))
+		s.Equal([]chat.RoomInfo{{Name: "test_room", Members: 1}}, node1.ListRooms(ctx))
+	})
+
+	s.Run("broadcast to every node", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+
+		// When
+		err := node1.Broadcast(ctx, &chat.SystemAnnouncementEvent{Message: "hello"})
+
+		// Then
+		s.NoError(err)
+		s.Eventually(func() bool {
+			return len(member2.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+		s.Equal([]chat.Event{&chat.SystemAnnouncementEvent{Message: "hello"}}, member1.received())
+		s.Equal([]chat.Event{&chat.SystemAnnouncementEvent{Message: "hello"}}, member2.received())
+	})
+
+	s.Run("drop messages while the broker isn't keeping up", func() {
+		// Given
+		ctx := context.Background()
+		b := &heldBroker{Broker: broker.NewInProcess(), held: make(chan struct{})}
+		node := s.startNode(b, "node_1")
+		member := &MockMember{username: "user_1"}
+		_, _ = node.CreateRoom(ctx, "test_room", "user_1")
+		_ = node.AddMember(ctx, "test_room", member)
+		defer close(b.held)
+
+		// When
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range 2000 {
+				_, _ = node.SendMessage(ctx, "test_room", member, fmt.Sprintf("message %d", i))
+			}
+		}()
+
+		// Then
+		s.Eventually(func() bool {
+			select {
+			case <-done:
+				return true
+			default:
+				return false
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	s.Run("disconnect users connected to another node", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		member := &RecordingMember{username: "user_1"}
+		_ = node2.Connect(ctx, member)
+		s.Eventually(func() bool {
+			return node1.IsConnected(ctx, "user_1")
+		}, time.Second, 10*time.Millisecond)
+
+		// When
+		err := node1.DisconnectUser(ctx, "user_1", "spam")
+
+		// Then
+		s.NoError(err)
+		s.Eventually(func() bool {
+			return !node1.IsConnected(ctx, "user_1")
+		}, time.Second, 10*time.Millisecond)
+		s.Equal([]chat.Event{&chat.DisconnectedEvent{Reason: "spam"}}, member.received())
+		s.False(node2.IsConnected(ctx, "user_1"))
+	})
+}
+
+// startNode starts a service sharing b, stopped at the end of the test.
+func (s *Suite) startNode(b chat.Broker, name string) *chat.Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	node := chat.NewService(chat.WithBroker(b, name))
+	s.Require().NoError(node.Start(ctx))
+
+	s.T().Cleanup(func() {
+		_ = node.Stop(context.Background())
+		cancel()
+	})
+
+	return node
+}
+
+// heldBroker is a broker whose publications wait until held is closed.
+type heldBroker struct {
+	chat.Broker
+	held chan struct{}
+}
+
+func (b *heldBroker) Publish(ctx context.Context, topic string, payload []byte) error {
+	<-b.held
+	return b.Broker.Publish(ctx, topic, payload)
+}
+
+// RecordingMember records its notifications, which may be received from
+// other goroutines.
+type RecordingMember struct {
+	username string
+
+	mu     sync.Mutex
+	events []chat.Event
+}
+
+func (m *RecordingMember) Username() string {
+	return m.username
+}
+
+func (m *RecordingMember) Notify(event chat.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, event)
+}
+
+func (m *RecordingMember) received() []chat.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]chat.Event(nil), m.events...)
+}
@@ -0,0 +1,236 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Connect registers member as connected. Each username may only be connected
+// once at a time, across the nodes of a sharded cluster too.
+func (r *Service) Connect(ctx context.Context, member Member) (err error) {
+	ctx, span := startSpan(ctx, "Connect", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if r.connectedElsewhere(ctx, member.Username()) {
+		return ErrAlreadyConnected
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if _, ok := r.members[member.Username()]; ok {
+		return ErrAlreadyConnected
+	}
+
+	r.members[member.Username()] = member
+	r.connectedAt[member.Username()] = time.Now()
+
+	r.publishConnected(member)
+
+	r.logger.Debug("connected", slog.String("username", member.Username()))
+
+	return nil
+}
+
+// Takeover registers member as connected, replacing any member connected
+// under the same username. The replaced member, which is returned, is
+// notified with a SessionTakenOverEvent and its room memberships are
+// transferred to member without the other room members noticing. A member
+// connected to another node is notified by that node. In a sharded cluster,
+// a member connected to another node is disconnected by it instead, leaving
+// its rooms.
+func (r *Service) Takeover(ctx context.Context, member Member) (_ Member, err error) {
+	ctx, span := startSpan(ctx, "Takeover", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	for _, node := range r.otherNodes(ctx) {
+		_, err := r.forward(ctx, node, peerCall{Method: peerTakeover, Username: member.Username()})
+		if err != nil {
+			r.logger.Warn("failed to take session over", slog.String("node", node), slog.Any("error", err))
+		}
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	previous, ok := r.members[member.Username()]
+
+	r.members[member.Username()] = member
+	r.connectedAt[member.Username()] = time.Now()
+
+	r.publishConnected(member)
+
+	if !ok {
+		return nil, nil
+	}
+
+	for _, room := range r.rooms {
+		room.replaceMember(previous, member)
+	}
+
+	if !isRemote(previous) {
+		previous.Notify(&SessionTakenOverEvent{})
+	}
+
+	r.logger.Info("session taken over", slog.String("username", member.Username()))
+
+	return previous, nil
+}
+
+// takenOver disconnects the member username connected to this node, whose
+// session has been taken over on another node of a sharded cluster.
+func (r *Service) takenOver(ctx context.Context, username string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	member, ok := r.members[username]
+	if !ok {
+		return
+	}
+
+	r.disconnect(ctx, member)
+
+	member.Notify(&SessionTakenOverEvent{})
+
+	r.logger.Info("session taken over on another node", slog.String("username", username))
+}
+
+// Disconnect unregisters member and removes it from every room it's in. It's
+// a no-op if member has been taken over in the meantime.
+func (r *Service) Disconnect(ctx context.Context, member Member) (err error) {
+	ctx, span := startSpan(ctx, "Disconnect", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.members[member.Username()] != member {
+		return nil
+	}
+
+	r.disconnect(ctx, member)
+
+	return nil
+}
+
+// disconnect unregisters the connected member and removes it from every room
+// it's in.
+func (r *Service) disconnect(ctx context.Context, member Member) {
+	delete(r.members, member.Username())
+	delete(r.connectedAt, member.Username())
+
+	r.logger.Debug("disconnected", slog.String("username", member.Username()))
+
+	for _, room := range r.rooms {
+		if room.members[member.Username()] == member {
+			_ = room.removeMember(ctx, member, true)
+		}
+	}
+
+	r.cluster.publish(clusterMessage{Type: messageDisconnected, Username: member.Username()})
+
+	if r.sharding != nil {
+		for roomName := range r.sharding.joined[member.Username()] {
+			r.sharding.send(r.sharding.ring.Owner(roomName), peerCall{Method: peerDisconnectMember, Room: roomName, Username: member.Username()})
+		}
+
+		delete(r.sharding.joined, member.Username())
+	}
+}
+
+// disconnectedFrom removes the member username, disconnected from another node
+// of a sharded cluster, from the room.
+func (r *Service) disconnectedFrom(ctx context.Context, roomName string, username string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	room, ok := r.rooms[roomName]
+	if !ok {
+		return ErrRoomNotFound
+	}
+
+	member, ok := room.members[username]
+	if !ok {
+		return ErrNotRoomMember
+	}
+
+	return room.removeMember(ctx, member, true)
+}
+
+// publishConnected tells the other nodes member connected to this node.
+func (r *Service) publishConnected(member Member) {
+	message := clusterMessage{
+		Type:        messageConnected,
+		Username:    member.Username(),
+		ConnectedAt: r.connectedAt[member.Username()],
+	}
+
+	if member, ok := member.(RemoteAddressable); ok {
+		message.RemoteAddr = member.RemoteAddr()
+	}
+
+	r.cluster.publish(message)
+}
+
+// IsConnected returns whether username is connected, to any node of a
+// cluster.
+func (r *Service) IsConnected(ctx context.Context, username string) bool {
+	r.mtx.Lock()
+	_, ok := r.members[username]
+	r.mtx.Unlock()
+
+	return ok || r.connectedElsewhere(ctx, username)
+}
+
+// connectedElsewhere returns whether username is connected to another node of
+// a sharded cluster. Nodes failing to answer are skipped.
+func (r *Service) connectedElsewhere(ctx context.Context, username string) bool {
+	for _, node := range r.otherNodes(ctx) {
+		result, err := r.forward(ctx, node, peerCall{Method: peerConnected, Username: username})
+		if err != nil {
+			r.logger.Warn("failed to check connection", slog.String("node", node), slog.Any("error", err))
+			continue
+		}
+
+		if result.Connected {
+			return true
+		}
+	}
+
+	return false
+}
+
+// connectedNode returns the other node of a sharded cluster username is
+// connected to. Unlike connectedElsewhere, it asks the other nodes for calls
+// forwarded by one of them too, peerConnected calls never being forwarded
+// again. Nodes failing to answer are skipped.
+func (r *Service) connectedNode(ctx context.Context, username string) (string, bool) {
+	if r.sharding == nil {
+		return "", false
+	}
+
+	r.mtx.Lock()
+	nodes := r.sharding.others()
+	r.mtx.Unlock()
+
+	for _, node := range nodes {
+		result, err := r.forward(ctx, node, peerCall{Method: peerConnected, Username: username})
+		if err != nil {
+			r.logger.Warn("failed to check connection", slog.String("node", node), slog.Any("error", err))
+			continue
+		}
+
+		if result.Connected {
+			return node, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,119 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+)
+
+func (s *Suite) TestConnect() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		member := &MockMember{username: "user_1"}
+
+		// When
+		err := s.svc.Connect(ctx, member)
+
+		// Then
+		s.NoError(err)
+		s.True(s.svc.IsConnected(ctx, "user_1"))
+	})
+
+	s.Run("already connected", func() {
+		// Given
+		ctx := context.Background()
+		_ = s.svc.Connect(ctx, &MockMember{username: "user_1"})
+
+		// When
+		err := s.svc.Connect(ctx, &MockMember{username: "user_1"})
+
+		// Then
+		s.ErrorIs(err, chat.ErrAlreadyConnected)
+	})
+}
+
+func (s *Suite) TestTakeover() {
+	s.Run("transfer room memberships", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		previous := &MockMember{username: "user_1"}
+		other := &MockMember{username: "user_2"}
+		_ = s.svc.Connect(ctx, previous)
+		_ = s.svc.AddMember(ctx, roomName, previous)
+		_ = s.svc.AddMember(ctx, roomName, other)
+		member := &MockMember{username: "user_1"}
+
+		// When
+		replaced, err := s.svc.Takeover(ctx, member)
+
+		// Then
+		s.NoError(err)
+		s.Equal(previous, replaced)
+		s.Equal(&chat.SessionTakenOverEvent{}, previous.lastNotification)
+		members, _ := s.svc.GetMembers(ctx, roomName)
+		s.Contains(members, member)
+		s.NotContains(members, previous)
+		s.Nil(other.lastNotification)
+	})
+
+	s.Run("no previous session", func() {
+		// Given
+		ctx := context.Background()
+		member := &MockMember{username: "user_1"}
+
+		// When
+		replaced, err := s.svc.Takeover(ctx, member)
+
+		// Then
+		s.NoError(err)
+		s.Nil(replaced)
+		s.True(s.svc.IsConnected(ctx, "user_1"))
+	})
+}
+
+func (s *Suite) TestDisconnect() {
+	s.Run("leave rooms", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		member := &MockMember{username: "user_1"}
+		other := &MockMember{username: "user_2"}
+		_ = s.svc.Connect(ctx, member)
+		_ = s.svc.AddMember(ctx, roomName, member)
+		_ = s.svc.AddMember(ctx, roomName, other)
+
+		// When
+		err := s.svc.Disconnect(ctx, member)
+
+		// Then
+		s.NoError(err)
+		s.False(s.svc.IsConnected(ctx, "user_1"))
+		members, _ := s.svc.GetMembers(ctx, roomName)
+		s.NotContains(members, member)
+		s.Equal(&chat.MemberLeftEvent{RoomName: roomName, MemberName: "user_1"}, other.lastNotification)
+	})
+
+	s.Run("ignore taken over sessions", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		previous := &MockMember{username: "user_1"}
+		_ = s.svc.Connect(ctx, previous)
+		_ = s.svc.AddMember(ctx, roomName, previous)
+		member := &MockMember{username: "user_1"}
+		_, _ = s.svc.Takeover(ctx, member)
+
+		// When
+		err := s.svc.Disconnect(ctx, previous)
+
+		// Then
+		s.NoError(err)
+		s.True(s.svc.IsConnected(ctx, "user_1"))
+		members, _ := s.svc.GetMembers(ctx, roomName)
+		s.Contains(members, member)
+	})
+}
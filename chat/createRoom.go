@@ -2,24 +2,61 @@ package chat
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
+	"time"
 )
 
-func (r *Service) CreateRoom(ctx context.Context, name string) (*Room, error) {
+// CreateRoom creates a room whose ACL lets owner manage it.
+func (r *Service) CreateRoom(ctx context.Context, name string, owner string) (_ *Room, err error) {
+	ctx, span := startSpan(ctx, "CreateRoom", roomAttribute(name), usernameAttribute(owner))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, name); ok {
+		_, err = r.forward(ctx, node, peerCall{Method: peerCreateRoom, Room: name, Username: owner})
+		return nil, err
+	}
+
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
 	room, ok := r.rooms[name]
 	if ok {
-		return nil, fmt.Errorf("room already exists")
+		return nil, ErrRoomAlreadyExists
 	}
 
-	room = &Room{
-		name:    name,
-		members: make(map[string]Member),
-	}
+	room = r.newRoom(name, owner)
 
 	r.rooms[name] = room
 
+	r.publish(clusterMessage{Type: messageRoomCreated, Room: name, Owner: owner})
+
+	room.logger.Debug("created room", slog.String("username", owner))
+
 	return room, nil
 }
+
+// newRoom returns an empty room whose ACL lets owner, if any, manage it.
+func (r *Service) newRoom(name string, owner string) *Room {
+	room := &Room{
+		name:        name,
+		members:     make(map[string]Member),
+		messages:    make(map[string]*Message),
+		index:       newSearchIndex(),
+		attachments: make(map[string]int),
+		typing:      make(map[string]time.Time),
+		read:        make(map[string]uint64),
+		away:        make(map[string]bool),
+		groups:      r.groups,
+		cluster:     r.cluster,
+		journal:     r.journal,
+		logger:      r.logger.With(slog.String("room", name)),
+	}
+
+	if owner != "" {
+		room.acl.add(Rule{Effect: EffectAllow, Permission: PermissionManage, Subject: "@" + owner})
+	}
+
+	return room
+}
@@ -12,7 +12,7 @@ func (s *Suite) TestCreateRoom() {
 		roomName := "test_room"
 
 		// When
-		r, err := s.svc.CreateRoom(ctx, roomName)
+		r, err := s.svc.CreateRoom(ctx, roomName, "owner")
 
 		// Then
 		s.NoError(err)
@@ -24,10 +24,10 @@ func (s *Suite) TestCreateRoom() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 
 		// When
-		r, err := s.svc.CreateRoom(ctx, roomName)
+		r, err := s.svc.CreateRoom(ctx, roomName, "owner")
 
 		// Then
 		s.Error(err)
@@ -45,7 +45,7 @@ func (s *Suite) TestCreateRoom() {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				_, _ = s.svc.CreateRoom(ctx, roomName)
+				_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 			}()
 		}
 		wg.Wait()
@@ -0,0 +1,43 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+)
+
+// DeleteMessage deletes the message id from the room member is in it has been
+// sent to, and returns the deleted message. Members may delete their own
+// messages, and those allowed to moderate or manage the room any message.
+func (r *Service) DeleteMessage(ctx context.Context, member Member, id string) (_ *Message, err error) {
+	ctx, span := startSpan(ctx, "DeleteMessage", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	r.mtx.Lock()
+
+	room, ok := r.messageRoom(member.Username(), id)
+	if !ok {
+		owners := r.joinedOwners(ctx, member.Username())
+		r.mtx.Unlock()
+
+		return messageResult(r.forwardMessageCall(ctx, owners, peerCall{
+			Method:   peerDeleteMessage,
+			Username: member.Username(),
+			Target:   id,
+		}))
+	}
+
+	defer r.mtx.Unlock()
+
+	if room.migrating {
+		return nil, ErrRoomMigrating
+	}
+
+	deleted, err := room.deleteMessage(ctx, member, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,143 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+	"time"
+)
+
+func (s *Suite) TestDeleteMessage() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "the deploy is done")
+
+		// When
+		deleted, err := s.svc.DeleteMessage(ctx, member1, sent.ID)
+
+		// Then
+		s.NoError(err)
+		s.Equal("the deploy is done", deleted.Text)
+		s.Equal("user_1", deleted.DeletedBy)
+		s.False(deleted.DeletedAt.IsZero())
+		s.Equal(&chat.MessageDeletedEvent{RoomName: "test_room", MessageID: sent.ID, DeletedBy: "user_1"}, member2.lastNotification)
+		results, _ := s.svc.Search(ctx, member1, "deploy")
+		s.Empty(results)
+	})
+
+	s.Run("message already deleted", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member, "hello")
+		_, _ = s.svc.DeleteMessage(ctx, member, sent.ID)
+
+		// When
+		_, err1 := s.svc.DeleteMessage(ctx, member, sent.ID)
+		_, err2 := s.svc.EditMessage(ctx, member, sent.ID, "bye")
+
+		// Then
+		s.ErrorIs(err1, chat.ErrMessageNotFound)
+		s.ErrorIs(err2, chat.ErrMessageNotFound)
+	})
+
+	s.Run("message of another member", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "hello")
+
+		// When
+		_, err := s.svc.DeleteMessage(ctx, member2, sent.ID)
+
+		// Then
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+	})
+
+	s.Run("moderators delete messages of other members", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		moderator := &MockMember{username: "moderator"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", moderator)
+		owner := &MockMember{username: "owner"}
+		_ = s.svc.AddACLRule(ctx, "test_room", owner, chat.Rule{Effect: chat.EffectAllow, Permission: chat.PermissionModerate, Subject: "@moderator"})
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "spam")
+
+		// When
+		deleted, err := s.svc.DeleteMessage(ctx, moderator, sent.ID)
+
+		// Then
+		s.NoError(err)
+		s.Equal("moderator", deleted.DeletedBy)
+		s.Equal("user_1", deleted.Sender)
+	})
+
+	s.Run("attachments of deleted messages", func() {
+		// Given
+		ctx := context.Background()
+		attachment := chat.Attachment{ID: "a1b2c3", Name: "server.log", Size: 18, Type: "text/plain", URL: "/attachments/a1b2c3"}
+		svc := chat.NewService(chat.WithAttachmentResolver(MockAttachments{attachment.ID: attachment}))
+		_, _ = svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = svc.AddMember(ctx, "test_room", member1)
+		_ = svc.AddMember(ctx, "test_room", member2)
+		first, _ := svc.SendMessage(ctx, "test_room", member1, "logs", attachment.ID)
+		second, _ := svc.SendMessage(ctx, "test_room", member1, "logs again", attachment.ID)
+
+		// When
+		_, _ = svc.DeleteMessage(ctx, member1, first.ID)
+		kept, _ := svc.CanDownload(ctx, "user_2", attachment.ID)
+		_, _ = svc.DeleteMessage(ctx, member1, second.ID)
+		revoked, _ := svc.CanDownload(ctx, "user_2", attachment.ID)
+
+		// Then
+		s.True(kept)
+		s.False(revoked)
+	})
+
+	s.Run("delete messages of rooms owned by other nodes", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, roomName, "owner")
+		_ = node1.AddMember(ctx, roomName, member1)
+		_ = node2.AddMember(ctx, roomName, member2)
+		sent, _ := node2.SendMessage(ctx, roomName, member2, "hello")
+
+		// When
+		_, err := node1.DeleteMessage(ctx, member1, sent.ID)
+
+		// Then
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+		deleted, err := node2.DeleteMessage(ctx, member2, sent.ID)
+		s.NoError(err)
+		s.Equal("user_2", deleted.DeletedBy)
+		s.Eventually(func() bool {
+			received := member1.received()
+			return len(received) > 0 && received[len(received)-1].Name() == chat.MessageDeletedEventName
+		}, time.Second, 10*time.Millisecond)
+	})
+}
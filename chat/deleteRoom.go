@@ -0,0 +1,38 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+)
+
+// DeleteRoom deletes a room on behalf of an operator, notifying its members
+// with a RoomDeletedEvent.
+func (r *Service) DeleteRoom(ctx context.Context, roomName string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteRoom", roomAttribute(roomName))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		_, err = r.forward(ctx, node, peerCall{Method: peerDeleteRoom, Room: roomName})
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	room, err := r.room(roomName)
+	if err != nil {
+		return err
+	}
+
+	delete(r.rooms, roomName)
+
+	r.publish(clusterMessage{Type: messageRoomDeleted, Room: roomName})
+
+	room.broadcastEvent(ctx, &RoomDeletedEvent{RoomName: roomName})
+
+	room.logger.Info("deleted room", slog.Int("members", len(room.members)))
+
+	return nil
+}
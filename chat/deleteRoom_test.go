@@ -0,0 +1,35 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+)
+
+func (s *Suite) TestDeleteRoom() {
+	s.Run("delete and notify members", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, roomName, member)
+
+		// When
+		err := s.svc.DeleteRoom(ctx, roomName)
+
+		// Then
+		s.NoError(err)
+		s.Equal(&chat.RoomDeletedEvent{RoomName: roomName}, member.lastNotification)
+
+		_, err = s.svc.GetMembers(ctx, roomName)
+		s.ErrorIs(err, chat.ErrRoomNotFound)
+	})
+
+	s.Run("room not found", func() {
+		// When
+		err := s.svc.DeleteRoom(context.Background(), "non_existent_room")
+
+		// Then
+		s.ErrorIs(err, chat.ErrRoomNotFound)
+	})
+}
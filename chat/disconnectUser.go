@@ -0,0 +1,65 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// DisconnectUser disconnects the connected user username on behalf of an
+// operator. It's removed from every room it's in and notified with a
+// DisconnectedEvent, upon which it's expected to close its connection. Users
+// connected to another node are disconnected by that node.
+func (r *Service) DisconnectUser(ctx context.Context, username string, reason string) (err error) {
+	ctx, span := startSpan(ctx, "DisconnectUser", usernameAttribute(username))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	err = r.disconnectLocalUser(ctx, username, reason)
+	if !errors.Is(err, ErrNotConnected) {
+		return err
+	}
+
+	for _, node := range r.otherNodes(ctx) {
+		_, err := r.forward(ctx, node, peerCall{Method: peerDisconnectUser, Username: username, Message: reason})
+		if errors.Is(err, ErrNotConnected) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to disconnect user from %s: %w", node, err)
+		}
+
+		return nil
+	}
+
+	return ErrNotConnected
+}
+
+func (r *Service) disconnectLocalUser(ctx context.Context, username string, reason string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	member, ok := r.members[username]
+	if !ok {
+		return ErrNotConnected
+	}
+
+	if member, ok := member.(*remoteMember); ok {
+		r.cluster.publish(clusterMessage{Type: messageDisconnectUser, Username: username, Node: member.node, Reason: reason})
+		return nil
+	}
+
+	r.disconnectUser(ctx, member, reason)
+
+	return nil
+}
+
+func (r *Service) disconnectUser(ctx context.Context, member Member, reason string) {
+	r.disconnect(ctx, member)
+
+	member.Notify(&DisconnectedEvent{Reason: reason})
+
+	r.logger.Info("disconnected user", slog.String("username", member.Username()), slog.String("reason", reason))
+}
@@ -0,0 +1,40 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+)
+
+func (s *Suite) TestDisconnectUser() {
+	s.Run("disconnect and remove from rooms", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.Connect(ctx, member1)
+		_ = s.svc.AddMember(ctx, roomName, member1)
+		_ = s.svc.AddMember(ctx, roomName, member2)
+
+		// When
+		err := s.svc.DisconnectUser(ctx, "user_1", "spam")
+
+		// Then
+		s.NoError(err)
+		s.False(s.svc.IsConnected(ctx, "user_1"))
+		s.Equal(&chat.DisconnectedEvent{Reason: "spam"}, member1.lastNotification)
+		s.Equal(&chat.MemberLeftEvent{RoomName: roomName, MemberName: "user_1"}, member2.lastNotification)
+
+		members, _ := s.svc.GetMembers(ctx, roomName)
+		s.Equal([]chat.Member{member2}, members)
+	})
+
+	s.Run("not connected", func() {
+		// When
+		err := s.svc.DisconnectUser(context.Background(), "user_1", "")
+
+		// Then
+		s.ErrorIs(err, chat.ErrNotConnected)
+	})
+}
@@ -0,0 +1,44 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+)
+
+// EditMessage replaces the text of the message id, sent by member to one of
+// the rooms they are in, and returns the edited message. Its previous text is
+// kept in its history.
+func (r *Service) EditMessage(ctx context.Context, member Member, id string, text string) (_ *Message, err error) {
+	ctx, span := startSpan(ctx, "EditMessage", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	r.mtx.Lock()
+
+	room, ok := r.messageRoom(member.Username(), id)
+	if !ok {
+		owners := r.joinedOwners(ctx, member.Username())
+		r.mtx.Unlock()
+
+		return messageResult(r.forwardMessageCall(ctx, owners, peerCall{
+			Method:   peerEditMessage,
+			Username: member.Username(),
+			Target:   id,
+			Message:  text,
+		}))
+	}
+
+	defer r.mtx.Unlock()
+
+	if room.migrating {
+		return nil, ErrRoomMigrating
+	}
+
+	edited, err := room.editMessage(ctx, member, id, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit message: %w", err)
+	}
+
+	return edited, nil
+}
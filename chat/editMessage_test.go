@@ -0,0 +1,220 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/broker"
+	"practice-run/chat"
+	"time"
+)
+
+func (s *Suite) TestEditMessage() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "the deploy is don")
+
+		// When
+		edited, err := s.svc.EditMessage(ctx, member1, sent.ID, "the deploy is done")
+
+		// Then
+		s.NoError(err)
+		s.Equal("the deploy is done", edited.Text)
+		s.False(edited.EditedAt.IsZero())
+		s.Equal([]chat.Revision{{Text: "the deploy is don", SentAt: sent.SentAt}}, edited.History)
+		s.Equal(&chat.MessageEditedEvent{
+			RoomName:   "test_room",
+			MessageID:  sent.ID,
+			SenderName: "user_1",
+			Message:    "the deploy is done",
+		}, member2.lastNotification)
+	})
+
+	s.Run("keep every previous version", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member, "v1")
+		first, _ := s.svc.EditMessage(ctx, member, sent.ID, "v2")
+
+		// When
+		edited, err := s.svc.EditMessage(ctx, member, sent.ID, "v3")
+
+		// Then
+		s.NoError(err)
+		s.Equal([]chat.Revision{
+			{Text: "v1", SentAt: sent.SentAt},
+			{Text: "v2", SentAt: first.EditedAt},
+		}, edited.History)
+	})
+
+	s.Run("search the edited text", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member, "the deploy failed")
+
+		// When
+		_, err := s.svc.EditMessage(ctx, member, sent.ID, "the release failed")
+
+		// Then
+		s.NoError(err)
+		results, _ := s.svc.Search(ctx, member, "deploy")
+		s.Empty(results)
+		results, _ = s.svc.Search(ctx, member, "release")
+		s.Len(results, 1)
+	})
+
+	s.Run("message of another member", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "hello")
+
+		// When
+		_, err := s.svc.EditMessage(ctx, member2, sent.ID, "bye")
+
+		// Then
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+	})
+
+	s.Run("post permission revoked", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		owner := &MockMember{username: "owner"}
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member, "the deploy is don")
+		s.Require().NoError(s.svc.AddACLRule(ctx, "test_room", owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionPost, Subject: "@user_1"}))
+
+		// When
+		_, err := s.svc.EditMessage(ctx, member, sent.ID, "the deploy is done")
+
+		// Then
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+	})
+
+	s.Run("mention the members of the edited text", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		member3 := &MockMember{username: "user_3"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		_ = s.svc.AddMember(ctx, "test_room", member3)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "@user_2 the deploy is done")
+
+		// When
+		edited, err := s.svc.EditMessage(ctx, member1, sent.ID, "@user_3 the deploy is done")
+
+		// Then
+		s.NoError(err)
+		s.Equal([]string{"user_3"}, edited.Mentions)
+	})
+
+	s.Run("message not found", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+
+		// When
+		_, err := s.svc.EditMessage(ctx, member, "0a1b2c3d4e5f", "hello")
+
+		// Then
+		s.ErrorIs(err, chat.ErrMessageNotFound)
+	})
+
+	s.Run("message of a room the member left", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member, "hello")
+		_ = s.svc.RemoveMember(ctx, "test_room", member)
+
+		// When
+		_, err := s.svc.EditMessage(ctx, member, sent.ID, "bye")
+
+		// Then
+		s.ErrorIs(err, chat.ErrMessageNotFound)
+	})
+
+	s.Run("edit messages on other nodes", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, "test_room", "user_1")
+		_ = node1.AddMember(ctx, "test_room", member1)
+		s.Eventually(func() bool {
+			rooms := node2.ListRooms(ctx)
+			return len(rooms) == 1 && rooms[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+		_ = node2.AddMember(ctx, "test_room", member2)
+		sent, _ := node1.SendMessage(ctx, "test_room", member1, "hello")
+
+		// When
+		_, err := node1.EditMessage(ctx, member1, sent.ID, "hello again")
+
+		// Then
+		s.NoError(err)
+		s.Eventually(func() bool {
+			results, err := node2.Search(ctx, member2, "again")
+			return err == nil && len(results) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	s.Run("edit messages of rooms owned by other nodes", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node1.AddMember(ctx, roomName, member1)
+		_ = node2.AddMember(ctx, roomName, member2)
+		sent, _ := node1.SendMessage(ctx, roomName, member1, "hello")
+
+		// When
+		edited, err := node1.EditMessage(ctx, member1, sent.ID, "hello again")
+		_, missing := node1.EditMessage(ctx, member1, "0a1b2c3d4e5f", "hello")
+
+		// Then
+		s.NoError(err)
+		s.Equal("hello again", edited.Text)
+		s.ErrorIs(missing, chat.ErrMessageNotFound)
+		s.Eventually(func() bool {
+			received := member2.received()
+			return len(received) > 0 && received[len(received)-1].Name() == chat.MessageEditedEventName
+		}, time.Second, 10*time.Millisecond)
+	})
+}
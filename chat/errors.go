@@ -0,0 +1,22 @@
+package chat
+
+import "errors"
+
+var (
+	ErrRoomNotFound        = errors.New("room not found")
+	ErrRoomAlreadyExists   = errors.New("room already exists")
+	ErrMemberAlreadyExists = errors.New("member already exists")
+	ErrNotRoomMember       = errors.New("not a room member")
+	ErrAlreadyConnected    = errors.New("already connected")
+	ErrNotConnected        = errors.New("not connected")
+	ErrPermissionDenied    = errors.New("permission denied")
+	ErrInvalidRule         = errors.New("invalid acl rule")
+	ErrRuleNotFound        = errors.New("acl rule not found")
+	ErrInvalidQuery        = errors.New("invalid search query")
+	ErrAttachmentNotFound  = errors.New("attachment not found")
+	ErrMessageNotFound     = errors.New("message not found")
+	ErrInvalidReaction     = errors.New("invalid reaction")
+	ErrAlreadyReacted      = errors.New("already reacted")
+	ErrReactionNotFound    = errors.New("reaction not found")
+	ErrRoomMigrating       = errors.New("room is being migrated to another node")
+)
@@ -1,19 +1,137 @@
 package chat
 
+import "time"
+
 type Event interface {
 	Name() string
 }
 
 const MessageReceivedEventName = "message_received"
 
+// MessageReceivedEvent is sent to the members of a room when a message is
+// sent to it, and to the followers of a thread when a reply is. Replies have
+// the ID of the first message of their thread, and its number of replies.
 type MessageReceivedEvent struct {
+	RoomName    string
+	MessageID   string
+	SenderName  string
+	Message     string
+	Attachments []Attachment
+	ParentID    string
+	Replies     int
+}
+
+func (e *MessageReceivedEvent) Name() string {
+	return MessageReceivedEventName
+}
+
+const MentionEventName = "mention"
+
+// MentionEvent is sent instead of a MessageReceivedEvent to the members
+// mentioned in a message, replies included whether they follow their thread
+// or not. Mention is the username of the member, or MentionHere or MentionRoom
+// when every member is.
+type MentionEvent struct {
+	RoomName    string
+	MessageID   string
+	SenderName  string
+	Message     string
+	Attachments []Attachment
+	ParentID    string
+	Mention     string
+}
+
+func (e *MentionEvent) Name() string {
+	return MentionEventName
+}
+
+const TypingEventName = "typing"
+
+// TypingEvent is sent to the other members of a room when a member is typing
+// a message, until ExpiresAt unless signaled again.
+type TypingEvent struct {
+	RoomName  string
+	Username  string
+	ExpiresAt time.Time
+}
+
+func (e *TypingEvent) Name() string {
+	return TypingEventName
+}
+
+const ReadReceiptEventName = "read_receipt"
+
+// ReadReceiptEvent is sent to the sender of a message when another member
+// marks it as read.
+type ReadReceiptEvent struct {
+	RoomName  string
+	MessageID string
+	Username  string
+}
+
+func (e *ReadReceiptEvent) Name() string {
+	return ReadReceiptEventName
+}
+
+const MessageEditedEventName = "message_edited"
+
+// MessageEditedEvent is sent to the members of a room when a message is
+// edited by its sender.
+type MessageEditedEvent struct {
 	RoomName   string
+	MessageID  string
 	SenderName string
 	Message    string
 }
 
-func (e *MessageReceivedEvent) Name() string {
-	return MessageReceivedEventName
+func (e *MessageEditedEvent) Name() string {
+	return MessageEditedEventName
+}
+
+const MessageDeletedEventName = "message_deleted"
+
+// MessageDeletedEvent is sent to the members of a room when a message is
+// deleted by its sender or a moderator.
+type MessageDeletedEvent struct {
+	RoomName  string
+	MessageID string
+	DeletedBy string
+}
+
+func (e *MessageDeletedEvent) Name() string {
+	return MessageDeletedEventName
+}
+
+const ReactionAddedEventName = "reaction_added"
+
+// ReactionAddedEvent is sent to the members of a room when a member reacts to
+// a message. Count is the number of members who reacted with Emoji.
+type ReactionAddedEvent struct {
+	RoomName  string
+	MessageID string
+	Username  string
+	Emoji     string
+	Count     int
+}
+
+func (e *ReactionAddedEvent) Name() string {
+	return ReactionAddedEventName
+}
+
+const ReactionRemovedEventName = "reaction_removed"
+
+// ReactionRemovedEvent is sent to the members of a room when a member removes
+// their reaction to a message.
+type ReactionRemovedEvent struct {
+	RoomName  string
+	MessageID string
+	Username  string
+	Emoji     string
+	Count     int
+}
+
+func (e *ReactionRemovedEvent) Name() string {
+	return ReactionRemovedEventName
 }
 
 const MemberJoinedEventName = "member_joined"
@@ -37,3 +155,82 @@ type MemberLeftEvent struct {
 func (e *MemberLeftEvent) Name() string {
 	return MemberLeftEventName
 }
+
+const InvitedEventName = "invited"
+
+// InvitedEvent is sent to a user who has been added to a room by another member.
+type InvitedEvent struct {
+	RoomName    string
+	InviterName string
+}
+
+func (e *InvitedEvent) Name() string {
+	return InvitedEventName
+}
+
+const SessionTakenOverEventName = "session_taken_over"
+
+// SessionTakenOverEvent is sent to a member whose session has been taken over
+// by a new connection for the same user.
+type SessionTakenOverEvent struct{}
+
+func (e *SessionTakenOverEvent) Name() string {
+	return SessionTakenOverEventName
+}
+
+const ServerShutdownEventName = "server_shutdown"
+
+// ServerShutdownEvent is broadcast to every connected member when the server
+// shuts down.
+type ServerShutdownEvent struct{}
+
+func (e *ServerShutdownEvent) Name() string {
+	return ServerShutdownEventName
+}
+
+const SystemAnnouncementEventName = "system_announcement"
+
+// SystemAnnouncementEvent is broadcast to every connected member, regardless
+// of the rooms they're in, on behalf of the server operators.
+type SystemAnnouncementEvent struct {
+	Message string
+	// Severity is SeverityInfo if empty.
+	Severity Severity
+}
+
+func (e *SystemAnnouncementEvent) Name() string {
+	return SystemAnnouncementEventName
+}
+
+const DisconnectedEventName = "disconnected"
+
+// DisconnectedEvent is sent to a member disconnected by an operator.
+type DisconnectedEvent struct {
+	Reason string
+}
+
+func (e *DisconnectedEvent) Name() string {
+	return DisconnectedEventName
+}
+
+const RemovedEventName = "removed"
+
+// RemovedEvent is sent to a member removed from a room by an operator.
+type RemovedEvent struct {
+	RoomName string
+}
+
+func (e *RemovedEvent) Name() string {
+	return RemovedEventName
+}
+
+const RoomDeletedEventName = "room_deleted"
+
+// RoomDeletedEvent is sent to the members of a room when it's deleted.
+type RoomDeletedEvent struct {
+	RoomName string
+}
+
+func (e *RoomDeletedEvent) Name() string {
+	return RoomDeletedEventName
+}
@@ -6,12 +6,20 @@ import (
 )
 
 func (r *Service) GetMembers(ctx context.Context, roomName string) ([]Member, error) {
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		result, err := r.forward(ctx, node, peerCall{Method: peerGetMembers, Room: roomName})
+		if err != nil {
+			return nil, err
+		}
+		return r.peerMembers(result.Members), nil
+	}
+
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
-	room, ok := r.rooms[roomName]
-	if !ok {
-		return nil, fmt.Errorf("room not found")
+	room, err := r.room(roomName)
+	if err != nil {
+		return nil, err
 	}
 
 	members, err := room.getMembers()
@@ -7,7 +7,7 @@ func (s *Suite) TestGetMembers() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member1 := &MockMember{username: "user_1"}
 		member2 := &MockMember{username: "user_2"}
 		_ = s.svc.AddMember(ctx, roomName, member1)
@@ -0,0 +1,84 @@
+package chat
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+)
+
+const (
+	// DefaultHistoryLimit is the number of messages returned by History when
+	// no limit is given, and MaxHistoryLimit the maximum.
+	DefaultHistoryLimit = 20
+	MaxHistoryLimit     = 100
+)
+
+// History returns the last messages of the room, oldest first, with their
+// reactions. Deleted messages and the replies of threads are left out.
+func (r *Service) History(ctx context.Context, roomName string, member Member, limit int) (_ []*Message, err error) {
+	ctx, span := startSpan(ctx, "History", roomAttribute(roomName), usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	limit = min(limit, MaxHistoryLimit)
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		result, err := r.forward(ctx, node, peerCall{
+			Method:   peerHistory,
+			Room:     roomName,
+			Username: member.Username(),
+			Limit:    limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return result.Messages, nil
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	room, err := r.room(roomName)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := room.history(member, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (r *Room) history(member Member, limit int) ([]*Message, error) {
+	if _, ok := r.members[member.Username()]; !ok {
+		return nil, ErrNotRoomMember
+	}
+
+	messages := make([]*Message, 0, len(r.messages))
+	for _, message := range r.messages {
+		if !message.deleted() && message.ParentID == "" {
+			messages = append(messages, message)
+		}
+	}
+
+	slices.SortFunc(messages, func(a, b *Message) int {
+		return cmp.Or(a.SentAt.Compare(b.SentAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	messages = messages[max(0, len(messages)-limit):]
+
+	history := make([]*Message, 0, len(messages))
+	for _, message := range messages {
+		history = append(history, message.clone())
+	}
+
+	return history, nil
+}
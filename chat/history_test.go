@@ -0,0 +1,89 @@
+package chat_test
+
+import (
+	"context"
+	"fmt"
+	"practice-run/chat"
+)
+
+func (s *Suite) TestHistory() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		first, _ := s.svc.SendMessage(ctx, "test_room", member, "first")
+		deleted, _ := s.svc.SendMessage(ctx, "test_room", member, "oops")
+		_, _ = s.svc.SendMessage(ctx, "test_room", member, "last")
+		_, _ = s.svc.DeleteMessage(ctx, member, deleted.ID)
+		_, _ = s.svc.React(ctx, member, first.ID, "👍")
+
+		// When
+		messages, err := s.svc.History(ctx, "test_room", member, 0)
+
+		// Then
+		s.NoError(err)
+		s.Len(messages, 2)
+		s.Equal("first", messages[0].Text)
+		s.Equal([]chat.Reaction{{Emoji: "👍", Users: []string{"user_1"}}}, messages[0].Reactions)
+		s.Equal("last", messages[1].Text)
+	})
+
+	s.Run("last messages", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		for i := range chat.MaxHistoryLimit + 5 {
+			_, _ = s.svc.SendMessage(ctx, "test_room", member, fmt.Sprintf("message %d", i))
+		}
+
+		// When
+		last3, err1 := s.svc.History(ctx, "test_room", member, 3)
+		all, err2 := s.svc.History(ctx, "test_room", member, 1000)
+
+		// Then
+		s.NoError(err1)
+		s.NoError(err2)
+		s.Len(last3, 3)
+		s.Len(all, chat.MaxHistoryLimit)
+	})
+
+	s.Run("not a room member", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+
+		// When
+		_, err := s.svc.History(ctx, "test_room", member, 0)
+
+		// Then
+		s.ErrorIs(err, chat.ErrNotRoomMember)
+	})
+
+	s.Run("history of rooms owned by other nodes", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		_ = node1.Connect(ctx, member1)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node1.AddMember(ctx, roomName, member1)
+		_, _ = node1.SendMessage(ctx, roomName, member1, "hello")
+
+		// When
+		messages, err := node1.History(ctx, roomName, member1, 0)
+
+		// Then
+		s.NoError(err)
+		s.Len(messages, 1)
+		s.Equal("hello", messages[0].Text)
+	})
+}
@@ -0,0 +1,60 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+)
+
+// InviteMember adds the connected user username to a room on behalf of
+// inviter. Invited users don't need the join permission, unless they're
+// denied it as a user or a group member. In a sharded cluster, username may be
+// connected to any node.
+func (r *Service) InviteMember(ctx context.Context, roomName string, inviter Member, username string) (err error) {
+	ctx, span := startSpan(ctx, "InviteMember", roomAttribute(roomName), usernameAttribute(inviter.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		_, err = r.forward(ctx, node, peerCall{Method: peerInviteMember, Room: roomName, Username: inviter.Username(), Target: username})
+		return err
+	}
+
+	invitee, err := r.invitee(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	room, err := r.room(roomName)
+	if err != nil {
+		return err
+	}
+
+	err = room.inviteMember(ctx, inviter, invitee)
+	if err != nil {
+		return fmt.Errorf("failed to invite member to room: %w", err)
+	}
+
+	return nil
+}
+
+// invitee returns the member username, connected to this node or another node
+// of a sharded cluster.
+func (r *Service) invitee(ctx context.Context, username string) (Member, error) {
+	r.mtx.Lock()
+	member, ok := r.members[username]
+	r.mtx.Unlock()
+
+	if ok {
+		return member, nil
+	}
+
+	if node, ok := r.connectedNode(ctx, username); ok {
+		return &peerMember{username: username, node: node, sharding: r.sharding}, nil
+	}
+
+	return nil, ErrNotConnected
+}
@@ -0,0 +1,104 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+)
+
+func (s *Suite) TestInviteMember() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		owner := &MockMember{username: "owner"}
+		inviter := &MockMember{username: "user_1"}
+		invitee := &MockMember{username: "user_2"}
+		_ = s.svc.Connect(ctx, invitee)
+		_ = s.svc.AddMember(ctx, roomName, inviter)
+		_ = s.svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionJoin, Subject: chat.Everyone})
+
+		// When
+		err := s.svc.InviteMember(ctx, roomName, inviter, "user_2")
+
+		// Then
+		s.NoError(err)
+		members, _ := s.svc.GetMembers(ctx, roomName)
+		s.Contains(members, invitee)
+		s.Equal(&chat.InvitedEvent{RoomName: roomName, InviterName: "user_1"}, invitee.lastNotification)
+		s.Equal(&chat.MemberJoinedEvent{RoomName: roomName, MemberName: "user_2"}, inviter.lastNotification)
+	})
+
+	s.Run("invite denied", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		owner := &MockMember{username: "owner"}
+		inviter := &MockMember{username: "user_1"}
+		_ = s.svc.Connect(ctx, &MockMember{username: "user_2"})
+		_ = s.svc.AddMember(ctx, roomName, inviter)
+		_ = s.svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionInvite, Subject: "@user_1"})
+
+		// When
+		err := s.svc.InviteMember(ctx, roomName, inviter, "user_2")
+
+		// Then
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+	})
+
+	s.Run("invitee denied to join", func() {
+		// Given
+		ctx := context.Background()
+		svc := chat.NewService(chat.WithGroupResolver(MockGroups{"user_3": {"contractors"}}))
+		roomName := "test_room"
+		_, _ = svc.CreateRoom(ctx, roomName, "owner")
+		owner := &MockMember{username: "owner"}
+		inviter := &MockMember{username: "user_1"}
+		_ = svc.Connect(ctx, &MockMember{username: "user_2"})
+		_ = svc.Connect(ctx, &MockMember{username: "user_3"})
+		_ = svc.AddMember(ctx, roomName, inviter)
+		_ = svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionJoin, Subject: chat.Everyone})
+		_ = svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionJoin, Subject: "@user_2"})
+		_ = svc.AddACLRule(ctx, roomName, owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionJoin, Subject: "%contractors"})
+
+		// When
+		err1 := svc.InviteMember(ctx, roomName, inviter, "user_2")
+		err2 := svc.InviteMember(ctx, roomName, inviter, "user_3")
+
+		// Then
+		s.ErrorIs(err1, chat.ErrPermissionDenied)
+		s.ErrorIs(err2, chat.ErrPermissionDenied)
+		members, _ := svc.GetMembers(ctx, roomName)
+		s.Equal([]string{"user_1"}, usernames(members))
+	})
+
+	s.Run("invitee not connected", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		inviter := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, roomName, inviter)
+
+		// When
+		err := s.svc.InviteMember(ctx, roomName, inviter, "user_2")
+
+		// Then
+		s.ErrorIs(err, chat.ErrNotConnected)
+	})
+
+	s.Run("inviter not a member", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		_ = s.svc.Connect(ctx, &MockMember{username: "user_2"})
+
+		// When
+		err := s.svc.InviteMember(ctx, roomName, &MockMember{username: "user_1"}, "user_2")
+
+		// Then
+		s.ErrorIs(err, chat.ErrNotRoomMember)
+	})
+}
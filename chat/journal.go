@@ -0,0 +1,256 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Journal keeps the changes of the state of a service, for it to be rebuilt
+// after a restart. Records are appended to a log, which Compact replaces by a
+// snapshot of the state it leads to. Load returns the last snapshot, nil if
+// there's none, and the records appended since, in order.
+type Journal interface {
+	Append(record []byte) error
+	Compact(snapshot []byte) error
+	Load() (snapshot []byte, records [][]byte, err error)
+	Close() error
+}
+
+// journalVersion is the version of the records and snapshots written to the
+// journal. Those of later versions are rejected when replaying it.
+const journalVersion = 1
+
+// WithJournal records the changes of the rooms, their ACL, members, messages
+// and read positions in store, compacted every compactEvery records. The
+// state is rebuilt from it by Start. Connections not surviving restarts, the
+// members are rebuilt away: they're no longer in the rooms until they join
+// them again, but their unread messages are still counted.
+func WithJournal(store Journal, compactEvery int) Option {
+	return func(s *Service) {
+		s.journal = &journal{store: store, compactEvery: compactEvery}
+	}
+}
+
+type journal struct {
+	store        Journal
+	compactEvery int
+	// records is the number of records appended since the last compaction,
+	// and snapshot returns the state to compact them into. Both are
+	// protected by the service lock.
+	records  int
+	snapshot func() []roomSnapshot
+	// closed is set once the store is closed, after which changes are no
+	// longer recorded
+	closed bool
+
+	logger *slog.Logger
+}
+
+// journalRecord is a change of the state, as published to the other nodes of
+// a cluster.
+type journalRecord struct {
+	Version int            `json:"version"`
+	At      time.Time      `json:"at"`
+	Change  clusterMessage `json:"change"`
+}
+
+type journalSnapshot struct {
+	Version int            `json:"version"`
+	At      time.Time      `json:"at"`
+	Rooms   []roomSnapshot `json:"rooms"`
+}
+
+// append records change, compacting the journal when enough records have
+// been appended. It's a no-op without a journal.
+func (j *journal) append(change clusterMessage) {
+	if j == nil || j.closed {
+		return
+	}
+
+	record, err := json.Marshal(journalRecord{Version: journalVersion, At: time.Now().UTC(), Change: change})
+	if err != nil {
+		j.logger.Error("failed to encode journal record", slog.String("type", string(change.Type)), slog.Any("error", err))
+		return
+	}
+
+	err = j.store.Append(record)
+	if err != nil {
+		j.logger.Error("failed to append journal record", slog.String("type", string(change.Type)), slog.Any("error", err))
+		return
+	}
+
+	j.records++
+	if j.compactEvery > 0 && j.records >= j.compactEvery {
+		j.compact()
+	}
+}
+
+func (j *journal) compact() {
+	if j == nil || j.closed {
+		return
+	}
+
+	snapshot, err := json.Marshal(journalSnapshot{Version: journalVersion, At: time.Now().UTC(), Rooms: j.snapshot()})
+	if err != nil {
+		j.logger.Error("failed to encode journal snapshot", slog.Any("error", err))
+		return
+	}
+
+	err = j.store.Compact(snapshot)
+	if err != nil {
+		j.logger.Error("failed to compact journal", slog.Any("error", err))
+		return
+	}
+
+	j.logger.Debug("compacted journal", slog.Int("records", j.records))
+
+	j.records = 0
+}
+
+// publish shares change with the other nodes of a cluster and records it in
+// the journal.
+func (r *Service) publish(change clusterMessage) {
+	r.cluster.publish(change)
+	r.journal.append(change)
+}
+
+// publish shares change with the other nodes of a cluster and records it in
+// the journal.
+func (r *Room) publish(change clusterMessage) {
+	r.cluster.publish(change)
+	r.journal.append(change)
+}
+
+// roomSnapshots returns the state of every room, for it to be compacted.
+// Members are left out, their read positions being kept, and rebuilt away as
+// they have no connection after a restart.
+func (r *Service) roomSnapshots() []roomSnapshot {
+	rooms := make([]roomSnapshot, 0, len(r.rooms))
+	for _, room := range r.rooms {
+		s := room.snapshot()
+		for username := range room.members {
+			s.Away = append(s.Away, username)
+		}
+
+		rooms = append(rooms, s)
+	}
+
+	return rooms
+}
+
+// replay rebuilds the rooms from the journal. Members having no connection
+// to be rebuilt with, only their read positions are, away.
+func (r *Service) replay() error {
+	if r.journal == nil {
+		return nil
+	}
+
+	raw, records, err := r.journal.store.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load journal: %w", err)
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if raw != nil {
+		var snapshot journalSnapshot
+		err = json.Unmarshal(raw, &snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to decode journal snapshot: %w", err)
+		}
+
+		if snapshot.Version > journalVersion {
+			return fmt.Errorf("unsupported journal snapshot version %d", snapshot.Version)
+		}
+
+		for _, roomSnapshot := range snapshot.Rooms {
+			room := r.newRoom(roomSnapshot.Name, "")
+			for _, rule := range roomSnapshot.Rules {
+				room.acl.add(rule)
+			}
+
+			room.restore(roomSnapshot)
+
+			r.rooms[room.Name()] = room
+		}
+	}
+
+	for i, raw := range records {
+		var record journalRecord
+		err = json.Unmarshal(raw, &record)
+		if err != nil {
+			return fmt.Errorf("failed to decode journal record %d: %w", i, err)
+		}
+
+		if record.Version > journalVersion {
+			return fmt.Errorf("unsupported journal record version %d", record.Version)
+		}
+
+		err = r.replayChange(record.Change)
+		if err != nil {
+			r.logger.Warn("failed to replay journal record",
+				slog.String("type", string(record.Change.Type)),
+				slog.Int("record", i),
+				slog.Any("error", err),
+			)
+		}
+	}
+
+	r.journal.records = len(records)
+
+	r.logger.Info("replayed journal", slog.Int("rooms", len(r.rooms)), slog.Int("records", len(records)))
+
+	return nil
+}
+
+func (r *Service) replayChange(change clusterMessage) error {
+	switch change.Type {
+	case messageMemberJoined:
+		room, ok := r.rooms[change.Room]
+		if !ok {
+			return ErrRoomNotFound
+		}
+
+		room.startReading(change.Username)
+		room.setAway(change.Username, true)
+
+	case messageMemberLeft:
+		// Members are rebuilt without their connection: there's none to
+		// remove
+		if room, ok := r.rooms[change.Room]; ok {
+			room.setAway(change.Username, change.Away)
+		}
+
+	default:
+		return r.applyChange(change)
+	}
+
+	return nil
+}
+
+// closeJournal compacts the journal, for the next start to replay the
+// snapshot only, and closes it.
+func (r *Service) closeJournal() error {
+	if r.journal == nil {
+		return nil
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.journal.records > 0 {
+		r.journal.compact()
+	}
+
+	r.journal.closed = true
+
+	err := r.journal.store.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close journal: %w", err)
+	}
+
+	return nil
+}
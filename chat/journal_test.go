@@ -0,0 +1,218 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+	"sync"
+)
+
+func (s *Suite) TestJournal() {
+	s.Run("rebuild the rooms on restart", func() {
+		// Given
+		ctx := context.Background()
+		journal := &MemoryJournal{}
+		svc := chat.NewService(chat.WithJournal(journal, 100))
+		s.Require().NoError(svc.Start(ctx))
+		owner := &MockMember{username: "owner"}
+		member := &MockMember{username: "user_1"}
+		_, _ = svc.CreateRoom(ctx, "test_room", "owner")
+		_ = svc.AddMember(ctx, "test_room", owner)
+		_ = svc.AddMember(ctx, "test_room", member)
+		_ = svc.AddACLRule(ctx, "test_room", owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionPost, Subject: "@user_2"})
+		first, _ := svc.SendMessage(ctx, "test_room", owner, "deployed")
+		second, _ := svc.SendMessage(ctx, "test_room", owner, "rolled back")
+		_, _ = svc.React(ctx, member, first.ID, "👍")
+		_, _ = svc.MarkRead(ctx, "test_room", member, first.ID)
+		_ = svc.Disconnect(ctx, member)
+		s.Require().NoError(svc.Stop(ctx))
+
+		// When
+		restarted := chat.NewService(chat.WithJournal(journal, 100))
+		err := restarted.Start(ctx)
+
+		// Then
+		s.Require().NoError(err)
+		s.Equal([]chat.RoomInfo{{Name: "test_room", Members: 0}}, restarted.ListRooms(ctx))
+		away, _ := restarted.Unread(ctx, "user_1")
+		s.Equal([]chat.UnreadCount{{Room: "test_room", Count: 1}}, away)
+		_ = restarted.AddMember(ctx, "test_room", member)
+		rules, _ := restarted.GetACL(ctx, "test_room", member)
+		s.Equal([]chat.Rule{
+			{Effect: chat.EffectAllow, Permission: chat.PermissionManage, Subject: "@owner"},
+			{Effect: chat.EffectDeny, Permission: chat.PermissionPost, Subject: "@user_2"},
+		}, rules)
+		history, _ := restarted.History(ctx, "test_room", member, 0)
+		s.Require().Len(history, 2)
+		s.Equal([]chat.Reaction{{Emoji: "👍", Users: []string{"user_1"}}}, history[0].Reactions)
+		s.Equal(second.ID, history[1].ID)
+		counts, _ := restarted.Unread(ctx, "user_1")
+		s.Equal([]chat.UnreadCount{{Room: "test_room", Count: 1}}, counts)
+		third, _ := restarted.SendMessage(ctx, "test_room", member, "retrying")
+		s.Greater(third.Seq, second.Seq)
+	})
+
+	s.Run("rebuild the members away on restart", func() {
+		// Given
+		ctx := context.Background()
+		journal := &MemoryJournal{}
+		svc := chat.NewService(chat.WithJournal(journal, 100))
+		s.Require().NoError(svc.Start(ctx))
+		owner := &MockMember{username: "owner"}
+		member := &MockMember{username: "user_1"}
+		_, _ = svc.CreateRoom(ctx, "test_room", "owner")
+		_ = svc.AddMember(ctx, "test_room", owner)
+		_ = svc.AddMember(ctx, "test_room", member)
+		s.Require().NoError(svc.Stop(ctx))
+		restarted := chat.NewService(chat.WithJournal(journal, 100))
+		s.Require().NoError(restarted.Start(ctx))
+		_ = restarted.Connect(ctx, owner)
+		_ = restarted.Connect(ctx, member)
+		_ = restarted.AddMember(ctx, "test_room", owner)
+
+		// When
+		_, err := restarted.SendMessage(ctx, "test_room", member, "hello")
+
+		// Then
+		s.ErrorIs(err, chat.ErrNotRoomMember)
+		members, _ := restarted.GetMembers(ctx, "test_room")
+		s.Equal([]string{"owner"}, usernames(members))
+		_, _ = restarted.SendMessage(ctx, "test_room", owner, "deployed")
+		counts, _ := restarted.Unread(ctx, "user_1")
+		s.Equal([]chat.UnreadCount{{Room: "test_room", Count: 1}}, counts)
+		s.NoError(restarted.AddMember(ctx, "test_room", member))
+	})
+
+	s.Run("keep the replies of threads on restart", func() {
+		// Given
+		ctx := context.Background()
+		journal := &MemoryJournal{}
+		svc := chat.NewService(chat.WithJournal(journal, 100))
+		s.Require().NoError(svc.Start(ctx))
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_, _ = svc.CreateRoom(ctx, "test_room", "owner")
+		_ = svc.AddMember(ctx, "test_room", member1)
+		_ = svc.AddMember(ctx, "test_room", member2)
+		root, _ := svc.SendMessage(ctx, "test_room", member1, "deploy?")
+		for range 5 {
+			_, _ = svc.Reply(ctx, member2, root.ID, "go ahead")
+		}
+		s.Require().NoError(svc.Stop(ctx))
+
+		// When
+		restarted := chat.NewService(chat.WithJournal(journal, 100))
+		err := restarted.Start(ctx)
+
+		// Then
+		s.Require().NoError(err)
+		_ = restarted.AddMember(ctx, "test_room", member1)
+		thread, _ := restarted.Thread(ctx, member1, root.ID)
+		s.Require().Len(thread, 6)
+		s.Equal(5, thread[0].Replies)
+		s.Equal([]string{"user_1", "user_2"}, thread[0].Followers)
+	})
+
+	s.Run("replay the replies of threads restored from a snapshot", func() {
+		// Given
+		ctx := context.Background()
+		journal := &MemoryJournal{}
+		svc := chat.NewService(chat.WithJournal(journal, 6))
+		s.Require().NoError(svc.Start(ctx))
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_, _ = svc.CreateRoom(ctx, "test_room", "owner")
+		_ = svc.AddMember(ctx, "test_room", member1)
+		_ = svc.AddMember(ctx, "test_room", member2)
+		root, _ := svc.SendMessage(ctx, "test_room", member1, "deploy?")
+		for range 4 {
+			_, _ = svc.Reply(ctx, member2, root.ID, "go ahead")
+		}
+		snapshot, records, _ := journal.Load()
+		s.Require().NotNil(snapshot)
+		s.Require().Len(records, 2)
+
+		// When
+		restarted := chat.NewService(chat.WithJournal(journal, 6))
+		err := restarted.Start(ctx)
+
+		// Then
+		s.Require().NoError(err)
+		_ = restarted.AddMember(ctx, "test_room", member1)
+		thread, _ := restarted.Thread(ctx, member1, root.ID)
+		s.Require().Len(thread, 5)
+		s.Equal(4, thread[0].Replies)
+		s.Equal([]string{"user_1", "user_2"}, thread[0].Followers)
+	})
+
+	s.Run("compact the journal", func() {
+		// Given
+		ctx := context.Background()
+		journal := &MemoryJournal{}
+		svc := chat.NewService(chat.WithJournal(journal, 3))
+		s.Require().NoError(svc.Start(ctx))
+		_, _ = svc.CreateRoom(ctx, "room_1", "owner")
+		_, _ = svc.CreateRoom(ctx, "room_2", "owner")
+
+		// When
+		_, _ = svc.CreateRoom(ctx, "room_3", "owner")
+		_ = svc.DeleteRoom(ctx, "room_2")
+
+		// Then
+		snapshot, records, _ := journal.Load()
+		s.NotNil(snapshot)
+		s.Len(records, 1)
+		restarted := chat.NewService(chat.WithJournal(journal, 3))
+		s.Require().NoError(restarted.Start(ctx))
+		s.Equal([]chat.RoomInfo{{Name: "room_1"}, {Name: "room_3"}}, restarted.ListRooms(ctx))
+	})
+
+	s.Run("reject later versions", func() {
+		// Given
+		ctx := context.Background()
+		journal := &MemoryJournal{records: [][]byte{[]byte(`{"version":2,"change":{"type":"room_created","room":"test_room"}}`)}}
+		svc := chat.NewService(chat.WithJournal(journal, 100))
+
+		// When
+		err := svc.Start(ctx)
+
+		// Then
+		s.ErrorContains(err, "unsupported journal record version 2")
+	})
+}
+
+// MemoryJournal keeps the journal of a service in memory, for it to be
+// restarted with the same journal.
+type MemoryJournal struct {
+	mu       sync.Mutex
+	snapshot []byte
+	records  [][]byte
+}
+
+func (j *MemoryJournal) Append(record []byte) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.records = append(j.records, record)
+
+	return nil
+}
+
+func (j *MemoryJournal) Compact(snapshot []byte) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.snapshot, j.records = snapshot, nil
+
+	return nil
+}
+
+func (j *MemoryJournal) Load() ([]byte, [][]byte, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.snapshot, j.records, nil
+}
+
+func (j *MemoryJournal) Close() error {
+	return nil
+}
@@ -0,0 +1,79 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// RemoteAddressable is implemented by members that know the remote address
+// of their connection.
+type RemoteAddressable interface {
+	RemoteAddr() string
+}
+
+type ConnectionInfo struct {
+	Username    string
+	RemoteAddr  string
+	ConnectedAt time.Time
+	Rooms       []string
+}
+
+// ListConnections returns every connected member, sorted by username, with
+// the rooms it's in, including the members connected to the other nodes of a
+// sharded cluster.
+func (r *Service) ListConnections(ctx context.Context) []ConnectionInfo {
+	connections := r.listConnections()
+
+	for _, node := range r.otherNodes(ctx) {
+		result, err := r.forward(ctx, node, peerCall{Method: peerConnections})
+		if err != nil {
+			r.logger.Error("failed to list connections", slog.String("node", node), slog.Any("error", err))
+			continue
+		}
+
+		connections = append(connections, result.Connections...)
+	}
+
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].Username < connections[j].Username
+	})
+
+	return connections
+}
+
+func (r *Service) listConnections() []ConnectionInfo {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	connections := make([]ConnectionInfo, 0, len(r.members))
+	for username, member := range r.members {
+		connection := ConnectionInfo{
+			Username:    username,
+			ConnectedAt: r.connectedAt[username],
+			Rooms:       []string{},
+		}
+
+		if member, ok := member.(RemoteAddressable); ok {
+			connection.RemoteAddr = member.RemoteAddr()
+		}
+
+		for _, room := range r.rooms {
+			if room.members[username] == member {
+				connection.Rooms = append(connection.Rooms, room.Name())
+			}
+		}
+
+		if r.sharding != nil {
+			for roomName := range r.sharding.joined[username] {
+				connection.Rooms = append(connection.Rooms, roomName)
+			}
+		}
+		sort.Strings(connection.Rooms)
+
+		connections = append(connections, connection)
+	}
+
+	return connections
+}
@@ -0,0 +1,43 @@
+package chat_test
+
+import (
+	"context"
+	"time"
+)
+
+type MockRemoteMember struct {
+	MockMember
+	remoteAddr string
+}
+
+func (m *MockRemoteMember) RemoteAddr() string {
+	return m.remoteAddr
+}
+
+func (s *Suite) TestListConnections() {
+	s.Run("connections with their rooms", func() {
+		// Given
+		ctx := context.Background()
+		member1 := &MockRemoteMember{MockMember: MockMember{username: "user_1"}, remoteAddr: "10.0.0.1:1234"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.Connect(ctx, member2)
+		_ = s.svc.Connect(ctx, member1)
+		_, _ = s.svc.CreateRoom(ctx, "room_2", "owner")
+		_, _ = s.svc.CreateRoom(ctx, "room_1", "owner")
+		_ = s.svc.AddMember(ctx, "room_2", member1)
+		_ = s.svc.AddMember(ctx, "room_1", member1)
+
+		// When
+		connections := s.svc.ListConnections(ctx)
+
+		// Then
+		s.Require().Len(connections, 2)
+		s.Equal("user_1", connections[0].Username)
+		s.Equal("10.0.0.1:1234", connections[0].RemoteAddr)
+		s.Equal([]string{"room_1", "room_2"}, connections[0].Rooms)
+		s.WithinDuration(time.Now(), connections[0].ConnectedAt, time.Second)
+		s.Equal("user_2", connections[1].Username)
+		s.Empty(connections[1].RemoteAddr)
+		s.Empty(connections[1].Rooms)
+	})
+}
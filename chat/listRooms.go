@@ -0,0 +1,69 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+)
+
+type RoomInfo struct {
+	Name    string
+	Members int
+}
+
+// ListRooms returns every room with its member count, sorted by name,
+// including the rooms owned by the other nodes of a sharded cluster.
+func (r *Service) ListRooms(ctx context.Context) []RoomInfo {
+	rooms := r.LocalRooms(ctx)
+
+	for _, node := range r.otherNodes(ctx) {
+		result, err := r.forward(ctx, node, peerCall{Method: peerRooms})
+		if err != nil {
+			r.logger.Error("failed to list rooms", slog.String("node", node), slog.Any("error", err))
+			continue
+		}
+
+		// Rooms being handed over are listed by both nodes
+		for _, room := range result.Rooms {
+			if !slices.ContainsFunc(rooms, func(listed RoomInfo) bool { return listed.Name == room.Name }) {
+				rooms = append(rooms, room)
+			}
+		}
+	}
+
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].Name < rooms[j].Name
+	})
+
+	return rooms
+}
+
+// LocalRooms returns the rooms of this node with their member count, in no
+// particular order. In a sharded cluster, those are the rooms it owns.
+func (r *Service) LocalRooms(ctx context.Context) []RoomInfo {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	rooms := make([]RoomInfo, 0, len(r.rooms))
+	for _, room := range r.rooms {
+		rooms = append(rooms, RoomInfo{Name: room.Name(), Members: len(room.members)})
+	}
+
+	return rooms
+}
+
+// ConnectedMembers returns every member connected to this node.
+func (r *Service) ConnectedMembers(ctx context.Context) []Member {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	members := make([]Member, 0, len(r.members))
+	for _, member := range r.members {
+		if !isRemote(member) {
+			members = append(members, member)
+		}
+	}
+
+	return members
+}
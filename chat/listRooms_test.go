@@ -0,0 +1,38 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+)
+
+func (s *Suite) TestListRooms() {
+	s.Run("rooms with member counts", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "room_2", "owner")
+		_, _ = s.svc.CreateRoom(ctx, "room_1", "owner")
+		_ = s.svc.AddMember(ctx, "room_1", &MockMember{username: "user_1"})
+		_ = s.svc.AddMember(ctx, "room_1", &MockMember{username: "user_2"})
+
+		// When
+		rooms := s.svc.ListRooms(ctx)
+
+		// Then
+		s.Equal([]chat.RoomInfo{{Name: "room_1", Members: 2}, {Name: "room_2", Members: 0}}, rooms)
+	})
+}
+
+func (s *Suite) TestConnectedMembers() {
+	s.Run("connected members", func() {
+		// Given
+		ctx := context.Background()
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.Connect(ctx, member)
+
+		// When
+		members := s.svc.ConnectedMembers(ctx)
+
+		// Then
+		s.Equal([]chat.Member{member}, members)
+	})
+}
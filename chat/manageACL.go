@@ -0,0 +1,103 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+func (r *Service) GetACL(ctx context.Context, roomName string, member Member) (_ []Rule, err error) {
+	ctx, span := startSpan(ctx, "GetACL", roomAttribute(roomName), usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		result, err := r.forward(ctx, node, peerCall{Method: peerGetACL, Room: roomName, Username: member.Username()})
+		if err != nil {
+			return nil, err
+		}
+		return result.Rules, nil
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	room, err := r.room(roomName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, isMember := room.members[member.Username()]
+	if !isMember && !room.can(member.Username(), PermissionManage) {
+		return nil, ErrPermissionDenied
+	}
+
+	return room.acl.Rules(), nil
+}
+
+func (r *Service) AddACLRule(ctx context.Context, roomName string, member Member, rule Rule) (err error) {
+	ctx, span := startSpan(ctx, "AddACLRule", roomAttribute(roomName), usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		_, err = r.forward(ctx, node, peerCall{Method: peerAddACLRule, Room: roomName, Username: member.Username(), Rule: &rule})
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	room, err := r.room(roomName)
+	if err != nil {
+		return err
+	}
+
+	if !room.can(member.Username(), PermissionManage) {
+		return fmt.Errorf("failed to edit acl: %w", ErrPermissionDenied)
+	}
+
+	room.acl.add(rule)
+
+	r.publish(clusterMessage{Type: messageRuleAdded, Room: roomName, Rule: &rule})
+
+	room.logger.Info("added acl rule", slog.String("username", member.Username()), slog.String("rule", rule.String()))
+
+	return nil
+}
+
+func (r *Service) RemoveACLRule(ctx context.Context, roomName string, member Member, rule Rule) (err error) {
+	ctx, span := startSpan(ctx, "RemoveACLRule", roomAttribute(roomName), usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		_, err = r.forward(ctx, node, peerCall{Method: peerRemoveACLRule, Room: roomName, Username: member.Username(), Rule: &rule})
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	room, err := r.room(roomName)
+	if err != nil {
+		return err
+	}
+
+	if !room.can(member.Username(), PermissionManage) {
+		return fmt.Errorf("failed to edit acl: %w", ErrPermissionDenied)
+	}
+
+	if !room.acl.remove(rule) {
+		return ErrRuleNotFound
+	}
+
+	r.publish(clusterMessage{Type: messageRuleRemoved, Room: roomName, Rule: &rule})
+
+	room.logger.Info("removed acl rule", slog.String("username", member.Username()), slog.String("rule", rule.String()))
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package chat
+
+import (
+	"context"
+	"regexp"
+	"slices"
+)
+
+// MentionHere and MentionRoom mention every member of a room, which only
+// moderators may do. Both notify the same members, every member of a room
+// being connected.
+const (
+	MentionHere = "here"
+	MentionRoom = "room"
+)
+
+// mentionRegex matches the @username mentions of a message, not preceded by a
+// word character so that email addresses aren't mentions.
+var mentionRegex = regexp.MustCompile(`(?:^|[^\w@])@(\w+)`)
+
+// parseMentions returns the usernames mentioned in text, in order and once.
+func parseMentions(text string) []string {
+	var mentions []string
+	for _, match := range mentionRegex.FindAllStringSubmatch(text, -1) {
+		if !slices.Contains(mentions, match[1]) {
+			mentions = append(mentions, match[1])
+		}
+	}
+
+	return mentions
+}
+
+// mentions returns the members mentioned by sender in text, leaving out
+// anyone but the other members of the room. Mentioning every member is
+// denied to those not allowed to moderate the room.
+func (r *Room) mentions(sender string, text string) ([]string, error) {
+	var mentions []string
+	for _, mention := range parseMentions(text) {
+		switch mention {
+		case MentionHere, MentionRoom:
+			if !r.moderates(sender) {
+				return nil, ErrPermissionDenied
+			}
+
+			return []string{mention}, nil
+		}
+
+		if _, ok := r.members[mention]; ok && mention != sender {
+			mentions = append(mentions, mention)
+		}
+	}
+
+	return mentions, nil
+}
+
+// mentions reports whether the message mentions username.
+func (m *Message) mentions(username string) bool {
+	if username == m.Sender {
+		return false
+	}
+
+	return slices.ContainsFunc(m.Mentions, func(mention string) bool {
+		return mention == username || mention == MentionHere || mention == MentionRoom
+	})
+}
+
+// notifyMentions notifies the members mentioned in a message of it.
+func (r *Room) notifyMentions(ctx context.Context, message *Message, received *MessageReceivedEvent) {
+	for _, mention := range message.Mentions {
+		event := &MentionEvent{
+			RoomName:    received.RoomName,
+			MessageID:   received.MessageID,
+			SenderName:  received.SenderName,
+			Message:     received.Message,
+			Attachments: received.Attachments,
+			ParentID:    received.ParentID,
+			Mention:     mention,
+		}
+
+		r.notifyMembers(ctx, event, func(m Member) bool {
+			if m.Username() == message.Sender {
+				return false
+			}
+
+			return mention == MentionHere || mention == MentionRoom || mention == m.Username()
+		})
+	}
+}
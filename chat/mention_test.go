@@ -0,0 +1,103 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+)
+
+func (s *Suite) TestMentions() {
+	s.Run("notify mentioned members", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		member3 := &MockMember{username: "user_3"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		_ = s.svc.AddMember(ctx, "test_room", member3)
+
+		// When
+		sent, err := s.svc.SendMessage(ctx, "test_room", member1, "@user_2 @stranger @user_1 see user_3@example.com")
+
+		// Then
+		s.NoError(err)
+		s.Equal([]string{"user_2"}, sent.Mentions)
+		s.Equal(&chat.MentionEvent{
+			RoomName:   "test_room",
+			MessageID:  sent.ID,
+			SenderName: "user_1",
+			Message:    "@user_2 @stranger @user_1 see user_3@example.com",
+			Mention:    "user_2",
+		}, member2.lastNotification)
+		s.IsType(&chat.MessageReceivedEvent{}, member3.lastNotification)
+	})
+
+	s.Run("notify mentioned members who don't follow the thread", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		root, _ := s.svc.SendMessage(ctx, "test_room", member1, "deploy?")
+
+		// When
+		reply, err := s.svc.Reply(ctx, member1, root.ID, "@user_2 what do you think?")
+
+		// Then
+		s.NoError(err)
+		s.Equal(&chat.MentionEvent{
+			RoomName:   "test_room",
+			MessageID:  reply.ID,
+			SenderName: "user_1",
+			Message:    "@user_2 what do you think?",
+			ParentID:   root.ID,
+			Mention:    "user_2",
+		}, member2.lastNotification)
+	})
+
+	s.Run("moderators mention every member", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		owner := &MockMember{username: "owner"}
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", owner)
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+
+		// When
+		sent, err := s.svc.SendMessage(ctx, "test_room", owner, "@here deploying @user_1")
+
+		// Then
+		s.NoError(err)
+		s.Equal([]string{chat.MentionHere}, sent.Mentions)
+		for _, member := range []*MockMember{member1, member2} {
+			s.Equal(&chat.MentionEvent{
+				RoomName:   "test_room",
+				MessageID:  sent.ID,
+				SenderName: "owner",
+				Message:    "@here deploying @user_1",
+				Mention:    chat.MentionHere,
+			}, member.lastNotification)
+		}
+		s.IsType(&chat.MemberJoinedEvent{}, owner.lastNotification)
+	})
+
+	s.Run("other members can't mention every member", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+
+		// When
+		_, err := s.svc.SendMessage(ctx, "test_room", member, "@room lunch?")
+
+		// Then
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+	})
+}
@@ -0,0 +1,114 @@
+package chat
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// Message is a message sent to a room. Deleted messages are kept, with their
+// edit history, for auditing.
+type Message struct {
+	ID     string    `json:"id"`
+	Room   string    `json:"room"`
+	Sender string    `json:"sender"`
+	Text   string    `json:"text"`
+	SentAt time.Time `json:"sent_at"`
+	// Seq is the position of the message in its room, starting at 1. It's
+	// assigned by the node the message is sent to: in replicated mode, messages
+	// sent on two nodes at the same time may share it.
+	Seq uint64 `json:"seq"`
+
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// EditedAt is when Text was last edited, and History holds its previous
+	// versions, oldest first
+	EditedAt  time.Time  `json:"edited_at"`
+	History   []Revision `json:"history,omitempty"`
+	DeletedAt time.Time  `json:"deleted_at"`
+	DeletedBy string     `json:"deleted_by,omitempty"`
+
+	Reactions []Reaction `json:"reactions,omitempty"`
+
+	// ParentID is the ID of the first message of the thread replies belong
+	// to. First messages have the number of Replies of their thread, and the
+	// Followers notified of them.
+	ParentID  string   `json:"parent_id,omitempty"`
+	Replies   int      `json:"replies,omitempty"`
+	Followers []string `json:"followers,omitempty"`
+
+	// Mentions are the usernames of the members mentioned in the message, or
+	// MentionHere or MentionRoom
+	Mentions []string `json:"mentions,omitempty"`
+}
+
+// Revision is a previous version of the text of a message, and when it was
+// written.
+type Revision struct {
+	Text   string    `json:"text"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+func (m *Message) deleted() bool {
+	return !m.DeletedAt.IsZero()
+}
+
+// clone returns a copy of the message, for it to be read without holding the
+// service lock.
+func (m *Message) clone() *Message {
+	c := *m
+	return &c
+}
+
+// newMessageID returns a random message identifier, unique across rooms and
+// nodes.
+func newMessageID() string {
+	id := make([]byte, 6)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+// messageRoom returns the room username is in the message id has been sent to.
+func (r *Service) messageRoom(username string, id string) (*Room, bool) {
+	for _, room := range r.rooms {
+		if _, ok := room.members[username]; !ok {
+			continue
+		}
+
+		if _, ok := room.messages[id]; ok {
+			return room, true
+		}
+	}
+
+	return nil, false
+}
+
+// forwardMessageCall makes a call about a message to the owners of the rooms
+// the member is in, until one of them has the message.
+func (r *Service) forwardMessageCall(ctx context.Context, owners []string, call peerCall) (*peerResult, error) {
+	for _, node := range owners {
+		result, err := r.forward(ctx, node, call)
+		if errors.Is(err, ErrMessageNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}
+
+	return nil, ErrMessageNotFound
+}
+
+// messageResult returns the message of the result of a peer call.
+func messageResult(result *peerResult, err error) (*Message, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Message, nil
+}
@@ -0,0 +1,145 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Query is a parsed search query. Messages match when they contain every term
+// and phrase, and pass every filter set.
+type Query struct {
+	Terms   []string
+	Phrases [][]string
+	// From is the username of the sender, and In the name of the room
+	From string
+	In   string
+	// After and Before bound the time messages were sent at
+	After  time.Time
+	Before time.Time
+}
+
+const dateLayout = "2006-01-02"
+
+// ParseQuery parses a search query made of words, "quoted phrases" and the
+// from:@user, in:#room, after:<date>, before:<date> and on:<date> filters,
+// dates being formatted as 2006-01-02 in UTC.
+func ParseQuery(s string) (Query, error) {
+	var q Query
+
+	for _, token := range splitQuery(s) {
+		if strings.HasPrefix(token, `"`) {
+			if phrase := tokenize(strings.Trim(token, `"`)); len(phrase) > 0 {
+				q.Phrases = append(q.Phrases, phrase)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || value == "" {
+			q.Terms = append(q.Terms, tokenize(token)...)
+			continue
+		}
+
+		switch key {
+		case "from":
+			q.From = strings.TrimPrefix(value, "@")
+		case "in":
+			q.In = strings.TrimPrefix(value, "#")
+		case "after", "before", "on":
+			date, err := time.Parse(dateLayout, value)
+			if err != nil {
+				return Query{}, fmt.Errorf("%w: %s: expected a date formatted as %s", ErrInvalidQuery, token, dateLayout)
+			}
+
+			switch key {
+			case "after":
+				q.After = date.AddDate(0, 0, 1)
+			case "before":
+				q.Before = date
+			case "on":
+				q.After, q.Before = date, date.AddDate(0, 0, 1)
+			}
+		default:
+			q.Terms = append(q.Terms, tokenize(token)...)
+		}
+	}
+
+	if len(q.Terms) == 0 && len(q.Phrases) == 0 && q.From == "" && q.In == "" && q.After.IsZero() && q.Before.IsZero() {
+		return Query{}, fmt.Errorf("%w: empty query", ErrInvalidQuery)
+	}
+
+	return q, nil
+}
+
+// matches reports whether message passes the filters of the query.
+func (q Query) matches(message *Message) bool {
+	if q.From != "" && message.Sender != q.From {
+		return false
+	}
+
+	if q.In != "" && message.Room != q.In {
+		return false
+	}
+
+	if !q.After.IsZero() && message.SentAt.Before(q.After) {
+		return false
+	}
+
+	if !q.Before.IsZero() && !message.SentAt.Before(q.Before) {
+		return false
+	}
+
+	return true
+}
+
+// splitQuery splits a query on whitespace, keeping quoted phrases whole.
+func splitQuery(s string) []string {
+	var (
+		tokens []string
+		token  strings.Builder
+		quoted bool
+	)
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			token.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+
+	return tokens
+}
+
+// tokenize splits text into case-folded words, made of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.Map(fold, text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// fold maps every rune of a case folding orbit (k, K and the Kelvin sign) to
+// the same one.
+func fold(r rune) rune {
+	folded := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < folded {
+			folded = f
+		}
+	}
+
+	return unicode.ToLower(folded)
+}
@@ -0,0 +1,54 @@
+package chat_test
+
+import (
+	"practice-run/chat"
+	"time"
+)
+
+func (s *Suite) TestParseQuery() {
+	s.Run("terms are tokenized and case folded", func() {
+		q, err := chat.ParseQuery("Deploy FAILED, again!")
+
+		s.NoError(err)
+		s.Equal([]string{"deploy", "failed", "again"}, q.Terms)
+	})
+
+	s.Run("phrases", func() {
+		q, err := chat.ParseQuery(`outage "Release Notes" `)
+
+		s.NoError(err)
+		s.Equal([]string{"outage"}, q.Terms)
+		s.Equal([][]string{{"release", "notes"}}, q.Phrases)
+	})
+
+	s.Run("filters", func() {
+		q, err := chat.ParseQuery("from:@user_1 in:#room_1 after:2026-10-01 before:2026-10-19")
+
+		s.NoError(err)
+		s.Empty(q.Terms)
+		s.Equal("user_1", q.From)
+		s.Equal("room_1", q.In)
+		s.Equal(time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), q.After)
+		s.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), q.Before)
+	})
+
+	s.Run("on a day", func() {
+		q, err := chat.ParseQuery("on:2026-10-19")
+
+		s.NoError(err)
+		s.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), q.After)
+		s.Equal(time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), q.Before)
+	})
+
+	s.Run("invalid date", func() {
+		_, err := chat.ParseQuery("after:yesterday")
+
+		s.ErrorIs(err, chat.ErrInvalidQuery)
+	})
+
+	s.Run("empty query", func() {
+		_, err := chat.ParseQuery(` "" !? `)
+
+		s.ErrorIs(err, chat.ErrInvalidQuery)
+	})
+}
@@ -0,0 +1,177 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxReactionLength bounds the number of characters of reactions, which are
+// emojis or short codes like :+1:.
+const maxReactionLength = 32
+
+// Reaction is an emoji members reacted to a message with, and the members who
+// did, in the order they reacted.
+type Reaction struct {
+	Emoji string   `json:"emoji"`
+	Users []string `json:"users"`
+}
+
+// reaction identifies the reaction replicated to the other nodes of a cluster.
+type reaction struct {
+	MessageID string `json:"message_id"`
+	Emoji     string `json:"emoji"`
+}
+
+func validReaction(emoji string) bool {
+	return emoji != "" && utf8.RuneCountInString(emoji) <= maxReactionLength && !strings.ContainsFunc(emoji, unicode.IsSpace)
+}
+
+// React adds the reaction of member to the message id of one of the rooms they
+// are in, and returns the message.
+func (r *Service) React(ctx context.Context, member Member, id string, emoji string) (_ *Message, err error) {
+	ctx, span := startSpan(ctx, "React", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return r.react(ctx, member, id, emoji, true)
+}
+
+// Unreact removes the reaction of member to the message id, and returns the
+// message.
+func (r *Service) Unreact(ctx context.Context, member Member, id string, emoji string) (_ *Message, err error) {
+	ctx, span := startSpan(ctx, "Unreact", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return r.react(ctx, member, id, emoji, false)
+}
+
+func (r *Service) react(ctx context.Context, member Member, id string, emoji string, add bool) (*Message, error) {
+	if !validReaction(emoji) {
+		return nil, ErrInvalidReaction
+	}
+
+	r.mtx.Lock()
+
+	room, ok := r.messageRoom(member.Username(), id)
+	if !ok {
+		owners := r.joinedOwners(ctx, member.Username())
+		r.mtx.Unlock()
+
+		method := peerReact
+		if !add {
+			method = peerUnreact
+		}
+
+		return messageResult(r.forwardMessageCall(ctx, owners, peerCall{
+			Method:   method,
+			Username: member.Username(),
+			Target:   id,
+			Message:  emoji,
+		}))
+	}
+
+	defer r.mtx.Unlock()
+
+	if room.migrating {
+		return nil, ErrRoomMigrating
+	}
+
+	message, err := room.react(ctx, member, id, emoji, add)
+	if err != nil {
+		return nil, fmt.Errorf("failed to react to message: %w", err)
+	}
+
+	return message, nil
+}
+
+func (r *Room) react(ctx context.Context, member Member, id string, emoji string, add bool) (*Message, error) {
+	if !r.can(member.Username(), PermissionPost) {
+		return nil, ErrPermissionDenied
+	}
+
+	count, err := r.setReaction(id, member.Username(), emoji, add)
+	if err != nil {
+		return nil, err
+	}
+
+	var event Event = &ReactionAddedEvent{RoomName: r.Name(), MessageID: id, Username: member.Username(), Emoji: emoji, Count: count}
+	messageType := messageReactionAdded
+	if !add {
+		event = &ReactionRemovedEvent{RoomName: r.Name(), MessageID: id, Username: member.Username(), Emoji: emoji, Count: count}
+		messageType = messageReactionRemoved
+	}
+
+	r.publish(clusterMessage{
+		Type:     messageType,
+		Room:     r.Name(),
+		Username: member.Username(),
+		Reaction: &reaction{MessageID: id, Emoji: emoji},
+	})
+
+	r.broadcastEvent(ctx, event, member)
+
+	r.logger.Debug("reaction changed",
+		slog.String("username", member.Username()),
+		slog.String("message", id),
+		slog.String("emoji", emoji),
+		slog.Bool("added", add),
+	)
+
+	return r.messages[id].clone(), nil
+}
+
+// setReaction adds or removes the reaction of username to the message id, and
+// returns the number of members who reacted with emoji. The message is
+// replaced rather than changed, for the copies returned to callers not to be
+// changed concurrently.
+func (r *Room) setReaction(id string, username string, emoji string, add bool) (int, error) {
+	message, ok := r.messages[id]
+	if !ok || message.deleted() {
+		return 0, ErrMessageNotFound
+	}
+
+	reactions := make([]Reaction, 0, len(message.Reactions)+1)
+	for _, reaction := range message.Reactions {
+		reactions = append(reactions, Reaction{Emoji: reaction.Emoji, Users: slices.Clone(reaction.Users)})
+	}
+
+	i := slices.IndexFunc(reactions, func(reaction Reaction) bool { return reaction.Emoji == emoji })
+	reacted := i >= 0 && slices.Contains(reactions[i].Users, username)
+
+	switch {
+	case add && reacted:
+		return 0, ErrAlreadyReacted
+	case !add && !reacted:
+		return 0, ErrReactionNotFound
+	case add && i < 0:
+		reactions = append(reactions, Reaction{Emoji: emoji, Users: []string{username}})
+		i = len(reactions) - 1
+	case add:
+		reactions[i].Users = append(reactions[i].Users, username)
+	default:
+		reactions[i].Users = slices.DeleteFunc(reactions[i].Users, func(user string) bool { return user == username })
+	}
+
+	count := len(reactions[i].Users)
+	if count == 0 {
+		reactions = slices.Delete(reactions, i, i+1)
+	}
+
+	updated := message.clone()
+	updated.Reactions = reactions
+	if len(reactions) == 0 {
+		updated.Reactions = nil
+	}
+
+	r.messages[id] = updated
+
+	return count, nil
+}
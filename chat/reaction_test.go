@@ -0,0 +1,228 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/broker"
+	"practice-run/chat"
+	"time"
+)
+
+func (s *Suite) TestReact() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "deployed")
+		_, _ = s.svc.React(ctx, member1, sent.ID, "👍")
+
+		// When
+		message, err := s.svc.React(ctx, member2, sent.ID, "👍")
+
+		// Then
+		s.NoError(err)
+		s.Equal([]chat.Reaction{{Emoji: "👍", Users: []string{"user_1", "user_2"}}}, message.Reactions)
+		s.Equal(&chat.ReactionAddedEvent{RoomName: "test_room", MessageID: sent.ID, Username: "user_1", Emoji: "👍", Count: 1}, member2.lastNotification)
+		s.Equal(&chat.ReactionAddedEvent{RoomName: "test_room", MessageID: sent.ID, Username: "user_2", Emoji: "👍", Count: 2}, member1.lastNotification)
+	})
+
+	s.Run("aggregate reactions by emoji", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "deployed")
+		_, _ = s.svc.React(ctx, member2, sent.ID, "🎉")
+		_, _ = s.svc.React(ctx, member1, sent.ID, "👍")
+
+		// When
+		message, err := s.svc.React(ctx, member1, sent.ID, "🎉")
+
+		// Then
+		s.NoError(err)
+		s.Equal([]chat.Reaction{
+			{Emoji: "🎉", Users: []string{"user_2", "user_1"}},
+			{Emoji: "👍", Users: []string{"user_1"}},
+		}, message.Reactions)
+	})
+
+	s.Run("already reacted", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member, "deployed")
+		_, _ = s.svc.React(ctx, member, sent.ID, "👍")
+
+		// When
+		_, err := s.svc.React(ctx, member, sent.ID, "👍")
+
+		// Then
+		s.ErrorIs(err, chat.ErrAlreadyReacted)
+	})
+
+	s.Run("invalid reaction", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member, "deployed")
+
+		// When
+		_, err1 := s.svc.React(ctx, member, sent.ID, "")
+		_, err2 := s.svc.React(ctx, member, sent.ID, "thumbs up")
+
+		// Then
+		s.ErrorIs(err1, chat.ErrInvalidReaction)
+		s.ErrorIs(err2, chat.ErrInvalidReaction)
+	})
+
+	s.Run("read-only room", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		owner := &MockMember{username: "owner"}
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", owner)
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", owner, "v1.2.3 released")
+		s.Require().NoError(s.svc.AddACLRule(ctx, "test_room", owner, chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionPost, Subject: chat.Everyone}))
+
+		// When
+		_, err := s.svc.React(ctx, member, sent.ID, "👍")
+
+		// Then
+		s.ErrorIs(err, chat.ErrPermissionDenied)
+	})
+
+	s.Run("message of a room the member is not in", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "deployed")
+
+		// When
+		_, err := s.svc.React(ctx, member2, sent.ID, "👍")
+
+		// Then
+		s.ErrorIs(err, chat.ErrMessageNotFound)
+	})
+
+	s.Run("react to messages on other nodes", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, "test_room", "user_1")
+		_ = node1.AddMember(ctx, "test_room", member1)
+		s.Eventually(func() bool {
+			rooms := node2.ListRooms(ctx)
+			return len(rooms) == 1 && rooms[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+		_ = node2.AddMember(ctx, "test_room", member2)
+		sent, _ := node1.SendMessage(ctx, "test_room", member1, "deployed")
+		s.Eventually(func() bool {
+			messages, _ := node2.History(ctx, "test_room", member2, 0)
+			return len(messages) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		// When
+		_, err1 := node1.React(ctx, member1, sent.ID, "👍")
+		_, err2 := node2.React(ctx, member2, sent.ID, "👍")
+
+		// Then
+		s.NoError(err1)
+		s.NoError(err2)
+		for _, node := range []*chat.Service{node1, node2} {
+			s.Eventually(func() bool {
+				messages, _ := node.History(ctx, "test_room", member1, 0)
+				return len(messages) == 1 && len(messages[0].Reactions) == 1 && len(messages[0].Reactions[0].Users) == 2
+			}, time.Second, 10*time.Millisecond)
+		}
+	})
+
+	s.Run("react to messages of rooms owned by other nodes", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node1.AddMember(ctx, roomName, member1)
+		_ = node2.AddMember(ctx, roomName, member2)
+		sent, _ := node2.SendMessage(ctx, roomName, member2, "deployed")
+
+		// When
+		message, err := node1.React(ctx, member1, sent.ID, "👍")
+
+		// Then
+		s.NoError(err)
+		s.Equal([]chat.Reaction{{Emoji: "👍", Users: []string{"user_1"}}}, message.Reactions)
+		s.Eventually(func() bool {
+			received := member2.received()
+			return len(received) > 0 && received[len(received)-1].Name() == chat.ReactionAddedEventName
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func (s *Suite) TestUnreact() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "deployed")
+		_, _ = s.svc.React(ctx, member1, sent.ID, "👍")
+
+		// When
+		message, err := s.svc.Unreact(ctx, member1, sent.ID, "👍")
+
+		// Then
+		s.NoError(err)
+		s.Empty(message.Reactions)
+		s.Equal(&chat.ReactionRemovedEvent{RoomName: "test_room", MessageID: sent.ID, Username: "user_1", Emoji: "👍", Count: 0}, member2.lastNotification)
+	})
+
+	s.Run("reaction not found", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member1, "deployed")
+		_, _ = s.svc.React(ctx, member1, sent.ID, "👍")
+
+		// When
+		_, err := s.svc.Unreact(ctx, member2, sent.ID, "👍")
+
+		// Then
+		s.ErrorIs(err, chat.ErrReactionNotFound)
+	})
+}
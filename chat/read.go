@@ -0,0 +1,177 @@
+package chat
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// UnreadCount is the number of messages of a room a member hasn't read.
+type UnreadCount struct {
+	Room  string `json:"room"`
+	Count int    `json:"count"`
+}
+
+// MarkRead records that member has read the messages of the room up to the
+// message id, and returns the number of messages left unread. The sender of
+// the message is notified with a ReadReceiptEvent. Marking an older message
+// as read changes nothing.
+func (r *Service) MarkRead(ctx context.Context, roomName string, member Member, id string) (_ int, err error) {
+	ctx, span := startSpan(ctx, "MarkRead", roomAttribute(roomName), usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		result, err := r.forward(ctx, node, peerCall{Method: peerMarkRead, Room: roomName, Username: member.Username(), Target: id})
+		if err != nil {
+			return 0, err
+		}
+
+		return result.Count, nil
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	room, err := r.room(roomName)
+	if err != nil {
+		return 0, err
+	}
+
+	unread, err := room.markRead(ctx, member, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark as read: %w", err)
+	}
+
+	return unread, nil
+}
+
+// Unread returns the number of unread messages of the rooms the user
+// username is in, or was in when disconnecting, for those that have any, by
+// room name.
+func (r *Service) Unread(ctx context.Context, username string) (_ []UnreadCount, err error) {
+	ctx, span := startSpan(ctx, "Unread", usernameAttribute(username))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	r.mtx.Lock()
+
+	var counts []UnreadCount
+	for _, room := range r.rooms {
+		if _, ok := room.members[username]; !ok && !room.away[username] {
+			continue
+		}
+
+		if count := room.unread(username); count > 0 {
+			counts = append(counts, UnreadCount{Room: room.Name(), Count: count})
+		}
+	}
+
+	r.mtx.Unlock()
+
+	// The rooms owned by other nodes aren't known on connection
+	for _, node := range r.otherNodes(ctx) {
+		result, err := r.forward(ctx, node, peerCall{Method: peerUnread, Username: username})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count unread messages of %s: %w", node, err)
+		}
+
+		counts = append(counts, result.Unread...)
+	}
+
+	slices.SortFunc(counts, func(a, b UnreadCount) int {
+		return cmp.Compare(a.Room, b.Room)
+	})
+
+	return counts, nil
+}
+
+func (r *Room) markRead(ctx context.Context, member Member, id string) (int, error) {
+	if _, ok := r.members[member.Username()]; !ok {
+		return 0, ErrNotRoomMember
+	}
+
+	message, ok := r.messages[id]
+	if !ok || message.deleted() {
+		return 0, ErrMessageNotFound
+	}
+
+	if message.Seq <= r.read[member.Username()] {
+		return r.unread(member.Username()), nil
+	}
+
+	r.read[member.Username()] = message.Seq
+
+	r.publish(clusterMessage{Type: messageMarkedRead, Room: r.Name(), Username: member.Username(), Seq: message.Seq})
+
+	if sender, ok := r.members[message.Sender]; ok && message.Sender != member.Username() {
+		sender.Notify(&ReadReceiptEvent{
+			RoomName:  r.Name(),
+			MessageID: id,
+			Username:  member.Username(),
+		})
+	}
+
+	r.logger.Debug("marked as read", slog.String("username", member.Username()), slog.String("message", id))
+
+	return r.unread(member.Username()), nil
+}
+
+// startReading makes the members joining the room for the first time start
+// with every message sent before read. The positions of members who left
+// are kept for them to find their unread messages when joining again, and
+// counted when connecting again if they left by disconnecting.
+func (r *Room) startReading(username string) {
+	if _, ok := r.read[username]; !ok {
+		r.read[username] = r.seq
+	}
+}
+
+// setAway records whether username left the room by disconnecting.
+func (r *Room) setAway(username string, away bool) {
+	if away {
+		r.away[username] = true
+	} else {
+		delete(r.away, username)
+	}
+}
+
+// setRead moves the read position of username forward to seq.
+func (r *Room) setRead(username string, seq uint64) {
+	r.read[username] = max(r.read[username], seq)
+}
+
+// sequenceIndex returns the index in the sequence of the first message whose
+// sequence number is seq or later.
+func (r *Room) sequenceIndex(seq uint64) int {
+	i, _ := slices.BinarySearchFunc(r.sequence, seq, func(id string, seq uint64) int {
+		return cmp.Compare(r.messages[id].Seq, seq)
+	})
+
+	return i
+}
+
+// unread counts the messages sent by others after the read position of
+// username, as they're delivered: deleted messages and the replies of
+// threads username doesn't follow nor is mentioned in are left out.
+func (r *Room) unread(username string) int {
+	count := 0
+	for _, id := range r.sequence[r.sequenceIndex(r.read[username]+1):] {
+		message := r.messages[id]
+		if message.deleted() || message.Sender == username {
+			continue
+		}
+
+		if root, ok := r.messages[message.ParentID]; ok && !slices.Contains(root.Followers, username) && !message.mentions(username) {
+			continue
+		}
+
+		count++
+	}
+
+	return count
+}
@@ -0,0 +1,288 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/broker"
+	"practice-run/chat"
+	"time"
+)
+
+func (s *Suite) TestMarkRead() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		first, _ := s.svc.SendMessage(ctx, "test_room", member1, "deployed")
+		_, _ = s.svc.SendMessage(ctx, "test_room", member1, "rolled back")
+
+		// When
+		unread, err := s.svc.MarkRead(ctx, "test_room", member2, first.ID)
+
+		// Then
+		s.NoError(err)
+		s.Equal(1, unread)
+		s.Equal(&chat.ReadReceiptEvent{RoomName: "test_room", MessageID: first.ID, Username: "user_2"}, member1.lastNotification)
+	})
+
+	s.Run("ignore older messages", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		first, _ := s.svc.SendMessage(ctx, "test_room", member1, "deployed")
+		second, _ := s.svc.SendMessage(ctx, "test_room", member1, "rolled back")
+		_, _ = s.svc.MarkRead(ctx, "test_room", member2, second.ID)
+		member1.lastNotification = nil
+
+		// When
+		unread, err := s.svc.MarkRead(ctx, "test_room", member2, first.ID)
+
+		// Then
+		s.NoError(err)
+		s.Zero(unread)
+		s.Nil(member1.lastNotification)
+	})
+
+	s.Run("not a room member", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		sent, _ := s.svc.SendMessage(ctx, "test_room", member, "deployed")
+
+		// When
+		_, err := s.svc.MarkRead(ctx, "test_room", &MockMember{username: "user_2"}, sent.ID)
+
+		// Then
+		s.ErrorIs(err, chat.ErrNotRoomMember)
+	})
+
+	s.Run("message not found", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+
+		// When
+		_, err := s.svc.MarkRead(ctx, "test_room", member, "0a1b2c3d4e5f")
+
+		// Then
+		s.ErrorIs(err, chat.ErrMessageNotFound)
+	})
+
+	s.Run("forward to the owner of the room", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node2.CreateRoom(ctx, roomName, "owner")
+		_ = node2.AddMember(ctx, roomName, member2)
+		_ = node1.AddMember(ctx, roomName, member1)
+		sent, _ := node2.SendMessage(ctx, roomName, member2, "deployed")
+
+		// When
+		unread, err := node1.MarkRead(ctx, roomName, member1, sent.ID)
+
+		// Then
+		s.NoError(err)
+		s.Zero(unread)
+		s.Equal(&chat.ReadReceiptEvent{RoomName: roomName, MessageID: sent.ID, Username: "user_1"}, member2.received()[len(member2.received())-1])
+	})
+}
+
+func (s *Suite) TestUnread() {
+	s.Run("count the messages of others", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "room_1", "owner")
+		_, _ = s.svc.CreateRoom(ctx, "room_2", "owner")
+		_, _ = s.svc.CreateRoom(ctx, "room_3", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		for _, roomName := range []string{"room_1", "room_2", "room_3"} {
+			_ = s.svc.AddMember(ctx, roomName, member1)
+			_ = s.svc.AddMember(ctx, roomName, member2)
+		}
+		_, _ = s.svc.SendMessage(ctx, "room_2", member1, "deployed")
+		_, _ = s.svc.SendMessage(ctx, "room_2", member1, "rolled back")
+		_, _ = s.svc.SendMessage(ctx, "room_1", member1, "hello")
+		_, _ = s.svc.SendMessage(ctx, "room_1", member2, "hi")
+		deleted, _ := s.svc.SendMessage(ctx, "room_3", member1, "oops")
+		_, _ = s.svc.DeleteMessage(ctx, member1, deleted.ID)
+
+		// When
+		counts, err := s.svc.Unread(ctx, "user_2")
+
+		// Then
+		s.NoError(err)
+		s.Equal([]chat.UnreadCount{{Room: "room_1", Count: 1}, {Room: "room_2", Count: 2}}, counts)
+	})
+
+	s.Run("start with the messages sent before joining read", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_, _ = s.svc.SendMessage(ctx, "test_room", member1, "deployed")
+
+		// When
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+
+		// Then
+		counts, err := s.svc.Unread(ctx, "user_2")
+		s.NoError(err)
+		s.Empty(counts)
+	})
+
+	s.Run("keep read positions when leaving", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		_ = s.svc.RemoveMember(ctx, "test_room", member2)
+		_, _ = s.svc.SendMessage(ctx, "test_room", member1, "deployed")
+
+		// When
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+
+		// Then
+		counts, err := s.svc.Unread(ctx, "user_2")
+		s.NoError(err)
+		s.Equal([]chat.UnreadCount{{Room: "test_room", Count: 1}}, counts)
+	})
+
+	s.Run("count the rooms left by disconnecting", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "room_1", "owner")
+		_, _ = s.svc.CreateRoom(ctx, "room_2", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.Connect(ctx, member2)
+		for _, roomName := range []string{"room_1", "room_2"} {
+			_ = s.svc.AddMember(ctx, roomName, member1)
+			_ = s.svc.AddMember(ctx, roomName, member2)
+		}
+		_ = s.svc.RemoveMember(ctx, "room_2", member2)
+		_ = s.svc.Disconnect(ctx, member2)
+		_, _ = s.svc.SendMessage(ctx, "room_1", member1, "deployed")
+		_, _ = s.svc.SendMessage(ctx, "room_2", member1, "deployed")
+
+		// When
+		counts, err := s.svc.Unread(ctx, "user_2")
+
+		// Then
+		s.NoError(err)
+		s.Equal([]chat.UnreadCount{{Room: "room_1", Count: 1}}, counts)
+	})
+
+	s.Run("count the rooms of other nodes left by disconnecting", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node2.CreateRoom(ctx, roomName, "owner")
+		_ = node2.AddMember(ctx, roomName, member2)
+		_ = node1.AddMember(ctx, roomName, member1)
+		_ = node1.Disconnect(ctx, member1)
+		s.Eventually(func() bool {
+			return node2.ListRooms(ctx)[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+		_, _ = node2.SendMessage(ctx, roomName, member2, "deployed")
+
+		// When
+		counts, err := node1.Unread(ctx, "user_1")
+
+		// Then
+		s.NoError(err)
+		s.Equal([]chat.UnreadCount{{Room: roomName, Count: 1}}, counts)
+	})
+
+	s.Run("leave out the replies of threads not followed", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		member3 := &MockMember{username: "user_3"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		_ = s.svc.AddMember(ctx, "test_room", member3)
+		root, _ := s.svc.SendMessage(ctx, "test_room", member1, "deployed")
+
+		// When
+		_, _ = s.svc.Reply(ctx, member3, root.ID, "where are the release notes?")
+
+		// Then
+		counts1, _ := s.svc.Unread(ctx, "user_1")
+		counts2, _ := s.svc.Unread(ctx, "user_2")
+		s.Equal([]chat.UnreadCount{{Room: "test_room", Count: 1}}, counts1)
+		s.Equal([]chat.UnreadCount{{Room: "test_room", Count: 1}}, counts2)
+	})
+
+	s.Run("share read positions between nodes", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, "test_room", "user_1")
+		_ = node1.AddMember(ctx, "test_room", member1)
+		s.Eventually(func() bool {
+			rooms := node2.ListRooms(ctx)
+			return len(rooms) == 1 && rooms[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+		_ = node2.AddMember(ctx, "test_room", member2)
+		sent, _ := node2.SendMessage(ctx, "test_room", member2, "deployed")
+		s.Eventually(func() bool {
+			counts, _ := node1.Unread(ctx, "user_1")
+			return len(counts) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		// When
+		_, err := node1.MarkRead(ctx, "test_room", member1, sent.ID)
+
+		// Then
+		s.NoError(err)
+		s.Eventually(func() bool {
+			counts, _ := node2.Unread(ctx, "user_1")
+			return len(counts) == 0
+		}, time.Second, 10*time.Millisecond)
+		s.Eventually(func() bool {
+			received := member2.received()
+			return len(received) > 0 && received[len(received)-1].Name() == chat.ReadReceiptEventName
+		}, time.Second, 10*time.Millisecond)
+	})
+}
@@ -5,19 +5,68 @@ import (
 	"fmt"
 )
 
-func (r *Service) RemoveMember(ctx context.Context, roomName string, member Member) error {
+func (r *Service) RemoveMember(ctx context.Context, roomName string, member Member) (err error) {
+	ctx, span := startSpan(ctx, "RemoveMember", roomAttribute(roomName), usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		_, err = r.forward(ctx, node, peerCall{Method: peerRemoveMember, Room: roomName, Username: member.Username()})
+		if err == nil {
+			r.joinedRemote(member.Username(), roomName, false)
+		}
+		return err
+	}
+
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
-	room, ok := r.rooms[roomName]
+	room, err := r.room(roomName)
+	if err != nil {
+		return err
+	}
+
+	err = room.removeMember(ctx, member, false)
+	if err != nil {
+		return fmt.Errorf("failed to remove member from room: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveUser removes the user username from a room on behalf of an operator.
+// The other members see it leave, and it's notified with a RemovedEvent.
+func (r *Service) RemoveUser(ctx context.Context, roomName string, username string) (err error) {
+	ctx, span := startSpan(ctx, "RemoveUser", roomAttribute(roomName), usernameAttribute(username))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		_, err = r.forward(ctx, node, peerCall{Method: peerRemoveUser, Room: roomName, Target: username})
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	room, err := r.room(roomName)
+	if err != nil {
+		return err
+	}
+
+	member, ok := room.members[username]
 	if !ok {
-		return fmt.Errorf("room not found")
+		return ErrNotRoomMember
 	}
 
-	err := room.removeMember(member)
+	err = room.removeMember(ctx, member, false)
 	if err != nil {
 		return fmt.Errorf("failed to remove member from room: %w", err)
 	}
 
+	member.Notify(&RemovedEvent{RoomName: roomName})
+
 	return nil
 }
@@ -11,7 +11,7 @@ func (s *Suite) TestRemoveMember() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member := &MockMember{username: "user_1"}
 		_ = s.svc.AddMember(ctx, roomName, member)
 
@@ -41,7 +41,7 @@ func (s *Suite) TestRemoveMember() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member := &MockMember{username: "user_1"}
 		_ = s.svc.AddMember(ctx, roomName, member)
 
@@ -64,7 +64,7 @@ func (s *Suite) TestRemoveMember() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member1 := &MockMember{username: "user_1"}
 		member2 := &MockMember{username: "user_2"}
 		member3 := &MockMember{username: "user_3"}
@@ -85,3 +85,37 @@ func (s *Suite) TestRemoveMember() {
 		s.Equal(expected, member3.lastNotification)
 	})
 }
+
+func (s *Suite) TestRemoveUser() {
+	s.Run("remove and notify", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, roomName, member1)
+		_ = s.svc.AddMember(ctx, roomName, member2)
+
+		// When
+		err := s.svc.RemoveUser(ctx, roomName, "user_1")
+
+		// Then
+		s.NoError(err)
+		s.Equal(&chat.RemovedEvent{RoomName: roomName}, member1.lastNotification)
+		s.Equal(&chat.MemberLeftEvent{RoomName: roomName, MemberName: "user_1"}, member2.lastNotification)
+	})
+
+	s.Run("not a member", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+
+		// When
+		err := s.svc.RemoveUser(ctx, roomName, "user_1")
+
+		// Then
+		s.ErrorIs(err, chat.ErrNotRoomMember)
+	})
+}
@@ -0,0 +1,65 @@
+package chat
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// virtualNodes is the number of points each node has on a Ring, for keys to
+// be spread evenly.
+const virtualNodes = 64
+
+// Ring assigns keys to nodes by consistent hashing: adding or removing a node
+// only moves the keys it's assigned, or was.
+type Ring struct {
+	points []uint32
+	nodes  map[uint32]string
+}
+
+func NewRing(nodes []string) *Ring {
+	r := &Ring{nodes: make(map[uint32]string)}
+
+	for _, node := range nodes {
+		for i := 0; i < virtualNodes; i++ {
+			point := hash(node + "#" + strconv.Itoa(i))
+			if _, ok := r.nodes[point]; ok {
+				continue
+			}
+
+			r.points = append(r.points, point)
+			r.nodes[point] = node
+		}
+	}
+
+	sort.Slice(r.points, func(i, j int) bool {
+		return r.points[i] < r.points[j]
+	})
+
+	return r
+}
+
+// Owner returns the node key is assigned to, or an empty string if the ring
+// has no nodes.
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+
+	point := hash(key)
+
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i] >= point
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+
+	return r.nodes[r.points[i]]
+}
+
+func hash(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
@@ -0,0 +1,49 @@
+package chat_test
+
+import (
+	"fmt"
+	"practice-run/chat"
+)
+
+func (s *Suite) TestRing() {
+	s.Run("spread keys over every node", func() {
+		// Given
+		ring := chat.NewRing([]string{"node_1", "node_2", "node_3"})
+		counts := map[string]int{}
+
+		// When
+		for i := 0; i < 3000; i++ {
+			counts[ring.Owner(fmt.Sprintf("room_%d", i))]++
+		}
+
+		// Then
+		s.Len(counts, 3)
+		for _, count := range counts {
+			s.Greater(count, 500)
+		}
+	})
+
+	s.Run("only move the keys of the added node", func() {
+		// Given
+		before := chat.NewRing([]string{"node_1", "node_2"})
+		after := chat.NewRing([]string{"node_1", "node_2", "node_3"})
+		moved := 0
+
+		// When
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprintf("room_%d", i)
+			if before.Owner(key) != after.Owner(key) {
+				moved++
+				s.Equal("node_3", after.Owner(key))
+			}
+		}
+
+		// Then
+		s.Greater(moved, 0)
+		s.Less(moved, 600)
+	})
+
+	s.Run("no nodes", func() {
+		s.Equal("", chat.NewRing(nil).Owner("room"))
+	})
+}
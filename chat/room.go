@@ -1,8 +1,14 @@
 package chat
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
+	"practice-run/metrics"
 	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Member interface {
@@ -13,7 +19,40 @@ type Member interface {
 type Room struct {
 	name string
 
-	members map[string]Member // protected from concurrent access by the service layer
+	// protected from concurrent access by the service layer
+	members map[string]Member
+	acl     ACL
+	// messages are indexed by ID
+	messages map[string]*Message
+	index    *searchIndex
+	// attachments counts the messages not deleted sending each attachment,
+	// by ID, for downloads to be checked without going through the messages
+	attachments map[string]int
+	// typing holds when the typing members were last signaled, by username.
+	// It's neither persisted nor shared with the other nodes.
+	typing map[string]time.Time
+	// seq is the sequence number of the last message sent, and read the
+	// sequence number of the last message each member read, by username.
+	// sequence holds the IDs of the messages by sequence number, for unread
+	// messages to be found without going through the read ones. In
+	// replicated mode, the messages sharing a sequence number are read
+	// together.
+	seq      uint64
+	read     map[string]uint64
+	sequence []string
+	// away holds the users who were in the room when disconnecting, for
+	// their unread messages to be counted when connecting again
+	away map[string]bool
+
+	// migrating is set while the room is handed over to another node of a
+	// sharded cluster, during which it only changes by members
+	// disconnecting, for no other change to be lost
+	migrating bool
+
+	groups  GroupResolver
+	cluster *cluster
+	journal *journal
+	logger  *slog.Logger
 }
 
 func (r *Room) Name() string {
@@ -29,30 +68,91 @@ func (r *Room) getMembers() ([]Member, error) {
 	return members, nil
 }
 
-func (r *Room) addMember(member Member) error {
+func (r *Room) can(username string, permission Permission) bool {
+	return r.acl.Allows(username, r.groups.Groups(username), permission)
+}
+
+// canBeInvited returns whether username may be invited to the room: unless
+// it's denied to join as a user or a group member, rules denying everyone to
+// join being those of invite-only rooms.
+func (r *Room) canBeInvited(username string) bool {
+	allowed, specificity := r.acl.decide(username, r.groups.Groups(username), PermissionJoin)
+
+	// Rules for everyone have a specificity of 1, see Rule.specificity
+	return allowed || specificity == 1
+}
+
+func (r *Room) addMember(ctx context.Context, member Member) error {
 	_, ok := r.members[member.Username()]
 	if ok {
-		return fmt.Errorf("member already exists")
+		return ErrMemberAlreadyExists
+	}
+
+	if !r.can(member.Username(), PermissionJoin) {
+		return ErrPermissionDenied
+	}
+
+	r.join(ctx, member)
+
+	return nil
+}
+
+func (r *Room) inviteMember(ctx context.Context, inviter, invitee Member) error {
+	if _, ok := r.members[inviter.Username()]; !ok {
+		return ErrNotRoomMember
+	}
+
+	if !r.can(inviter.Username(), PermissionInvite) {
+		return ErrPermissionDenied
 	}
 
+	if _, ok := r.members[invitee.Username()]; ok {
+		return ErrMemberAlreadyExists
+	}
+
+	if !r.canBeInvited(invitee.Username()) {
+		return ErrPermissionDenied
+	}
+
+	r.join(ctx, invitee)
+
+	invitee.Notify(&InvitedEvent{
+		RoomName:    r.Name(),
+		InviterName: inviter.Username(),
+	})
+
+	return nil
+}
+
+func (r *Room) join(ctx context.Context, member Member) {
 	r.members[member.Username()] = member
+	r.startReading(member.Username())
+	r.setAway(member.Username(), false)
 
-	r.broadcastEvent(&MemberJoinedEvent{
+	r.publish(clusterMessage{Type: messageMemberJoined, Room: r.Name(), Username: member.Username()})
+
+	r.logger.Debug("member joined", slog.String("username", member.Username()))
+
+	r.broadcastEvent(ctx, &MemberJoinedEvent{
 		RoomName:   r.Name(),
 		MemberName: member.Username(),
 	}, member)
-
-	return nil
 }
 
-func (r *Room) removeMember(member Member) error {
+// removeMember removes member from the room, away if it's disconnecting.
+func (r *Room) removeMember(ctx context.Context, member Member, away bool) error {
 	if _, ok := r.members[member.Username()]; !ok {
-		return fmt.Errorf("not a room member")
+		return ErrNotRoomMember
 	}
 
 	delete(r.members, member.Username())
+	r.setAway(member.Username(), away)
 
-	r.broadcastEvent(&MemberLeftEvent{
+	r.publish(clusterMessage{Type: messageMemberLeft, Room: r.Name(), Username: member.Username(), Away: away})
+
+	r.logger.Debug("member left", slog.String("username", member.Username()))
+
+	r.broadcastEvent(ctx, &MemberLeftEvent{
 		RoomName:   r.Name(),
 		MemberName: member.Username(),
 	}, member)
@@ -60,29 +160,319 @@ func (r *Room) removeMember(member Member) error {
 	return nil
 }
 
-func (r *Room) sendMessage(member Member, message string) error {
+func (r *Room) replaceMember(previous, member Member) {
+	if r.members[previous.Username()] == previous {
+		r.members[member.Username()] = member
+	}
+}
+
+// sendMessage sends message to the room, or to the thread of the message
+// parentID when given. Replies are sent to the followers of the thread only,
+// and the members mentioned in the message are sent a MentionEvent instead.
+func (r *Room) sendMessage(ctx context.Context, member Member, message string, attachments []Attachment, parentID string) (*Message, error) {
 	_, ok := r.members[member.Username()]
 	if !ok {
-		return fmt.Errorf("not a room member")
+		return nil, ErrNotRoomMember
+	}
+
+	if !r.can(member.Username(), PermissionPost) {
+		return nil, ErrPermissionDenied
+	}
+
+	mentions, err := r.mentions(member.Username(), message)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &Message{
+		ID:     newMessageID(),
+		Room:   r.Name(),
+		Sender: member.Username(),
+		Text:   message,
+		SentAt: time.Now().UTC(),
+		Seq:    r.seq + 1,
+
+		Attachments: attachments,
+		Mentions:    mentions,
+	}
+
+	if parentID == "" {
+		stored.Followers = []string{member.Username()}
+	} else {
+		root, err := r.threadRoot(parentID)
+		if err != nil {
+			return nil, err
+		}
+
+		stored.ParentID = root.ID
 	}
 
-	r.broadcastEvent(&MessageReceivedEvent{
+	r.store(stored)
+
+	// Sending the message ends the typing indicator, for the next signal not
+	// to be throttled
+	delete(r.typing, member.Username())
+
+	r.publish(clusterMessage{Type: messageMessageSent, Room: r.Name(), Message: stored})
+
+	event := &MessageReceivedEvent{
+		RoomName:    r.Name(),
+		MessageID:   stored.ID,
+		SenderName:  member.Username(),
+		Message:     message,
+		Attachments: attachments,
+	}
+
+	var followers []string
+	if stored.ParentID != "" {
+		root := r.messages[stored.ParentID]
+		event.ParentID, event.Replies = root.ID, root.Replies
+		followers = root.Followers
+	}
+
+	r.notifyMembers(ctx, event, func(m Member) bool {
+		return m.Username() != member.Username() &&
+			(stored.ParentID == "" || slices.Contains(followers, m.Username())) &&
+			!stored.mentions(m.Username())
+	})
+
+	r.notifyMentions(ctx, stored, event)
+
+	metrics.MessagesSent.Inc()
+	r.logger.Debug("message sent", slog.String("username", member.Username()))
+
+	return stored.clone(), nil
+}
+
+// store keeps message and indexes it for search, unless it's already kept.
+// Replies are counted in the first message of their thread, whose sender
+// follows it.
+func (r *Room) store(message *Message) {
+	if r.keep(message) {
+		r.countReply(message)
+	}
+}
+
+// restoreMessages keeps the messages of a snapshot. Their threads already
+// count the replies they carry, only those of threads kept before are
+// counted.
+func (r *Room) restoreMessages(messages []*Message) {
+	restored := make(map[string]bool, len(messages))
+	for _, message := range messages {
+		if r.keep(message) {
+			restored[message.ID] = true
+		}
+	}
+
+	for _, message := range messages {
+		if restored[message.ID] && message.ParentID != "" && !restored[message.ParentID] {
+			r.countReply(message)
+		}
+	}
+}
+
+// keep keeps message and indexes it for search, reporting whether it wasn't
+// already kept.
+func (r *Room) keep(message *Message) bool {
+	if _, ok := r.messages[message.ID]; ok {
+		return false
+	}
+
+	r.messages[message.ID] = message
+	r.seq = max(r.seq, message.Seq)
+	if !message.deleted() {
+		r.index.add(message)
+		r.countAttachments(message, 1)
+	}
+
+	// Messages mostly arrive in order, and are then appended
+	i := r.sequenceIndex(message.Seq + 1)
+	r.sequence = slices.Insert(r.sequence, i, message.ID)
+
+	return true
+}
+
+// countReply counts message in the first message of its thread, whose
+// followers its sender joins.
+func (r *Room) countReply(message *Message) {
+	root, ok := r.messages[message.ParentID]
+	if !ok || message.deleted() {
+		return
+	}
+
+	updated := root.clone()
+	updated.Replies++
+	if !slices.Contains(root.Followers, message.Sender) {
+		updated.Followers = append(slices.Clone(root.Followers), message.Sender)
+	}
+
+	r.messages[root.ID] = updated
+}
+
+// replace replaces a message kept by its edited or deleted version.
+func (r *Room) replace(message *Message) {
+	previous, ok := r.messages[message.ID]
+	if ok && !previous.deleted() {
+		r.index.remove(previous)
+		r.countAttachments(previous, -1)
+	}
+
+	r.messages[message.ID] = message
+	if !message.deleted() {
+		r.index.add(message)
+		r.countAttachments(message, 1)
+	}
+
+	if !ok {
+		r.sequence = slices.Insert(r.sequence, r.sequenceIndex(message.Seq+1), message.ID)
+	}
+
+	// Deleted replies are no longer counted
+	if root, found := r.messages[message.ParentID]; found && ok && !previous.deleted() && message.deleted() {
+		updated := root.clone()
+		updated.Replies--
+		r.messages[root.ID] = updated
+	}
+}
+
+// moderates reports whether username may delete the messages of others.
+func (r *Room) moderates(username string) bool {
+	return r.can(username, PermissionModerate) || r.can(username, PermissionManage)
+}
+
+// editMessage replaces the text of the message id sent by member, and its
+// mentions by those of the new text.
+func (r *Room) editMessage(ctx context.Context, member Member, id string, text string) (*Message, error) {
+	message, ok := r.messages[id]
+	if !ok || message.deleted() {
+		return nil, ErrMessageNotFound
+	}
+
+	if message.Sender != member.Username() {
+		return nil, ErrPermissionDenied
+	}
+
+	if !r.can(member.Username(), PermissionPost) {
+		return nil, ErrPermissionDenied
+	}
+
+	mentions, err := r.mentions(member.Username(), text)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := Revision{Text: message.Text, SentAt: message.SentAt}
+	if !message.EditedAt.IsZero() {
+		previous.SentAt = message.EditedAt
+	}
+
+	edited := message.clone()
+	edited.Text = text
+	edited.Mentions = mentions
+	edited.EditedAt = time.Now().UTC()
+	edited.History = append(slices.Clone(message.History), previous)
+
+	r.replace(edited)
+
+	r.publish(clusterMessage{Type: messageMessageUpdated, Room: r.Name(), Message: edited})
+
+	r.broadcastEvent(ctx, &MessageEditedEvent{
 		RoomName:   r.Name(),
+		MessageID:  id,
 		SenderName: member.Username(),
-		Message:    message,
+		Message:    text,
 	}, member)
 
-	return nil
+	r.logger.Debug("message edited", slog.String("username", member.Username()), slog.String("message", id))
+
+	return edited.clone(), nil
 }
 
-func (r *Room) broadcastEvent(event Event, exclude ...Member) {
-	for _, member := range r.members {
-		if slices.IndexFunc(exclude, func(i Member) bool {
+func (r *Room) deleteMessage(ctx context.Context, member Member, id string) (*Message, error) {
+	message, ok := r.messages[id]
+	if !ok || message.deleted() {
+		return nil, ErrMessageNotFound
+	}
+
+	if message.Sender != member.Username() && !r.moderates(member.Username()) {
+		return nil, ErrPermissionDenied
+	}
+
+	deleted := message.clone()
+	deleted.DeletedAt = time.Now().UTC()
+	deleted.DeletedBy = member.Username()
+
+	r.replace(deleted)
+
+	r.publish(clusterMessage{Type: messageMessageUpdated, Room: r.Name(), Message: deleted})
+
+	r.broadcastEvent(ctx, &MessageDeletedEvent{
+		RoomName:  r.Name(),
+		MessageID: id,
+		DeletedBy: member.Username(),
+	}, member)
+
+	r.logger.Info("message deleted",
+		slog.String("username", member.Username()),
+		slog.String("message", id),
+		slog.String("sender", message.Sender),
+	)
+
+	return deleted.clone(), nil
+}
+
+// broadcastEvent notifies every member but those in exclude of event.
+func (r *Room) broadcastEvent(ctx context.Context, event Event, exclude ...Member) {
+	r.notifyMembers(ctx, event, func(member Member) bool {
+		return slices.IndexFunc(exclude, func(i Member) bool {
 			return i.Username() == member.Username()
-		}) != -1 {
+		}) == -1
+	})
+}
+
+// notifyMembers notifies the members for which notified returns true of
+// event. The members connected to other nodes are notified with a single
+// message.
+func (r *Room) notifyMembers(ctx context.Context, event Event, notified func(Member) bool) {
+	_, span := tracer.Start(ctx, "chat.broadcast", trace.WithAttributes(
+		roomAttribute(r.Name()),
+		attribute.String("chat.event", event.Name()),
+	))
+	defer span.End()
+
+	start := time.Now()
+	defer func() {
+		metrics.BroadcastDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	var remote []string
+	recipients := 0
+
+	for _, member := range r.members {
+		if !notified(member) {
+			continue
+		}
+
+		recipients++
+
+		if isRemote(member) {
+			remote = append(remote, member.Username())
 			continue
 		}
 
 		member.Notify(event)
 	}
+
+	span.SetAttributes(attribute.Int("chat.recipients", recipients))
+
+	r.cluster.publishEvent(event, remote)
+}
+
+// notifyLocal notifies the members connected to this node of event.
+func (r *Room) notifyLocal(event Event) {
+	for _, member := range r.members {
+		if !isRemote(member) {
+			member.Notify(event)
+		}
+	}
 }
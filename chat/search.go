@@ -0,0 +1,187 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// searchLimit is the maximum number of results returned by a search.
+const searchLimit = 20
+
+type SearchResult struct {
+	Message *Message `json:"message"`
+	Score   float64  `json:"score"`
+}
+
+// searchIndex is the inverted index of the messages of a room.
+type searchIndex struct {
+	// postings holds the positions of each term in each message, by message ID
+	postings map[string]map[string][]int
+	messages int
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{postings: make(map[string]map[string][]int)}
+}
+
+func (i *searchIndex) add(message *Message) {
+	for position, term := range tokenize(message.Text) {
+		if i.postings[term] == nil {
+			i.postings[term] = make(map[string][]int)
+		}
+
+		i.postings[term][message.ID] = append(i.postings[term][message.ID], position)
+	}
+
+	i.messages++
+}
+
+func (i *searchIndex) remove(message *Message) {
+	for _, term := range tokenize(message.Text) {
+		delete(i.postings[term], message.ID)
+		if len(i.postings[term]) == 0 {
+			delete(i.postings, term)
+		}
+	}
+
+	i.messages--
+}
+
+// search returns the messages matching q, scored by TF-IDF. Queries without
+// terms or phrases match every message passing their filters, scored 0.
+func (i *searchIndex) search(q Query, messages map[string]*Message) []SearchResult {
+	terms := slices.Clone(q.Terms)
+	for _, phrase := range q.Phrases {
+		terms = append(terms, phrase...)
+	}
+
+	var candidates []string
+	if len(terms) == 0 {
+		for id := range messages {
+			candidates = append(candidates, id)
+		}
+	} else {
+		for id := range i.postings[terms[0]] {
+			candidates = append(candidates, id)
+		}
+	}
+
+	var results []SearchResult
+
+candidates:
+	for _, id := range candidates {
+		message, ok := messages[id]
+		if !ok || message.deleted() || !q.matches(message) {
+			continue
+		}
+
+		score := 0.0
+
+		for _, term := range q.Terms {
+			positions := i.postings[term][id]
+			if len(positions) == 0 {
+				continue candidates
+			}
+
+			score += (1 + math.Log(float64(len(positions)))) * i.idf(term)
+		}
+
+		for _, phrase := range q.Phrases {
+			occurrences := i.occurrences(phrase, id)
+			if occurrences == 0 {
+				continue candidates
+			}
+
+			for _, term := range phrase {
+				score += (1 + math.Log(float64(occurrences))) * i.idf(term)
+			}
+		}
+
+		results = append(results, SearchResult{Message: message.clone(), Score: score})
+	}
+
+	return results
+}
+
+// idf is the inverse document frequency of term, which weighs rare terms
+// over common ones.
+func (i *searchIndex) idf(term string) float64 {
+	return math.Log(1 + float64(i.messages)/float64(1+len(i.postings[term])))
+}
+
+// occurrences returns the number of times phrase occurs in a message.
+func (i *searchIndex) occurrences(phrase []string, id string) int {
+	count := 0
+
+positions:
+	for _, start := range i.postings[phrase[0]][id] {
+		for offset, term := range phrase[1:] {
+			if !slices.Contains(i.postings[term][id], start+offset+1) {
+				continue positions
+			}
+		}
+
+		count++
+	}
+
+	return count
+}
+
+// Search returns the messages of the rooms member is in matching query, as
+// parsed by ParseQuery, best first.
+func (r *Service) Search(ctx context.Context, member Member, query string) (_ []SearchResult, err error) {
+	ctx, span := startSpan(ctx, "Search", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mtx.Lock()
+
+	var results []SearchResult
+	for _, room := range r.rooms {
+		if _, ok := room.members[member.Username()]; !ok {
+			continue
+		}
+
+		if q.In != "" && room.Name() != q.In {
+			continue
+		}
+
+		results = append(results, room.index.search(q, room.messages)...)
+	}
+
+	owners := r.joinedOwners(ctx, member.Username())
+
+	r.mtx.Unlock()
+
+	for _, node := range owners {
+		result, err := r.forward(ctx, node, peerCall{Method: peerSearch, Username: member.Username(), Message: query})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search rooms of %s: %w", node, err)
+		}
+
+		results = append(results, result.Results...)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		return results[i].Message.SentAt.After(results[j].Message.SentAt)
+	})
+
+	if len(results) > searchLimit {
+		results = results[:searchLimit]
+	}
+
+	return results, nil
+}
@@ -0,0 +1,164 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/broker"
+	"practice-run/chat"
+	"time"
+)
+
+func (s *Suite) TestSearch() {
+	s.Run("rank matching messages", func() {
+		// Given
+		ctx := context.Background()
+		member := &MockMember{username: "user_1"}
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "user_1")
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		_, _ = s.svc.SendMessage(ctx, "test_room", member, "the deploy is done")
+		_, _ = s.svc.SendMessage(ctx, "test_room", member, "Deploy failed, retrying the deploy")
+		_, _ = s.svc.SendMessage(ctx, "test_room", member, "lunch?")
+
+		// When
+		results, err := s.svc.Search(ctx, member, "DEPLOY")
+
+		// Then
+		s.NoError(err)
+		s.Len(results, 2)
+		s.Equal("Deploy failed, retrying the deploy", results[0].Message.Text)
+		s.Equal("the deploy is done", results[1].Message.Text)
+		s.Greater(results[0].Score, results[1].Score)
+		s.NotEmpty(results[0].Message.ID)
+		s.Equal("test_room", results[0].Message.Room)
+		s.Equal("user_1", results[0].Message.Sender)
+	})
+
+	s.Run("match phrases", func() {
+		// Given
+		ctx := context.Background()
+		member := &MockMember{username: "user_1"}
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "user_1")
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		_, _ = s.svc.SendMessage(ctx, "test_room", member, "notes on the release")
+		_, _ = s.svc.SendMessage(ctx, "test_room", member, "release notes are out")
+
+		// When
+		results, err := s.svc.Search(ctx, member, `"release notes"`)
+
+		// Then
+		s.NoError(err)
+		s.Len(results, 1)
+		s.Equal("release notes are out", results[0].Message.Text)
+	})
+
+	s.Run("apply filters", func() {
+		// Given
+		ctx := context.Background()
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_, _ = s.svc.CreateRoom(ctx, "room_1", "user_1")
+		_, _ = s.svc.CreateRoom(ctx, "room_2", "user_1")
+		_ = s.svc.AddMember(ctx, "room_1", member1)
+		_ = s.svc.AddMember(ctx, "room_1", member2)
+		_ = s.svc.AddMember(ctx, "room_2", member1)
+		_ = s.svc.AddMember(ctx, "room_2", member2)
+		_, _ = s.svc.SendMessage(ctx, "room_1", member1, "hello")
+		_, _ = s.svc.SendMessage(ctx, "room_1", member2, "hello")
+		_, _ = s.svc.SendMessage(ctx, "room_2", member2, "hello")
+		today := time.Now().UTC().Format("2006-01-02")
+
+		// When
+		results, err := s.svc.Search(ctx, member1, "hello from:@user_2 in:#room_1 on:"+today)
+		none, _ := s.svc.Search(ctx, member1, "hello after:"+today)
+
+		// Then
+		s.NoError(err)
+		s.Len(results, 1)
+		s.Equal("room_1", results[0].Message.Room)
+		s.Equal("user_2", results[0].Message.Sender)
+		s.Empty(none)
+	})
+
+	s.Run("only search the rooms of the member", func() {
+		// Given
+		ctx := context.Background()
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_, _ = s.svc.CreateRoom(ctx, "room_1", "user_1")
+		_ = s.svc.AddMember(ctx, "room_1", member1)
+		_, _ = s.svc.SendMessage(ctx, "room_1", member1, "secret plans")
+
+		// When
+		results, err := s.svc.Search(ctx, member2, "plans")
+
+		// Then
+		s.NoError(err)
+		s.Empty(results)
+	})
+
+	s.Run("invalid query", func() {
+		// Given
+		ctx := context.Background()
+		member := &MockMember{username: "user_1"}
+
+		// When
+		_, err := s.svc.Search(ctx, member, "before:tomorrow")
+
+		// Then
+		s.ErrorIs(err, chat.ErrInvalidQuery)
+	})
+
+	s.Run("search messages sent on other nodes", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, "test_room", "user_1")
+		_ = node1.AddMember(ctx, "test_room", member1)
+		s.Eventually(func() bool {
+			rooms := node2.ListRooms(ctx)
+			return len(rooms) == 1 && rooms[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+		_ = node2.AddMember(ctx, "test_room", member2)
+
+		// When
+		_, _ = node1.SendMessage(ctx, "test_room", member1, "hello from node 1")
+
+		// Then
+		s.Eventually(func() bool {
+			results, err := node2.Search(ctx, member2, "hello")
+			return err == nil && len(results) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	s.Run("search rooms owned by other nodes", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node1.AddMember(ctx, roomName, member1)
+		_ = node2.AddMember(ctx, roomName, member2)
+		_, _ = node2.SendMessage(ctx, roomName, member2, "hello from node 2")
+
+		// When
+		results, err := node1.Search(ctx, member1, "hello")
+
+		// Then
+		s.NoError(err)
+		s.Len(results, 1)
+		s.Equal(roomName, results[0].Message.Room)
+		s.Equal("user_2", results[0].Message.Sender)
+	})
+}
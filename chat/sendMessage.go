@@ -5,19 +5,50 @@ import (
 	"fmt"
 )
 
-func (r *Service) SendMessage(ctx context.Context, roomName string, member Member, message string) error {
+// SendMessage sends message to the room, along with the attachments of the
+// given IDs, and returns the message sent.
+func (r *Service) SendMessage(ctx context.Context, roomName string, member Member, message string, attachments ...string) (_ *Message, err error) {
+	ctx, span := startSpan(ctx, "SendMessage", roomAttribute(roomName), usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	resolved, err := r.resolveAttachments(ctx, member.Username(), attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.sendMessage(ctx, roomName, member, message, resolved)
+}
+
+func (r *Service) sendMessage(ctx context.Context, roomName string, member Member, message string, attachments []Attachment) (*Message, error) {
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		result, err := r.forward(ctx, node, peerCall{
+			Method:      peerSendMessage,
+			Room:        roomName,
+			Username:    member.Username(),
+			Message:     message,
+			Attachments: attachments,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return result.Message, nil
+	}
+
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
-	room, ok := r.rooms[roomName]
-	if !ok {
-		return fmt.Errorf("room not found")
+	room, err := r.room(roomName)
+	if err != nil {
+		return nil, err
 	}
 
-	err := room.sendMessage(member, message)
+	sent, err := room.sendMessage(ctx, member, message, attachments, "")
 	if err != nil {
-		return fmt.Errorf("failed to send message to room: %w", err)
+		return nil, fmt.Errorf("failed to send message to room: %w", err)
 	}
 
-	return nil
+	return sent, nil
 }
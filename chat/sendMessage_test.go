@@ -11,13 +11,13 @@ func (s *Suite) TestSendMessage() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member := &MockMember{username: "user_1"}
 		_ = s.svc.AddMember(ctx, roomName, member)
 		message := "hello, world!"
 
 		// When
-		err := s.svc.SendMessage(ctx, roomName, member, message)
+		_, err := s.svc.SendMessage(ctx, roomName, member, message)
 
 		// Then
 		s.NoError(err)
@@ -31,7 +31,7 @@ func (s *Suite) TestSendMessage() {
 		message := "hello, world!"
 
 		// When
-		err := s.svc.SendMessage(ctx, roomName, member, message)
+		_, err := s.svc.SendMessage(ctx, roomName, member, message)
 
 		// Then
 		s.Error(err)
@@ -41,12 +41,12 @@ func (s *Suite) TestSendMessage() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member := &MockMember{username: "user_1"}
 		message := "hello, world!"
 
 		// When
-		err := s.svc.SendMessage(ctx, roomName, member, message)
+		_, err := s.svc.SendMessage(ctx, roomName, member, message)
 
 		// Then
 		s.Error(err)
@@ -56,7 +56,7 @@ func (s *Suite) TestSendMessage() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member := &MockMember{username: "user_1"}
 		_ = s.svc.AddMember(ctx, roomName, member)
 		message := "hello, world!"
@@ -67,7 +67,7 @@ func (s *Suite) TestSendMessage() {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				_ = s.svc.SendMessage(ctx, roomName, member, message)
+				_, _ = s.svc.SendMessage(ctx, roomName, member, message)
 			}()
 		}
 		wg.Wait()
@@ -80,7 +80,7 @@ func (s *Suite) TestSendMessage() {
 		// Given
 		ctx := context.Background()
 		roomName := "test_room"
-		_, _ = s.svc.CreateRoom(ctx, roomName)
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
 		member1 := &MockMember{username: "user_1"}
 		member2 := &MockMember{username: "user_2"}
 		member3 := &MockMember{username: "user_3"}
@@ -90,11 +90,12 @@ func (s *Suite) TestSendMessage() {
 		message := "hello, world!"
 
 		// When
-		_ = s.svc.SendMessage(ctx, roomName, member1, message)
+		sent, _ := s.svc.SendMessage(ctx, roomName, member1, message)
 
 		// Then
 		expected := &chat.MessageReceivedEvent{
 			RoomName:   roomName,
+			MessageID:  sent.ID,
 			SenderName: member1.username,
 			Message:    message,
 		}
@@ -102,4 +103,87 @@ func (s *Suite) TestSendMessage() {
 		s.Equal(expected, member2.lastNotification)
 		s.Equal(expected, member3.lastNotification)
 	})
+
+	s.Run("with attachments", func() {
+		// Given
+		ctx := context.Background()
+		attachment := chat.Attachment{ID: "a1b2c3", Name: "server.log", Size: 18, Type: "text/plain", URL: "/attachments/a1b2c3"}
+		svc := chat.NewService(chat.WithAttachmentResolver(MockAttachments{attachment.ID: attachment}))
+		_, _ = svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = svc.AddMember(ctx, "test_room", member1)
+		_ = svc.AddMember(ctx, "test_room", member2)
+
+		// When
+		sent, err := svc.SendMessage(ctx, "test_room", member1, "logs", attachment.ID)
+
+		// Then
+		s.NoError(err)
+		s.Equal(&chat.MessageReceivedEvent{
+			RoomName:    "test_room",
+			MessageID:   sent.ID,
+			SenderName:  "user_1",
+			Message:     "logs",
+			Attachments: []chat.Attachment{attachment},
+		}, member2.lastNotification)
+		allowed, _ := svc.CanDownload(ctx, "user_2", attachment.ID)
+		s.True(allowed)
+		allowed, _ = svc.CanDownload(ctx, "user_3", attachment.ID)
+		s.False(allowed)
+	})
+
+	s.Run("attachments of others", func() {
+		// Given
+		ctx := context.Background()
+		attachment := chat.Attachment{ID: "a1b2c3", Name: "server.log", Size: 18, Type: "text/plain", URL: "/attachments/a1b2c3"}
+		svc := chat.NewService(chat.WithAttachmentResolver(MockAttachments{attachment.ID: attachment}))
+		_, _ = svc.CreateRoom(ctx, "room_1", "owner")
+		_, _ = svc.CreateRoom(ctx, "room_2", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		member3 := &MockMember{username: "user_3"}
+		_ = svc.AddMember(ctx, "room_1", member1)
+		_ = svc.AddMember(ctx, "room_1", member2)
+		_ = svc.AddMember(ctx, "room_2", member2)
+		_ = svc.AddMember(ctx, "room_2", member3)
+		_, _ = svc.SendMessage(ctx, "room_1", member1, "logs", attachment.ID)
+
+		// When
+		_, err1 := svc.SendMessage(ctx, "room_2", member3, "logs", attachment.ID)
+		_, err2 := svc.SendMessage(ctx, "room_2", member2, "logs", attachment.ID)
+
+		// Then
+		s.ErrorIs(err1, chat.ErrAttachmentNotFound)
+		s.NoError(err2)
+		allowed, _ := svc.CanDownload(ctx, "user_3", attachment.ID)
+		s.True(allowed)
+	})
+
+	s.Run("unknown attachment", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+
+		// When
+		_, err := s.svc.SendMessage(ctx, "test_room", member, "logs", "a1b2c3")
+
+		// Then
+		s.ErrorIs(err, chat.ErrAttachmentNotFound)
+	})
+}
+
+// MockAttachments resolves the attachments it has by ID, all uploaded by
+// user_1.
+type MockAttachments map[string]chat.Attachment
+
+func (m MockAttachments) Attachment(_ context.Context, username string, id string) (*chat.Attachment, bool, error) {
+	attachment, ok := m[id]
+	if !ok {
+		return nil, false, chat.ErrAttachmentNotFound
+	}
+
+	return &attachment, username == "user_1", nil
 }
@@ -1,17 +1,96 @@
 package chat
 
 import (
+	"log/slog"
 	"sync"
+	"time"
 )
 
+// GroupResolver returns the groups a user belongs to, which ACL rules can
+// refer to.
+type GroupResolver interface {
+	Groups(username string) []string
+}
+
+type noGroups struct{}
+
+func (noGroups) Groups(string) []string {
+	return nil
+}
+
+type Option func(*Service)
+
+func WithGroupResolver(groups GroupResolver) Option {
+	return func(s *Service) {
+		s.groups = groups
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
 type Service struct {
-	mtx   sync.Mutex
-	rooms map[string]*Room
+	mtx     sync.Mutex
+	rooms   map[string]*Room
+	members map[string]Member // connected members
+	// connectedAt records when each connected member connected
+	connectedAt map[string]time.Time
+	groups      GroupResolver
+	attachments AttachmentResolver
+	logger      *slog.Logger
+	// cluster is nil unless the service is a node of a replicated cluster,
+	// and sharding unless it's a node of a sharded one
+	cluster  *cluster
+	sharding *sharding
+	// journal is nil unless the state is recorded with WithJournal
+	journal *journal
 }
 
-func NewService() *Service {
-	return &Service{
-		mtx:   sync.Mutex{},
-		rooms: make(map[string]*Room),
+func NewService(options ...Option) *Service {
+	s := &Service{
+		mtx:         sync.Mutex{},
+		rooms:       make(map[string]*Room),
+		members:     make(map[string]Member),
+		connectedAt: make(map[string]time.Time),
+		groups:      noGroups{},
+		attachments: noAttachments{},
+		logger:      slog.Default(),
+	}
+
+	for _, option := range options {
+		option(s)
 	}
+
+	if s.cluster != nil {
+		s.cluster.logger = s.logger
+	}
+
+	if s.sharding != nil {
+		s.sharding.logger = s.logger
+	}
+
+	if s.journal != nil {
+		s.journal.logger = s.logger
+		s.journal.snapshot = s.roomSnapshots
+	}
+
+	return s
+}
+
+// room returns the room roomName, unless it's being handed over to another
+// node of a sharded cluster.
+func (r *Service) room(roomName string) (*Room, error) {
+	room, ok := r.rooms[roomName]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	if room.migrating {
+		return nil, ErrRoomMigrating
+	}
+
+	return room, nil
 }
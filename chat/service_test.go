@@ -5,19 +5,29 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 type Suite struct {
 	suite.Suite
-	svc *chat.Service
+	svc   *chat.Service
+	spans *tracetest.SpanRecorder
 }
 
 func TestSuite(t *testing.T) {
 	suite.Run(t, new(Suite))
 }
 
+func (s *Suite) SetupSuite() {
+	s.spans = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.spans)))
+}
+
 func (s *Suite) SetupSubTest() {
 	s.svc = chat.NewService()
+	s.spans.Reset()
 }
 
 type MockMember struct {
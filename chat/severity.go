@@ -0,0 +1,24 @@
+package chat
+
+import "fmt"
+
+// Severity is the severity of a system announcement.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// ParseSeverity parses a severity, an empty string meaning SeverityInfo.
+func ParseSeverity(s string) (Severity, error) {
+	switch Severity(s) {
+	case "", SeverityInfo:
+		return SeverityInfo, nil
+	case SeverityWarning, SeverityCritical:
+		return Severity(s), nil
+	default:
+		return "", fmt.Errorf("invalid severity %q: must be info, warning or critical", s)
+	}
+}
@@ -0,0 +1,25 @@
+package chat_test
+
+import "practice-run/chat"
+
+func (s *Suite) TestParseSeverity() {
+	s.Run("default to info", func() {
+		severity, err := chat.ParseSeverity("")
+
+		s.NoError(err)
+		s.Equal(chat.SeverityInfo, severity)
+	})
+
+	s.Run("known severities", func() {
+		severity, err := chat.ParseSeverity("critical")
+
+		s.NoError(err)
+		s.Equal(chat.SeverityCritical, severity)
+	})
+
+	s.Run("unknown severity", func() {
+		_, err := chat.ParseSeverity("urgent")
+
+		s.Error(err)
+	})
+}
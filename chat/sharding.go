@@ -0,0 +1,752 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// PeerClient calls another node of a sharded cluster, which handles the call
+// with HandlePeerCall, and returns the result.
+type PeerClient interface {
+	Call(ctx context.Context, node string, payload []byte) ([]byte, error)
+}
+
+// WithSharding makes the service, identified by node, a node of a sharded
+// cluster: each room is owned by one of peers, chosen by consistent hashing,
+// and calls about rooms owned by other nodes are forwarded to their owner
+// through client. The events of the members connected to this node are
+// delivered back to it.
+func WithSharding(client PeerClient, node string, peers []string) Option {
+	return func(s *Service) {
+		s.sharding = &sharding{
+			client:   client,
+			node:     node,
+			peers:    peers,
+			ring:     NewRing(peers),
+			outboxes: make(map[string]chan []byte),
+			joined:   make(map[string]map[string]bool),
+		}
+	}
+}
+
+// peerOutboxSize is the number of calls a node may have waiting to be sent to
+// each other node before dropping them.
+const peerOutboxSize = 1024
+
+type sharding struct {
+	client PeerClient
+	node   string
+
+	// protected by the service lock
+	peers []string
+	ring  *Ring
+	// outboxes queue the calls sent to each node without waiting for their
+	// result, in order
+	outboxes map[string]chan []byte
+	// joined records the rooms owned by other nodes each member connected to
+	// this node is in, for it to leave them when disconnecting
+	joined map[string]map[string]bool
+
+	logger *slog.Logger
+}
+
+type peerMethod string
+
+const (
+	peerCreateRoom    peerMethod = "create_room"
+	peerAddMember     peerMethod = "add_member"
+	peerRemoveMember  peerMethod = "remove_member"
+	peerSendMessage   peerMethod = "send_message"
+	peerInviteMember  peerMethod = "invite_member"
+	peerGetMembers    peerMethod = "get_members"
+	peerGetACL        peerMethod = "get_acl"
+	peerAddACLRule    peerMethod = "add_acl_rule"
+	peerRemoveACLRule peerMethod = "remove_acl_rule"
+	peerDeleteRoom    peerMethod = "delete_room"
+	peerRemoveUser    peerMethod = "remove_user"
+	// peerSearch searches the rooms of the called node Username is in for
+	// the query in Message
+	peerSearch peerMethod = "search"
+	// peerCanDownload checks whether the attachment Target has been sent to
+	// the rooms of the called node Username is in
+	peerCanDownload peerMethod = "can_download"
+	// peerEditMessage replaces the text of the message Target by Message, and
+	// peerDeleteMessage deletes it
+	peerEditMessage   peerMethod = "edit_message"
+	peerDeleteMessage peerMethod = "delete_message"
+	// peerReact adds the reaction Message of Username to the message Target,
+	// and peerUnreact removes it
+	peerReact   peerMethod = "react"
+	peerUnreact peerMethod = "unreact"
+	// peerHistory returns the last Limit messages of the room
+	peerHistory peerMethod = "history"
+	// peerReply replies Message to the message Target, peerThread returns its
+	// thread, and peerFollow and peerUnfollow change whether Username
+	// follows it
+	peerReply    peerMethod = "reply"
+	peerThread   peerMethod = "thread"
+	peerFollow   peerMethod = "follow"
+	peerUnfollow peerMethod = "unfollow"
+	peerTyping   peerMethod = "typing"
+	// peerMarkRead marks the messages of the room up to Target as read by
+	// Username, and peerUnread counts its unread messages in the rooms of
+	// the called node
+	peerMarkRead peerMethod = "mark_read"
+	peerUnread   peerMethod = "unread"
+
+	// peerBroadcast notifies every member connected to the called node of
+	// Event, and peerConnections returns them
+	peerBroadcast   peerMethod = "broadcast"
+	peerConnections peerMethod = "connections"
+	// peerRooms returns the rooms owned by the called node
+	peerRooms peerMethod = "rooms"
+	// peerConnected checks whether Username is connected to the called node,
+	// peerTakeover replaces its connection, and peerDisconnectUser
+	// disconnects it for the reason Message
+	peerConnected      peerMethod = "connected"
+	peerTakeover       peerMethod = "takeover"
+	peerDisconnectUser peerMethod = "disconnect_user"
+
+	// peerDisconnectMember removes Username, disconnected from the calling
+	// node, from the room, away
+	peerDisconnectMember peerMethod = "disconnect_member"
+	// peerDeliver notifies the Recipients, connected to the called node, of
+	// Event
+	peerDeliver peerMethod = "deliver"
+	// peerMigrate hands the room described by State over to the called node
+	peerMigrate peerMethod = "migrate"
+)
+
+// peerCall is a call made by Node on behalf of its member Username.
+type peerCall struct {
+	Method   peerMethod `json:"method"`
+	Node     string     `json:"node"`
+	Room     string     `json:"room,omitempty"`
+	Username string     `json:"username,omitempty"`
+	// Target is the user invited or removed, or the attachment or message
+	// the call is about
+	Target      string        `json:"target,omitempty"`
+	Message     string        `json:"message,omitempty"`
+	Attachments []Attachment  `json:"attachments,omitempty"`
+	Rule        *Rule         `json:"rule,omitempty"`
+	Event       *encodedEvent `json:"event,omitempty"`
+	Recipients  []string      `json:"recipients,omitempty"`
+	State       *roomState    `json:"state,omitempty"`
+	Limit       int           `json:"limit,omitempty"`
+}
+
+type peerResult struct {
+	// Error is the message of the error returned by the call, and Code the
+	// message of the package error it wraps, if any
+	Error    string         `json:"error,omitempty"`
+	Code     string         `json:"code,omitempty"`
+	Members  []memberState  `json:"members,omitempty"`
+	Rules    []Rule         `json:"rules,omitempty"`
+	Results  []SearchResult `json:"results,omitempty"`
+	Allowed  bool           `json:"allowed,omitempty"`
+	Message  *Message       `json:"message,omitempty"`
+	Messages []*Message     `json:"messages,omitempty"`
+	Count    int            `json:"count,omitempty"`
+	Unread   []UnreadCount  `json:"unread,omitempty"`
+	// Connected is whether the user of a peerConnected call is connected to
+	// the called node, and Connections the members connected to it
+	Connected   bool             `json:"connected,omitempty"`
+	Connections []ConnectionInfo `json:"connections,omitempty"`
+	Rooms       []RoomInfo       `json:"rooms,omitempty"`
+}
+
+type roomState struct {
+	Name     string            `json:"name"`
+	Rules    []Rule            `json:"rules"`
+	Members  []memberState     `json:"members"`
+	Messages []*Message        `json:"messages,omitempty"`
+	Read     map[string]uint64 `json:"read,omitempty"`
+	Away     []string          `json:"away,omitempty"`
+}
+
+// memberState is a room member and the node it's connected to.
+type memberState struct {
+	Username string `json:"username"`
+	Node     string `json:"node"`
+}
+
+// peerErrors are the errors recognized in the results of peer calls.
+var peerErrors = []error{
+	ErrRoomNotFound,
+	ErrRoomAlreadyExists,
+	ErrMemberAlreadyExists,
+	ErrNotRoomMember,
+	ErrAlreadyConnected,
+	ErrNotConnected,
+	ErrPermissionDenied,
+	ErrInvalidRule,
+	ErrRuleNotFound,
+	ErrInvalidQuery,
+	ErrAttachmentNotFound,
+	ErrMessageNotFound,
+	ErrInvalidReaction,
+	ErrAlreadyReacted,
+	ErrReactionNotFound,
+	ErrRoomMigrating,
+}
+
+// peerError is an error returned by another node, which wraps the package
+// error the original one did.
+type peerError struct {
+	message string
+	err     error
+}
+
+func (e *peerError) Error() string {
+	return e.message
+}
+
+func (e *peerError) Unwrap() error {
+	return e.err
+}
+
+// forwardedKey marks the contexts of calls forwarded by other nodes, which are
+// never forwarded again.
+type forwardedKey struct{}
+
+// peerMember stands for a member connected to another node of a sharded
+// cluster, in a room owned by this node. Notifying it delivers the event to
+// its node.
+type peerMember struct {
+	username string
+	node     string
+	sharding *sharding
+}
+
+func (m *peerMember) Username() string {
+	return m.username
+}
+
+func (m *peerMember) Notify(event Event) {
+	encoded, err := encodeEvent(event)
+	if err != nil {
+		m.sharding.logger.Error("failed to encode event", slog.String("event", event.Name()), slog.Any("error", err))
+		return
+	}
+
+	m.sharding.send(m.node, peerCall{Method: peerDeliver, Event: encoded, Recipients: []string{m.username}})
+}
+
+// send queues call for node, without waiting for its result. Calls are dropped
+// when too many are waiting, rather than blocking the service on a slow or
+// unreachable node.
+func (s *sharding) send(node string, call peerCall) {
+	call.Node = s.node
+
+	payload, err := json.Marshal(call)
+	if err != nil {
+		s.logger.Error("failed to encode peer call", slog.String("method", string(call.Method)), slog.Any("error", err))
+		return
+	}
+
+	outbox, ok := s.outboxes[node]
+	if !ok {
+		outbox = make(chan []byte, peerOutboxSize)
+		s.outboxes[node] = outbox
+
+		go s.run(node, outbox)
+	}
+
+	select {
+	case outbox <- payload:
+	default:
+		s.logger.Error("failed to call peer: too many calls waiting",
+			slog.String("node", node),
+			slog.String("method", string(call.Method)),
+		)
+	}
+}
+
+func (s *sharding) run(node string, outbox chan []byte) {
+	for payload := range outbox {
+		_, err := s.client.Call(context.Background(), node, payload)
+		if err != nil {
+			s.logger.Error("failed to call peer", slog.String("node", node), slog.Any("error", err))
+		}
+	}
+}
+
+// join records that the member username joined a room owned by another node,
+// or left it if joined is false.
+func (s *sharding) join(username string, roomName string, joined bool) {
+	if !joined {
+		delete(s.joined[username], roomName)
+		return
+	}
+
+	if s.joined[username] == nil {
+		s.joined[username] = make(map[string]bool)
+	}
+
+	s.joined[username][roomName] = true
+}
+
+// joinedRemote records that the member username joined, or left, a room owned
+// by another node.
+func (r *Service) joinedRemote(username string, roomName string, joined bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.sharding.join(username, roomName, joined)
+}
+
+// joinedOwners returns the other nodes owning rooms the member username is
+// in, unless the call has been forwarded by another node.
+func (r *Service) joinedOwners(ctx context.Context, username string) []string {
+	if r.sharding == nil || ctx.Value(forwardedKey{}) != nil {
+		return nil
+	}
+
+	var owners []string
+	for roomName := range r.sharding.joined[username] {
+		owner := r.sharding.ring.Owner(roomName)
+		if owner != r.sharding.node && !slices.Contains(owners, owner) {
+			owners = append(owners, owner)
+		}
+	}
+
+	return owners
+}
+
+// otherNodes returns the other nodes of a sharded cluster, unless the call has
+// been forwarded by another node.
+func (r *Service) otherNodes(ctx context.Context) []string {
+	if r.sharding == nil || ctx.Value(forwardedKey{}) != nil {
+		return nil
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r.sharding.others()
+}
+
+// others returns the other nodes of the cluster.
+func (s *sharding) others() []string {
+	var nodes []string
+	for _, node := range s.peers {
+		if node != s.node && !slices.Contains(nodes, node) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+// remoteOwner returns the node owning roomName if it's another node of a
+// sharded cluster, unless the call has been forwarded by another node or the
+// room is still being handed over to its owner.
+func (r *Service) remoteOwner(ctx context.Context, roomName string) (string, bool) {
+	if r.sharding == nil || ctx.Value(forwardedKey{}) != nil {
+		return "", false
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if room, ok := r.rooms[roomName]; ok && room.migrating {
+		return "", false
+	}
+
+	owner := r.sharding.ring.Owner(roomName)
+
+	return owner, owner != "" && owner != r.sharding.node
+}
+
+// forward makes call to node, the owner of the room it's about, and returns
+// its result.
+func (r *Service) forward(ctx context.Context, node string, call peerCall) (*peerResult, error) {
+	call.Node = r.sharding.node
+
+	payload, err := json.Marshal(call)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode peer call: %w", err)
+	}
+
+	payload, err = r.sharding.client.Call(ctx, node, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", node, err)
+	}
+
+	var result peerResult
+	err = json.Unmarshal(payload, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode result of %s: %w", node, err)
+	}
+
+	if result.Error != "" {
+		err := &peerError{message: result.Error}
+		for _, peerErr := range peerErrors {
+			if peerErr.Error() == result.Code {
+				err.err = peerErr
+			}
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// HandlePeerCall handles a call made by another node of a sharded cluster
+// through its PeerClient, and returns the result.
+func (r *Service) HandlePeerCall(ctx context.Context, payload []byte) ([]byte, error) {
+	if r.sharding == nil {
+		return nil, errors.New("not a node of a sharded cluster")
+	}
+
+	var call peerCall
+	err := json.Unmarshal(payload, &call)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode peer call: %w", err)
+	}
+
+	ctx = context.WithValue(ctx, forwardedKey{}, call.Node)
+
+	result, err := r.handlePeerCall(ctx, call)
+	if err != nil {
+		result.Error = err.Error()
+		for _, peerErr := range peerErrors {
+			if errors.Is(err, peerErr) {
+				result.Code = peerErr.Error()
+			}
+		}
+	}
+
+	return json.Marshal(result)
+}
+
+func (r *Service) handlePeerCall(ctx context.Context, call peerCall) (result peerResult, err error) {
+	member := &peerMember{username: call.Username, node: call.Node, sharding: r.sharding}
+
+	switch call.Method {
+	case peerCreateRoom:
+		_, err = r.CreateRoom(ctx, call.Room, call.Username)
+
+	case peerAddMember:
+		err = r.AddMember(ctx, call.Room, member)
+
+	case peerRemoveMember:
+		err = r.RemoveMember(ctx, call.Room, member)
+
+	case peerDisconnectMember:
+		err = r.disconnectedFrom(ctx, call.Room, call.Username)
+
+	case peerSendMessage:
+		result.Message, err = r.sendMessage(ctx, call.Room, member, call.Message, call.Attachments)
+
+	case peerInviteMember:
+		err = r.InviteMember(ctx, call.Room, member, call.Target)
+
+	case peerGetMembers:
+		var members []Member
+		members, err = r.GetMembers(ctx, call.Room)
+		for _, member := range members {
+			result.Members = append(result.Members, r.memberState(member))
+		}
+
+	case peerGetACL:
+		result.Rules, err = r.GetACL(ctx, call.Room, member)
+
+	case peerAddACLRule, peerRemoveACLRule:
+		if call.Rule == nil {
+			return result, ErrInvalidRule
+		}
+
+		if call.Method == peerAddACLRule {
+			err = r.AddACLRule(ctx, call.Room, member, *call.Rule)
+		} else {
+			err = r.RemoveACLRule(ctx, call.Room, member, *call.Rule)
+		}
+
+	case peerDeleteRoom:
+		err = r.DeleteRoom(ctx, call.Room)
+
+	case peerRemoveUser:
+		err = r.RemoveUser(ctx, call.Room, call.Target)
+
+	case peerSearch:
+		result.Results, err = r.Search(ctx, member, call.Message)
+
+	case peerCanDownload:
+		result.Allowed, err = r.CanDownload(ctx, call.Username, call.Target)
+
+	case peerEditMessage:
+		result.Message, err = r.EditMessage(ctx, member, call.Target, call.Message)
+
+	case peerDeleteMessage:
+		result.Message, err = r.DeleteMessage(ctx, member, call.Target)
+
+	case peerReact:
+		result.Message, err = r.React(ctx, member, call.Target, call.Message)
+
+	case peerUnreact:
+		result.Message, err = r.Unreact(ctx, member, call.Target, call.Message)
+
+	case peerHistory:
+		result.Messages, err = r.History(ctx, call.Room, member, call.Limit)
+
+	case peerReply:
+		result.Message, err = r.reply(ctx, member, call.Target, call.Message, call.Attachments)
+
+	case peerThread:
+		result.Messages, err = r.Thread(ctx, member, call.Target)
+
+	case peerFollow:
+		result.Message, err = r.Follow(ctx, member, call.Target)
+
+	case peerUnfollow:
+		result.Message, err = r.Unfollow(ctx, member, call.Target)
+
+	case peerTyping:
+		err = r.Typing(ctx, call.Room, member)
+
+	case peerMarkRead:
+		result.Count, err = r.MarkRead(ctx, call.Room, member, call.Target)
+
+	case peerUnread:
+		result.Unread, err = r.Unread(ctx, call.Username)
+
+	case peerBroadcast:
+		var event Event
+		event, err = decodeEvent(call.Event)
+		if err == nil {
+			r.BroadcastLocal(ctx, event)
+		}
+
+	case peerConnections:
+		result.Connections = r.ListConnections(ctx)
+
+	case peerRooms:
+		result.Rooms = r.LocalRooms(ctx)
+
+	case peerConnected:
+		result.Connected = r.IsConnected(ctx, call.Username)
+
+	case peerTakeover:
+		r.takenOver(ctx, call.Username)
+
+	case peerDisconnectUser:
+		err = r.DisconnectUser(ctx, call.Username, call.Message)
+
+	case peerDeliver:
+		err = r.deliver(call)
+
+	case peerMigrate:
+		err = r.adopt(call.State)
+
+	default:
+		err = fmt.Errorf("unknown peer method %q", call.Method)
+	}
+
+	return result, err
+}
+
+// deliver notifies the recipients of a call, connected to this node, of its
+// event, recording the rooms of other nodes they're invited to or removed
+// from.
+func (r *Service) deliver(call peerCall) error {
+	event, err := decodeEvent(call.Event)
+	if err != nil {
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for _, username := range call.Recipients {
+		switch event := event.(type) {
+		case *InvitedEvent:
+			r.sharding.join(username, event.RoomName, true)
+		case *RemovedEvent:
+			r.sharding.join(username, event.RoomName, false)
+		case *RoomDeletedEvent:
+			r.sharding.join(username, event.RoomName, false)
+		}
+
+		if member, ok := r.members[username]; ok {
+			member.Notify(event)
+		}
+	}
+
+	return nil
+}
+
+func (r *Service) memberState(member Member) memberState {
+	if member, ok := member.(*peerMember); ok {
+		return memberState{Username: member.username, Node: member.node}
+	}
+
+	return memberState{Username: member.Username(), Node: r.sharding.node}
+}
+
+// peerMembers returns the members described by states, which are connected to
+// other nodes.
+func (r *Service) peerMembers(states []memberState) []Member {
+	members := make([]Member, 0, len(states))
+	for _, state := range states {
+		members = append(members, &peerMember{username: state.Username, node: state.Node, sharding: r.sharding})
+	}
+
+	return members
+}
+
+// SetPeers changes the nodes of a sharded cluster, handing the rooms this node
+// no longer owns over to their new owner. Rooms are only removed once their
+// new owner has taken them over, and are kept if it fails to. Calls about the
+// rooms being handed over are rejected with ErrRoomMigrating in the meantime,
+// but for the members disconnecting, who are then removed from the new owner.
+func (r *Service) SetPeers(ctx context.Context, peers []string) {
+	if r.sharding == nil {
+		return
+	}
+
+	type migration struct {
+		owner string
+		room  *Room
+		state roomState
+	}
+
+	var migrations []migration
+
+	r.mtx.Lock()
+
+	r.sharding.peers = peers
+	r.sharding.ring = NewRing(peers)
+
+	for name, room := range r.rooms {
+		owner := r.sharding.ring.Owner(name)
+		if owner == "" || owner == r.sharding.node {
+			continue
+		}
+
+		state := roomState{Name: name, Rules: room.acl.Rules(), Members: []memberState{}, Read: maps.Clone(room.read)}
+		for _, member := range room.members {
+			state.Members = append(state.Members, r.memberState(member))
+		}
+
+		for _, message := range room.messages {
+			state.Messages = append(state.Messages, message)
+		}
+
+		for username := range room.away {
+			state.Away = append(state.Away, username)
+		}
+
+		room.migrating = true
+
+		migrations = append(migrations, migration{owner: owner, room: room, state: state})
+	}
+
+	r.mtx.Unlock()
+
+	for _, migration := range migrations {
+		_, err := r.forward(ctx, migration.owner, peerCall{Method: peerMigrate, State: &migration.state})
+		if err != nil {
+			r.logger.Error("failed to migrate room",
+				slog.String("room", migration.state.Name),
+				slog.String("node", migration.owner),
+				slog.Any("error", err),
+			)
+
+			r.mtx.Lock()
+			migration.room.migrating = false
+			r.mtx.Unlock()
+
+			continue
+		}
+
+		r.handedOver(migration.room, migration.owner, migration.state.Members)
+
+		r.logger.Info("migrated room", slog.String("room", migration.state.Name), slog.String("node", migration.owner))
+	}
+}
+
+// handedOver removes room, taken over by owner, which the members connected
+// to this node are now in remotely. The members handed over who disconnected
+// in the meantime are removed from owner.
+func (r *Service) handedOver(room *Room, owner string, members []memberState) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.rooms[room.Name()] != room {
+		return
+	}
+
+	for _, member := range members {
+		if _, ok := room.members[member.Username]; !ok {
+			r.sharding.send(owner, peerCall{Method: peerDisconnectMember, Room: room.Name(), Username: member.Username})
+		}
+	}
+
+	for _, member := range room.members {
+		if _, ok := member.(*peerMember); !ok {
+			r.sharding.join(member.Username(), room.Name(), true)
+		}
+	}
+
+	delete(r.rooms, room.Name())
+
+	// Rooms handed over are no longer recorded here
+	r.journal.compact()
+}
+
+// adopt creates the room described by state, handed over by its previous
+// owner.
+func (r *Service) adopt(state *roomState) error {
+	if state == nil {
+		return errors.New("missing room state")
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if _, ok := r.rooms[state.Name]; ok {
+		return ErrRoomAlreadyExists
+	}
+
+	room := r.newRoom(state.Name, "")
+	for _, rule := range state.Rules {
+		room.acl.add(rule)
+	}
+
+	room.restoreMessages(state.Messages)
+
+	for username, seq := range state.Read {
+		room.setRead(username, seq)
+	}
+
+	for _, username := range state.Away {
+		room.setAway(username, true)
+	}
+
+	for _, member := range state.Members {
+		if member.Node != r.sharding.node {
+			room.members[member.Username] = &peerMember{username: member.Username, node: member.Node, sharding: r.sharding}
+			continue
+		}
+
+		if local, ok := r.members[member.Username]; ok {
+			room.members[member.Username] = local
+			r.sharding.join(member.Username, state.Name, false)
+		}
+	}
+
+	r.rooms[state.Name] = room
+
+	r.journal.compact()
+
+	room.logger.Info("adopted room", slog.Int("members", len(room.members)))
+
+	return nil
+}
@@ -0,0 +1,511 @@
+package chat_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"practice-run/chat"
+	"sync"
+	"time"
+)
+
+func (s *Suite) TestSharding() {
+	s.Run("forward calls to the owner of the room", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+
+		// When
+		_, err1 := node1.CreateRoom(ctx, roomName, "user_1")
+		err2 := node1.AddMember(ctx, roomName, member1)
+		err3 := node2.AddMember(ctx, roomName, member2)
+		sent, err4 := node2.SendMessage(ctx, roomName, member2, "hello")
+
+		// Then
+		s.NoError(err1)
+		s.NoError(err2)
+		s.NoError(err3)
+		s.NoError(err4)
+		s.Eventually(func() bool {
+			return len(member1.received()) == 2
+		}, time.Second, 10*time.Millisecond)
+		s.Equal([]chat.Event{
+			&chat.MemberJoinedEvent{RoomName: roomName, MemberName: "user_2"},
+			&chat.MessageReceivedEvent{RoomName: roomName, MessageID: sent.ID, SenderName: "user_2", Message: "hello"},
+		}, member1.received())
+		s.Equal([]chat.RoomInfo{{Name: roomName, Members: 2}}, node1.ListRooms(ctx))
+		s.Equal([]chat.RoomInfo{{Name: roomName, Members: 2}}, node2.ListRooms(ctx))
+		members, err := node1.GetMembers(ctx, roomName)
+		s.NoError(err)
+		s.ElementsMatch([]string{"user_1", "user_2"}, usernames(members))
+	})
+
+	s.Run("return the errors of the owner", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		_ = network.start("node_2", peers)
+
+		// When
+		err := node1.AddMember(ctx, roomOwnedBy(peers, "node_2"), &RecordingMember{username: "user_1"})
+
+		// Then
+		s.ErrorIs(err, chat.ErrRoomNotFound)
+	})
+
+	s.Run("leave the rooms of other nodes on disconnect", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member := &RecordingMember{username: "user_1"}
+		_ = node1.Connect(ctx, member)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node1.AddMember(ctx, roomName, member)
+
+		// When
+		err := node1.Disconnect(ctx, member)
+
+		// Then
+		s.NoError(err)
+		s.Eventually(func() bool {
+			return node2.ListRooms(ctx)[0].Members == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	s.Run("invite users connected to another node", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		member3 := &RecordingMember{username: "user_3"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_ = node1.Connect(ctx, member3)
+		_, _ = node2.CreateRoom(ctx, roomName, "user_2")
+		_ = node2.AddMember(ctx, roomName, member2)
+
+		// When
+		err1 := node2.InviteMember(ctx, roomName, member2, "user_1")
+		err2 := node1.InviteMember(ctx, roomName, member1, "user_3")
+
+		// Then
+		s.NoError(err1)
+		s.NoError(err2)
+		s.Eventually(func() bool {
+			return len(member1.received()) == 2 && len(member3.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+		s.Equal([]chat.Event{
+			&chat.InvitedEvent{RoomName: roomName, InviterName: "user_2"},
+			&chat.MemberJoinedEvent{RoomName: roomName, MemberName: "user_3"},
+		}, member1.received())
+		s.Equal([]chat.Event{&chat.InvitedEvent{RoomName: roomName, InviterName: "user_1"}}, member3.received())
+		s.Equal([]chat.RoomInfo{{Name: roomName, Members: 3}}, node2.ListRooms(ctx))
+		s.NoError(node1.Disconnect(ctx, member1))
+		s.NoError(node1.Disconnect(ctx, member3))
+		s.Eventually(func() bool {
+			return node2.ListRooms(ctx)[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	s.Run("migrate rooms when peers change", func() {
+		// Given
+		ctx := context.Background()
+		network := &peerNetwork{}
+		node1 := network.start("node_1", []string{"node_1"})
+		node2 := network.start("node_2", []string{"node_1"})
+		peers := []string{"node_1", "node_2"}
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node2.AddMember(ctx, roomName, member2)
+		_ = node1.AddMember(ctx, roomName, member1)
+
+		// When
+		node2.SetPeers(ctx, peers)
+		node1.SetPeers(ctx, peers)
+
+		// Then
+		s.Equal([]chat.RoomInfo{{Name: roomName, Members: 2}}, node1.ListRooms(ctx))
+		s.Equal([]chat.RoomInfo{{Name: roomName, Members: 2}}, node2.ListRooms(ctx))
+		rules, err := node1.GetACL(ctx, roomName, member1)
+		s.NoError(err)
+		s.Equal([]chat.Rule{{Effect: chat.EffectAllow, Permission: chat.PermissionManage, Subject: "@user_1"}}, rules)
+		sent, err := node2.SendMessage(ctx, roomName, member2, "hello")
+		s.NoError(err)
+		s.Eventually(func() bool {
+			return len(member1.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+		s.Equal(&chat.MessageReceivedEvent{RoomName: roomName, MessageID: sent.ID, SenderName: "user_2", Message: "hello"}, member1.received()[0])
+		s.NoError(node1.Disconnect(ctx, member1))
+		s.Eventually(func() bool {
+			return node2.ListRooms(ctx)[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	s.Run("reject calls about rooms being migrated", func() {
+		// Given
+		ctx := context.Background()
+		network := &peerNetwork{}
+		node1 := network.start("node_1", []string{"node_1"})
+		node2 := network.start("node_2", []string{"node_1"})
+		peers := []string{"node_1", "node_2"}
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node1.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node1.AddMember(ctx, roomName, member1)
+		_ = node1.AddMember(ctx, roomName, member2)
+		node2.SetPeers(ctx, peers)
+		release := network.hold("node_2")
+
+		// When
+		migrated := make(chan struct{})
+		go func() {
+			defer close(migrated)
+			node1.SetPeers(ctx, peers)
+		}()
+
+		// Then
+		s.Eventually(func() bool {
+			_, err := node1.SendMessage(ctx, roomName, member1, "hello")
+			return errors.Is(err, chat.ErrRoomMigrating)
+		}, time.Second, 10*time.Millisecond)
+		s.NoError(node1.Disconnect(ctx, member2))
+		release()
+		<-migrated
+		s.Eventually(func() bool {
+			rooms := node2.ListRooms(ctx)
+			return len(rooms) == 1 && rooms[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+		_, err := node1.SendMessage(ctx, roomName, member1, "hello")
+		s.NoError(err)
+	})
+
+	s.Run("keep rooms the new owner fails to take over", func() {
+		// Given
+		ctx := context.Background()
+		network := &peerNetwork{}
+		node1 := network.start("node_1", []string{"node_1"})
+		peers := []string{"node_1", "node_2"}
+		roomName := roomOwnedBy(peers, "node_2")
+		member := &RecordingMember{username: "user_1"}
+		_ = node1.Connect(ctx, member)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node1.AddMember(ctx, roomName, member)
+		sent, _ := node1.SendMessage(ctx, roomName, member, "hello")
+
+		// When
+		node1.SetPeers(ctx, peers)
+
+		// Then
+		s.Equal([]chat.RoomInfo{{Name: roomName, Members: 1}}, node1.ListRooms(ctx))
+		node1.SetPeers(ctx, []string{"node_1"})
+		messages, err := node1.History(ctx, roomName, member, 0)
+		s.NoError(err)
+		s.Len(messages, 1)
+		s.Equal(sent.ID, messages[0].ID)
+	})
+
+	s.Run("drop the events of nodes not keeping up", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_1")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node1.AddMember(ctx, roomName, member1)
+		_ = node2.AddMember(ctx, roomName, member2)
+		release := network.hold("node_2")
+		defer release()
+
+		// When
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range 2000 {
+				_, _ = node1.SendMessage(ctx, roomName, member1, fmt.Sprintf("message %d", i))
+			}
+		}()
+
+		// Then
+		s.Eventually(func() bool {
+			select {
+			case <-done:
+				return true
+			default:
+				return false
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	s.Run("reject users connected to another node", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		_ = node1.Connect(ctx, &RecordingMember{username: "user_1"})
+
+		// When
+		err := node2.Connect(ctx, &RecordingMember{username: "user_1"})
+
+		// Then
+		s.ErrorIs(err, chat.ErrAlreadyConnected)
+		s.True(node2.IsConnected(ctx, "user_1"))
+	})
+
+	s.Run("take over sessions of another node", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		previous := &RecordingMember{username: "user_1"}
+		_ = node1.Connect(ctx, previous)
+
+		// When
+		_, err := node2.Takeover(ctx, &RecordingMember{username: "user_1"})
+
+		// Then
+		s.NoError(err)
+		s.Equal([]chat.Event{&chat.SessionTakenOverEvent{}}, previous.received())
+		s.Empty(node1.ConnectedMembers(ctx))
+		s.True(node2.IsConnected(ctx, "user_1"))
+	})
+
+	s.Run("broadcast to the members of every node", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+
+		// When
+		err := node1.Broadcast(ctx, &chat.SystemAnnouncementEvent{Message: "hello"})
+
+		// Then
+		s.NoError(err)
+		s.Eventually(func() bool {
+			return len(member2.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+		s.Equal([]chat.Event{&chat.SystemAnnouncementEvent{Message: "hello"}}, member1.received())
+		s.Equal([]chat.Event{&chat.SystemAnnouncementEvent{Message: "hello"}}, member2.received())
+	})
+
+	s.Run("list the connections of every node", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_1")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node2.AddMember(ctx, roomName, member2)
+
+		// When
+		connections := node1.ListConnections(ctx)
+
+		// Then
+		s.Len(connections, 2)
+		s.Equal("user_1", connections[0].Username)
+		s.Empty(connections[0].Rooms)
+		s.Equal("user_2", connections[1].Username)
+		s.Equal([]string{roomName}, connections[1].Rooms)
+	})
+
+	s.Run("list the rooms of every node", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		network.start("node_2", peers)
+		roomName1 := roomOwnedBy(peers, "node_1")
+		roomName2 := roomOwnedBy(peers, "node_2")
+		member := &RecordingMember{username: "user_1"}
+		_ = node1.Connect(ctx, member)
+		_, _ = node1.CreateRoom(ctx, roomName1, "user_1")
+		_, _ = node1.CreateRoom(ctx, roomName2, "user_1")
+		_ = node1.AddMember(ctx, roomName2, member)
+
+		// When
+		rooms := node1.ListRooms(ctx)
+		localRooms := node1.LocalRooms(ctx)
+
+		// Then
+		s.ElementsMatch([]chat.RoomInfo{{Name: roomName1}, {Name: roomName2, Members: 1}}, rooms)
+		s.Equal([]chat.RoomInfo{{Name: roomName1}}, localRooms)
+	})
+
+	s.Run("disconnect users connected to another node", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		member := &RecordingMember{username: "user_1"}
+		_ = node2.Connect(ctx, member)
+
+		// When
+		err1 := node1.DisconnectUser(ctx, "user_1", "spam")
+		err2 := node1.DisconnectUser(ctx, "user_2", "spam")
+
+		// Then
+		s.NoError(err1)
+		s.ErrorIs(err2, chat.ErrNotConnected)
+		s.Equal([]chat.Event{&chat.DisconnectedEvent{Reason: "spam"}}, member.received())
+		s.False(node2.IsConnected(ctx, "user_1"))
+	})
+
+	s.Run("forward attachments and check downloads with the owner", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		attachment := chat.Attachment{ID: "a1b2c3", Name: "server.log", Size: 18, Type: "text/plain", URL: "/attachments/a1b2c3"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers, chat.WithAttachmentResolver(MockAttachments{attachment.ID: attachment}))
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node1.AddMember(ctx, roomName, member1)
+		_ = node2.AddMember(ctx, roomName, member2)
+
+		// When
+		sent, err := node1.SendMessage(ctx, roomName, member1, "logs", attachment.ID)
+
+		// Then
+		s.NoError(err)
+		s.Equal([]chat.Event{&chat.MessageReceivedEvent{
+			RoomName:    roomName,
+			MessageID:   sent.ID,
+			SenderName:  "user_1",
+			Message:     "logs",
+			Attachments: []chat.Attachment{attachment},
+		}}, member2.received())
+		allowed, err := node1.CanDownload(ctx, "user_1", attachment.ID)
+		s.NoError(err)
+		s.True(allowed)
+	})
+}
+
+// peerNetwork routes the calls between the nodes of a sharded cluster.
+type peerNetwork struct {
+	mu    sync.Mutex
+	nodes map[string]*chat.Service
+	held  map[string]chan struct{}
+}
+
+func (n *peerNetwork) start(node string, peers []string, options ...chat.Option) *chat.Service {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.nodes == nil {
+		n.nodes = make(map[string]*chat.Service)
+	}
+
+	n.nodes[node] = chat.NewService(append(options, chat.WithSharding(n, node, peers))...)
+
+	return n.nodes[node]
+}
+
+// hold makes the calls to node wait until released.
+func (n *peerNetwork) hold(node string) (release func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.held == nil {
+		n.held = make(map[string]chan struct{})
+	}
+
+	held := make(chan struct{})
+	n.held[node] = held
+
+	return func() {
+		close(held)
+	}
+}
+
+func (n *peerNetwork) Call(ctx context.Context, node string, payload []byte) ([]byte, error) {
+	n.mu.Lock()
+	service, ok := n.nodes[node]
+	held := n.held[node]
+	n.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown node %s", node)
+	}
+
+	if held != nil {
+		<-held
+	}
+
+	return service.HandlePeerCall(ctx, payload)
+}
+
+// roomOwnedBy returns the name of a room owned by node.
+func roomOwnedBy(peers []string, node string) string {
+	ring := chat.NewRing(peers)
+
+	for i := 0; ; i++ {
+		roomName := fmt.Sprintf("room_%d", i)
+		if ring.Owner(roomName) == node {
+			return roomName
+		}
+	}
+}
+
+func usernames(members []chat.Member) []string {
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		names = append(names, member.Username())
+	}
+
+	return names
+}
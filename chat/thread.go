@@ -0,0 +1,221 @@
+package chat
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// Reply sends message to the thread of the message parentID, along with the
+// attachments of the given IDs, and returns the reply. Replies to replies
+// belong to the same thread.
+func (r *Service) Reply(ctx context.Context, member Member, parentID string, message string, attachments ...string) (_ *Message, err error) {
+	ctx, span := startSpan(ctx, "Reply", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	resolved, err := r.resolveAttachments(ctx, member.Username(), attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.reply(ctx, member, parentID, message, resolved)
+}
+
+func (r *Service) reply(ctx context.Context, member Member, parentID string, message string, attachments []Attachment) (*Message, error) {
+	r.mtx.Lock()
+
+	room, ok := r.messageRoom(member.Username(), parentID)
+	if !ok {
+		owners := r.joinedOwners(ctx, member.Username())
+		r.mtx.Unlock()
+
+		return messageResult(r.forwardMessageCall(ctx, owners, peerCall{
+			Method:      peerReply,
+			Username:    member.Username(),
+			Target:      parentID,
+			Message:     message,
+			Attachments: attachments,
+		}))
+	}
+
+	defer r.mtx.Unlock()
+
+	if room.migrating {
+		return nil, ErrRoomMigrating
+	}
+
+	sent, err := room.sendMessage(ctx, member, message, attachments, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reply: %w", err)
+	}
+
+	return sent, nil
+}
+
+// Thread returns the first message of the thread of the message id, followed
+// by its replies, oldest first. Deleted replies are left out.
+func (r *Service) Thread(ctx context.Context, member Member, id string) (_ []*Message, err error) {
+	ctx, span := startSpan(ctx, "Thread", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	r.mtx.Lock()
+
+	room, ok := r.messageRoom(member.Username(), id)
+	if !ok {
+		owners := r.joinedOwners(ctx, member.Username())
+		r.mtx.Unlock()
+
+		result, err := r.forwardMessageCall(ctx, owners, peerCall{
+			Method:   peerThread,
+			Username: member.Username(),
+			Target:   id,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return result.Messages, nil
+	}
+
+	defer r.mtx.Unlock()
+
+	if room.migrating {
+		return nil, ErrRoomMigrating
+	}
+
+	thread, err := room.thread(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	return thread, nil
+}
+
+// Follow makes member follow the thread of the message id, for them to be
+// notified of its replies, and returns its first message. Members follow the
+// threads they start and reply to.
+func (r *Service) Follow(ctx context.Context, member Member, id string) (_ *Message, err error) {
+	ctx, span := startSpan(ctx, "Follow", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return r.follow(ctx, member, id, true)
+}
+
+// Unfollow stops notifying member of the replies of the thread of the message
+// id, and returns its first message.
+func (r *Service) Unfollow(ctx context.Context, member Member, id string) (_ *Message, err error) {
+	ctx, span := startSpan(ctx, "Unfollow", usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return r.follow(ctx, member, id, false)
+}
+
+func (r *Service) follow(ctx context.Context, member Member, id string, follow bool) (*Message, error) {
+	r.mtx.Lock()
+
+	room, ok := r.messageRoom(member.Username(), id)
+	if !ok {
+		owners := r.joinedOwners(ctx, member.Username())
+		r.mtx.Unlock()
+
+		method := peerFollow
+		if !follow {
+			method = peerUnfollow
+		}
+
+		return messageResult(r.forwardMessageCall(ctx, owners, peerCall{
+			Method:   method,
+			Username: member.Username(),
+			Target:   id,
+		}))
+	}
+
+	defer r.mtx.Unlock()
+
+	if room.migrating {
+		return nil, ErrRoomMigrating
+	}
+
+	root, err := room.follow(member, id, follow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow thread: %w", err)
+	}
+
+	return root, nil
+}
+
+// threadRoot returns the first message of the thread of the message id.
+func (r *Room) threadRoot(id string) (*Message, error) {
+	message, ok := r.messages[id]
+	if ok && message.ParentID != "" {
+		message, ok = r.messages[message.ParentID]
+	}
+
+	if !ok || message.deleted() {
+		return nil, ErrMessageNotFound
+	}
+
+	return message, nil
+}
+
+func (r *Room) thread(id string) ([]*Message, error) {
+	root, err := r.threadRoot(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var replies []*Message
+	for _, message := range r.messages {
+		if message.ParentID == root.ID && !message.deleted() {
+			replies = append(replies, message.clone())
+		}
+	}
+
+	slices.SortFunc(replies, func(a, b *Message) int {
+		return cmp.Or(a.SentAt.Compare(b.SentAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return append([]*Message{root.clone()}, replies...), nil
+}
+
+func (r *Room) follow(member Member, id string, follow bool) (*Message, error) {
+	root, err := r.threadRoot(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(root.Followers, member.Username()) == follow {
+		return root.clone(), nil
+	}
+
+	updated := root.clone()
+	if follow {
+		updated.Followers = append(slices.Clone(root.Followers), member.Username())
+	} else {
+		updated.Followers = slices.DeleteFunc(slices.Clone(root.Followers), func(username string) bool {
+			return username == member.Username()
+		})
+	}
+
+	r.replace(updated)
+
+	r.publish(clusterMessage{Type: messageMessageUpdated, Room: r.Name(), Message: updated})
+
+	r.logger.Debug("thread followers changed",
+		slog.String("username", member.Username()),
+		slog.String("message", root.ID),
+		slog.Bool("following", follow),
+	)
+
+	return updated.clone(), nil
+}
@@ -0,0 +1,216 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/broker"
+	"practice-run/chat"
+	"time"
+)
+
+func (s *Suite) TestReply() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		member3 := &MockMember{username: "user_3"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		_ = s.svc.AddMember(ctx, "test_room", member3)
+		root, _ := s.svc.SendMessage(ctx, "test_room", member1, "deploy?")
+
+		// When
+		reply, err := s.svc.Reply(ctx, member2, root.ID, "go ahead")
+
+		// Then
+		s.NoError(err)
+		s.Equal(root.ID, reply.ParentID)
+		s.Equal(&chat.MessageReceivedEvent{
+			RoomName:   "test_room",
+			MessageID:  reply.ID,
+			SenderName: "user_2",
+			Message:    "go ahead",
+			ParentID:   root.ID,
+			Replies:    1,
+		}, member1.lastNotification)
+		s.Equal(&chat.MessageReceivedEvent{RoomName: "test_room", MessageID: root.ID, SenderName: "user_1", Message: "deploy?"}, member3.lastNotification)
+	})
+
+	s.Run("notify the followers of the thread", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		member3 := &MockMember{username: "user_3"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		_ = s.svc.AddMember(ctx, "test_room", member3)
+		root, _ := s.svc.SendMessage(ctx, "test_room", member1, "deploy?")
+		_, _ = s.svc.Reply(ctx, member2, root.ID, "go ahead")
+		_, _ = s.svc.Follow(ctx, member3, root.ID)
+		_, _ = s.svc.Unfollow(ctx, member1, root.ID)
+		member1.lastNotification = nil
+
+		// When
+		reply, err := s.svc.Reply(ctx, member2, root.ID, "done")
+
+		// Then
+		s.NoError(err)
+		s.Nil(member1.lastNotification)
+		s.Equal(reply.ID, member3.lastNotification.(*chat.MessageReceivedEvent).MessageID)
+		s.Equal(2, member3.lastNotification.(*chat.MessageReceivedEvent).Replies)
+	})
+
+	s.Run("replies to replies belong to the same thread", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		root, _ := s.svc.SendMessage(ctx, "test_room", member, "deploy?")
+		first, _ := s.svc.Reply(ctx, member, root.ID, "go ahead")
+
+		// When
+		reply, err := s.svc.Reply(ctx, member, first.ID, "done")
+
+		// Then
+		s.NoError(err)
+		s.Equal(root.ID, reply.ParentID)
+	})
+
+	s.Run("message not found", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		root, _ := s.svc.SendMessage(ctx, "test_room", member, "deploy?")
+		_, _ = s.svc.DeleteMessage(ctx, member, root.ID)
+
+		// When
+		_, err1 := s.svc.Reply(ctx, member, root.ID, "go ahead")
+		_, err2 := s.svc.Reply(ctx, member, "0a1b2c3d4e5f", "go ahead")
+
+		// Then
+		s.ErrorIs(err1, chat.ErrMessageNotFound)
+		s.ErrorIs(err2, chat.ErrMessageNotFound)
+	})
+
+	s.Run("reply on other nodes", func() {
+		// Given
+		ctx := context.Background()
+		b := broker.NewInProcess()
+		node1 := s.startNode(b, "node_1")
+		node2 := s.startNode(b, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, "test_room", "user_1")
+		_ = node1.AddMember(ctx, "test_room", member1)
+		s.Eventually(func() bool {
+			rooms := node2.ListRooms(ctx)
+			return len(rooms) == 1 && rooms[0].Members == 1
+		}, time.Second, 10*time.Millisecond)
+		_ = node2.AddMember(ctx, "test_room", member2)
+		root, _ := node1.SendMessage(ctx, "test_room", member1, "deploy?")
+		s.Eventually(func() bool {
+			_, err := node2.Thread(ctx, member2, root.ID)
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+
+		// When
+		reply, err := node2.Reply(ctx, member2, root.ID, "go ahead")
+
+		// Then
+		s.NoError(err)
+		s.Eventually(func() bool {
+			received := member1.received()
+			if len(received) == 0 {
+				return false
+			}
+
+			event, ok := received[len(received)-1].(*chat.MessageReceivedEvent)
+			return ok && event.MessageID == reply.ID
+		}, time.Second, 10*time.Millisecond)
+		s.Eventually(func() bool {
+			thread, err := node1.Thread(ctx, member1, root.ID)
+			return err == nil && len(thread) == 2 && thread[0].Replies == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	s.Run("reply in rooms owned by other nodes", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node1.CreateRoom(ctx, roomName, "user_1")
+		_ = node1.AddMember(ctx, roomName, member1)
+		_ = node2.AddMember(ctx, roomName, member2)
+		root, _ := node2.SendMessage(ctx, roomName, member2, "deploy?")
+
+		// When
+		reply, err := node1.Reply(ctx, member1, root.ID, "go ahead")
+
+		// Then
+		s.NoError(err)
+		s.Equal(root.ID, reply.ParentID)
+		thread, err := node1.Thread(ctx, member1, root.ID)
+		s.NoError(err)
+		s.Len(thread, 2)
+		s.Equal(reply.ID, thread[1].ID)
+	})
+}
+
+func (s *Suite) TestThread() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		root, _ := s.svc.SendMessage(ctx, "test_room", member, "deploy?")
+		first, _ := s.svc.Reply(ctx, member, root.ID, "go ahead")
+		deleted, _ := s.svc.Reply(ctx, member, root.ID, "oops")
+		last, _ := s.svc.Reply(ctx, member, root.ID, "done")
+		_, _ = s.svc.DeleteMessage(ctx, member, deleted.ID)
+
+		// When
+		thread, err := s.svc.Thread(ctx, member, last.ID)
+
+		// Then
+		s.NoError(err)
+		s.Len(thread, 3)
+		s.Equal(root.ID, thread[0].ID)
+		s.Equal(2, thread[0].Replies)
+		s.Equal(first.ID, thread[1].ID)
+		s.Equal(last.ID, thread[2].ID)
+	})
+
+	s.Run("replies left out of the history", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member := &MockMember{username: "user_1"}
+		_ = s.svc.AddMember(ctx, "test_room", member)
+		root, _ := s.svc.SendMessage(ctx, "test_room", member, "deploy?")
+		_, _ = s.svc.Reply(ctx, member, root.ID, "go ahead")
+
+		// When
+		messages, err := s.svc.History(ctx, "test_room", member, 0)
+
+		// Then
+		s.NoError(err)
+		s.Len(messages, 1)
+		s.Equal(1, messages[0].Replies)
+	})
+}
@@ -0,0 +1,35 @@
+package chat
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("practice-run/chat")
+
+// startSpan starts the span of a service call, named after the method.
+func startSpan(ctx context.Context, method string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "chat."+method, trace.WithAttributes(attributes...))
+}
+
+// endSpan ends span, recording err if any.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+func roomAttribute(roomName string) attribute.KeyValue {
+	return attribute.String("chat.room", roomName)
+}
+
+func usernameAttribute(username string) attribute.KeyValue {
+	return attribute.String("chat.username", username)
+}
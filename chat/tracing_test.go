@@ -0,0 +1,54 @@
+package chat_test
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func (s *Suite) TestTracing() {
+	s.Run("span per service call with a child span per broadcast", func() {
+		// Given
+		ctx := context.Background()
+		roomName := "test_room"
+		_, _ = s.svc.CreateRoom(ctx, roomName, "owner")
+		_ = s.svc.AddMember(ctx, roomName, &MockMember{username: "user_1"})
+		s.spans.Reset()
+
+		// When
+		_, err := s.svc.SendMessage(ctx, roomName, &MockMember{username: "user_1"}, "hello")
+
+		// Then
+		s.NoError(err)
+		spans := s.spans.Ended()
+		s.Require().Len(spans, 2)
+
+		broadcast, call := spans[0], spans[1]
+		s.Equal("chat.broadcast", broadcast.Name())
+		s.Equal("chat.SendMessage", call.Name())
+		s.Equal(call.SpanContext().SpanID(), broadcast.Parent().SpanID())
+		s.Contains(attributes(call), "chat.room=test_room")
+		s.Contains(attributes(broadcast), "chat.event=message_received")
+	})
+
+	s.Run("record errors", func() {
+		// When
+		_, err := s.svc.SendMessage(context.Background(), "non_existent_room", &MockMember{username: "user_1"}, "hello")
+
+		// Then
+		s.Error(err)
+		spans := s.spans.Ended()
+		s.Require().Len(spans, 1)
+		s.Equal(codes.Error, spans[0].Status().Code)
+	})
+}
+
+func attributes(span sdktrace.ReadOnlySpan) []string {
+	var attributes []string
+	for _, attribute := range span.Attributes() {
+		attributes = append(attributes, string(attribute.Key)+"="+attribute.Value.Emit())
+	}
+
+	return attributes
+}
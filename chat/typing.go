@@ -0,0 +1,75 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// TypingTimeout is how long members are shown typing after they signal
+	// it.
+	TypingTimeout = 5 * time.Second
+	// typingInterval is the minimum interval between the signals of a member
+	// notified to the room. Signals sent more often are dropped.
+	typingInterval = 3 * time.Second
+)
+
+// Typing notifies the other members of the room that member is typing a
+// message. Members signal it again to keep being shown typing.
+func (r *Service) Typing(ctx context.Context, roomName string, member Member) (err error) {
+	ctx, span := startSpan(ctx, "Typing", roomAttribute(roomName), usernameAttribute(member.Username()))
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if node, ok := r.remoteOwner(ctx, roomName); ok {
+		_, err := r.forward(ctx, node, peerCall{Method: peerTyping, Room: roomName, Username: member.Username()})
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	room, err := r.room(roomName)
+	if err != nil {
+		return err
+	}
+
+	err = room.signalTyping(ctx, member, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to signal typing: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Room) signalTyping(ctx context.Context, member Member, now time.Time) error {
+	if _, ok := r.members[member.Username()]; !ok {
+		return ErrNotRoomMember
+	}
+
+	if !r.can(member.Username(), PermissionPost) {
+		return ErrPermissionDenied
+	}
+
+	if last, ok := r.typing[member.Username()]; ok && now.Sub(last) < typingInterval {
+		return nil
+	}
+
+	for username, last := range r.typing {
+		if now.Sub(last) >= TypingTimeout {
+			delete(r.typing, username)
+		}
+	}
+
+	r.typing[member.Username()] = now
+
+	r.broadcastEvent(ctx, &TypingEvent{
+		RoomName:  r.Name(),
+		Username:  member.Username(),
+		ExpiresAt: now.Add(TypingTimeout).UTC(),
+	}, member)
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package chat_test
+
+import (
+	"context"
+	"practice-run/chat"
+	"time"
+)
+
+func (s *Suite) TestTyping() {
+	s.Run("ok", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &MockMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+
+		// When
+		err := s.svc.Typing(ctx, "test_room", member1)
+
+		// Then
+		s.NoError(err)
+		event, ok := member2.lastNotification.(*chat.TypingEvent)
+		s.Require().True(ok)
+		s.Equal("test_room", event.RoomName)
+		s.Equal("user_1", event.Username)
+		s.WithinDuration(time.Now().Add(chat.TypingTimeout), event.ExpiresAt, time.Second)
+		s.IsType(&chat.MemberJoinedEvent{}, member1.lastNotification)
+	})
+
+	s.Run("throttle signals", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+
+		// When
+		err1 := s.svc.Typing(ctx, "test_room", member1)
+		err2 := s.svc.Typing(ctx, "test_room", member1)
+
+		// Then
+		s.NoError(err1)
+		s.NoError(err2)
+		s.Len(member2.received(), 1)
+	})
+
+	s.Run("end on message", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+		member1 := &MockMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = s.svc.AddMember(ctx, "test_room", member1)
+		_ = s.svc.AddMember(ctx, "test_room", member2)
+		_ = s.svc.Typing(ctx, "test_room", member1)
+		_, _ = s.svc.SendMessage(ctx, "test_room", member1, "hello")
+
+		// When
+		err := s.svc.Typing(ctx, "test_room", member1)
+
+		// Then
+		s.NoError(err)
+		s.Len(member2.received(), 3)
+		s.IsType(&chat.TypingEvent{}, member2.received()[2])
+	})
+
+	s.Run("not a room member", func() {
+		// Given
+		ctx := context.Background()
+		_, _ = s.svc.CreateRoom(ctx, "test_room", "owner")
+
+		// When
+		err := s.svc.Typing(ctx, "test_room", &MockMember{username: "user_1"})
+
+		// Then
+		s.ErrorIs(err, chat.ErrNotRoomMember)
+	})
+
+	s.Run("room not found", func() {
+		// Given
+		ctx := context.Background()
+
+		// When
+		err := s.svc.Typing(ctx, "non_existent_room", &MockMember{username: "user_1"})
+
+		// Then
+		s.ErrorIs(err, chat.ErrRoomNotFound)
+	})
+
+	s.Run("forward to the owner of the room", func() {
+		// Given
+		ctx := context.Background()
+		peers := []string{"node_1", "node_2"}
+		network := &peerNetwork{}
+		node1 := network.start("node_1", peers)
+		node2 := network.start("node_2", peers)
+		roomName := roomOwnedBy(peers, "node_2")
+		member1 := &RecordingMember{username: "user_1"}
+		member2 := &RecordingMember{username: "user_2"}
+		_ = node1.Connect(ctx, member1)
+		_ = node2.Connect(ctx, member2)
+		_, _ = node2.CreateRoom(ctx, roomName, "owner")
+		_ = node2.AddMember(ctx, roomName, member2)
+		_ = node1.AddMember(ctx, roomName, member1)
+
+		// When
+		err := node1.Typing(ctx, roomName, member1)
+
+		// Then
+		s.NoError(err)
+		s.Require().Len(member2.received(), 2)
+		s.IsType(&chat.TypingEvent{}, member2.received()[1])
+		s.Empty(member1.received())
+	})
+}
@@ -0,0 +1,447 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variable of every setting, named after
+// its key in upper case with dots replaced by underscores, e.g.
+// PRACTICE_RUN_HTTP_ADDR for http.addr.
+const EnvPrefix = "PRACTICE_RUN_"
+
+type Config struct {
+	HTTP          HTTP        `yaml:"http"`
+	GRPC          GRPC        `yaml:"grpc"`
+	TLS           TLS         `yaml:"tls"`
+	WebSocket     WebSocket   `yaml:"websocket"`
+	RateLimit     RateLimit   `yaml:"rate_limit"`
+	Log           Log         `yaml:"log"`
+	Metrics       Metrics     `yaml:"metrics"`
+	Tracing       Tracing     `yaml:"tracing"`
+	Auth          Auth        `yaml:"auth"`
+	Accounts      Accounts    `yaml:"accounts"`
+	Admin         Admin       `yaml:"admin"`
+	Cluster       Cluster     `yaml:"cluster"`
+	Attachments   Attachments `yaml:"attachments"`
+	Journal       Journal     `yaml:"journal"`
+	SessionPolicy string      `yaml:"session_policy"`
+	// ShutdownTimeout bounds the time given to in-flight commands and
+	// connections to complete on shutdown.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type HTTP struct {
+	Addr string `yaml:"addr"`
+}
+
+type GRPC struct {
+	Addr string `yaml:"addr"`
+}
+
+// TLS is enabled on both listeners when a certificate and key are configured.
+type TLS struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
+
+type WebSocket struct {
+	ReadBufferSize  int `yaml:"read_buffer_size"`
+	WriteBufferSize int `yaml:"write_buffer_size"`
+	// AllowedOrigins lists the origins allowed to connect, "*" allowing any.
+	// When empty, only same-origin requests are allowed.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	MaxMessageSize int64    `yaml:"max_message_size"`
+	// PingInterval is the interval between pings sent to clients, and
+	// IdleTimeout the time after which silent clients are disconnected.
+	// 0 disables them.
+	PingInterval time.Duration `yaml:"ping_interval"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+}
+
+// RateLimit caps the number of messages each connection may send.
+// A rate of 0 disables it.
+type RateLimit struct {
+	MessagesPerSecond float64 `yaml:"messages_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+type Log struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// Tracing exports OpenTelemetry traces with the given exporter: none, stdout,
+// file (File) or otlp (to the OTLP gRPC collector at Endpoint).
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"`
+	File        string  `yaml:"file"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// Metrics exposes Prometheus metrics on the HTTP listener.
+type Metrics struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
+type Auth struct {
+	JWTSecret   string        `yaml:"jwt_secret"`
+	APIKeysFile string        `yaml:"api_keys_file"`
+	SessionTTL  time.Duration `yaml:"session_ttl"`
+}
+
+// Accounts are stored in File. Reserved usernames, such as those of the
+// users authenticated by JWT, can't be registered.
+type Accounts struct {
+	File     string   `yaml:"file"`
+	Reserved []string `yaml:"reserved"`
+}
+
+// Admin serves the admin API on its own listener, disabled when Addr is
+// empty, to the listed users and the members of the listed groups.
+type Admin struct {
+	Addr   string   `yaml:"addr"`
+	Users  []string `yaml:"users"`
+	Groups []string `yaml:"groups"`
+}
+
+// Cluster makes the server a node of a cluster, in one of two modes.
+//
+// In replicated mode, nodes share every room through Broker: memory for a
+// single node, or redis (at RedisAddr) for several.
+//
+// In sharded mode, each room is owned by one of the Peers, <node>=<url>
+// entries which PeersFile replaces when set, and the other nodes call it on
+// Addr, authenticating with Secret.
+type Cluster struct {
+	Mode      string `yaml:"mode"`
+	Broker    string `yaml:"broker"`
+	RedisAddr string `yaml:"redis_addr"`
+	// NodeID identifies the node in the cluster. It's generated when empty in
+	// replicated mode.
+	NodeID    string   `yaml:"node_id"`
+	Addr      string   `yaml:"addr"`
+	Peers     []string `yaml:"peers"`
+	PeersFile string   `yaml:"peers_file"`
+	Secret    string   `yaml:"secret"`
+}
+
+// Attachments stores uploaded files in Dir, disabled when empty, up to
+// MaxSize bytes and of the AllowedTypes MIME types (or type/* wildcards).
+type Attachments struct {
+	Dir          string   `yaml:"dir"`
+	MaxSize      int64    `yaml:"max_size"`
+	AllowedTypes []string `yaml:"allowed_types"`
+}
+
+// Journal records the state of the chat service in Dir, disabled when empty,
+// for it to be rebuilt on restart. Records are compacted into a snapshot
+// every CompactEvery records.
+type Journal struct {
+	Dir          string `yaml:"dir"`
+	CompactEvery int    `yaml:"compact_every"`
+}
+
+func Default() *Config {
+	return &Config{
+		HTTP: HTTP{Addr: ":8080"},
+		GRPC: GRPC{Addr: ":9090"},
+		WebSocket: WebSocket{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			MaxMessageSize:  64 * 1024,
+			PingInterval:    30 * time.Second,
+			IdleTimeout:     90 * time.Second,
+		},
+		RateLimit: RateLimit{
+			MessagesPerSecond: 10,
+			Burst:             20,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "text",
+		},
+		Auth: Auth{
+			SessionTTL: 24 * time.Hour,
+		},
+		Metrics: Metrics{
+			Enabled: true,
+			Path:    "/metrics",
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			File:        "traces.jsonl",
+			Endpoint:    "localhost:4317",
+			SampleRatio: 1,
+		},
+		Accounts: Accounts{
+			File: "accounts.json",
+		},
+		Cluster: Cluster{
+			Mode:      "replicated",
+			Broker:    "memory",
+			RedisAddr: "localhost:6379",
+			Addr:      ":9091",
+		},
+		Attachments: Attachments{
+			Dir:          "attachments",
+			MaxSize:      10 * 1024 * 1024,
+			AllowedTypes: []string{"image/*", "text/*", "application/pdf", "application/json", "application/zip", "application/x-gzip"},
+		},
+		Journal: Journal{
+			CompactEvery: 1000,
+		},
+		SessionPolicy:   "reject",
+		ShutdownTimeout: 10 * time.Second,
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence, the
+// defaults, the YAML or JSON file given by -config (or PRACTICE_RUN_CONFIG),
+// environment variables and command line flags.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("practice-run", flag.ContinueOnError)
+	path := fs.String("config", os.Getenv(EnvPrefix+"CONFIG"), "path to a YAML or JSON configuration file")
+	cfg.bind(fs)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flags have been applied on top of the defaults: remember them so that
+	// they can be applied again on top of the file and the environment.
+	flags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		flags[f.Name] = f.Value.String()
+	})
+
+	if *path != "" {
+		err = cfg.loadFile(*path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" {
+			return
+		}
+
+		if value, ok := os.LookupEnv(envName(f.Name)); ok {
+			if err := fs.Set(f.Name, value); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s: %w", envName(f.Name), err))
+			}
+		}
+	})
+
+	for name, value := range flags {
+		_ = fs.Set(name, value)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, c.Log.Level) {
+		errs = append(errs, fmt.Errorf("log.level must be one of debug, info, warn or error"))
+	}
+
+	if !slices.Contains([]string{"text", "json"}, c.Log.Format) {
+		errs = append(errs, fmt.Errorf("log.format must be text or json"))
+	}
+
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		errs = append(errs, fmt.Errorf("metrics.path must start with /"))
+	}
+
+	if !slices.Contains([]string{"none", "stdout", "file", "otlp"}, c.Tracing.Exporter) {
+		errs = append(errs, fmt.Errorf("tracing.exporter must be one of none, stdout, file or otlp"))
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio must be between 0 and 1"))
+	}
+
+	if c.Admin.Addr != "" && len(c.Admin.Users) == 0 && len(c.Admin.Groups) == 0 {
+		errs = append(errs, fmt.Errorf("admin.users or admin.groups must be set when admin.addr is"))
+	}
+
+	if !slices.Contains([]string{"replicated", "sharded"}, c.Cluster.Mode) {
+		errs = append(errs, fmt.Errorf("cluster.mode must be replicated or sharded"))
+	}
+
+	if !slices.Contains([]string{"memory", "redis"}, c.Cluster.Broker) {
+		errs = append(errs, fmt.Errorf("cluster.broker must be memory or redis"))
+	}
+
+	if c.Cluster.Mode == "sharded" {
+		if c.Cluster.NodeID == "" || c.Cluster.Secret == "" {
+			errs = append(errs, fmt.Errorf("cluster.node_id and cluster.secret must be set in sharded mode"))
+		}
+
+		if len(c.Cluster.Peers) == 0 && c.Cluster.PeersFile == "" {
+			errs = append(errs, fmt.Errorf("cluster.peers or cluster.peers_file must be set in sharded mode"))
+		}
+	}
+
+	if c.Attachments.Dir != "" && c.Attachments.MaxSize <= 0 {
+		errs = append(errs, fmt.Errorf("attachments.max_size must be positive"))
+	}
+
+	if c.Journal.Dir != "" && c.Journal.CompactEvery <= 0 {
+		errs = append(errs, fmt.Errorf("journal.compact_every must be positive"))
+	}
+
+	if !slices.Contains([]string{"reject", "takeover"}, c.SessionPolicy) {
+		errs = append(errs, fmt.Errorf("session_policy must be reject or takeover"))
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		errs = append(errs, fmt.Errorf("tls.cert_file and tls.key_file must be set together"))
+	}
+
+	if c.WebSocket.ReadBufferSize <= 0 || c.WebSocket.WriteBufferSize <= 0 {
+		errs = append(errs, fmt.Errorf("websocket buffer sizes must be positive"))
+	}
+
+	if c.WebSocket.MaxMessageSize <= 0 {
+		errs = append(errs, fmt.Errorf("websocket.max_message_size must be positive"))
+	}
+
+	if c.WebSocket.PingInterval < 0 || c.WebSocket.IdleTimeout < 0 {
+		errs = append(errs, fmt.Errorf("websocket.ping_interval and websocket.idle_timeout must not be negative"))
+	}
+
+	if c.WebSocket.IdleTimeout > 0 && c.WebSocket.PingInterval >= c.WebSocket.IdleTimeout {
+		errs = append(errs, fmt.Errorf("websocket.ping_interval must be shorter than websocket.idle_timeout"))
+	}
+
+	if c.RateLimit.MessagesPerSecond < 0 || (c.RateLimit.MessagesPerSecond > 0 && c.RateLimit.Burst <= 0) {
+		errs = append(errs, fmt.Errorf("rate_limit must have a non-negative rate and a positive burst"))
+	}
+
+	if c.Auth.SessionTTL <= 0 {
+		errs = append(errs, fmt.Errorf("auth.session_ttl must be positive"))
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("shutdown_timeout must be positive"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// bind registers a flag for every setting, named after its key.
+func (c *Config) bind(fs *flag.FlagSet) {
+	fs.StringVar(&c.HTTP.Addr, "http.addr", c.HTTP.Addr, "WebSocket and HTTP listen address")
+	fs.StringVar(&c.GRPC.Addr, "grpc.addr", c.GRPC.Addr, "gRPC listen address")
+	fs.StringVar(&c.TLS.CertFile, "tls.cert_file", c.TLS.CertFile, "TLS certificate file")
+	fs.StringVar(&c.TLS.KeyFile, "tls.key_file", c.TLS.KeyFile, "TLS key file")
+	fs.IntVar(&c.WebSocket.ReadBufferSize, "websocket.read_buffer_size", c.WebSocket.ReadBufferSize, "WebSocket read buffer size in bytes")
+	fs.IntVar(&c.WebSocket.WriteBufferSize, "websocket.write_buffer_size", c.WebSocket.WriteBufferSize, "WebSocket write buffer size in bytes")
+	fs.Var((*listValue)(&c.WebSocket.AllowedOrigins), "websocket.allowed_origins", "comma-separated origins allowed to connect, * for any")
+	fs.Int64Var(&c.WebSocket.MaxMessageSize, "websocket.max_message_size", c.WebSocket.MaxMessageSize, "maximum size of a client message in bytes")
+	fs.DurationVar(&c.WebSocket.PingInterval, "websocket.ping_interval", c.WebSocket.PingInterval, "interval between pings sent to clients, 0 to disable")
+	fs.DurationVar(&c.WebSocket.IdleTimeout, "websocket.idle_timeout", c.WebSocket.IdleTimeout, "time after which silent clients are disconnected, 0 to disable")
+	fs.Float64Var(&c.RateLimit.MessagesPerSecond, "rate_limit.messages_per_second", c.RateLimit.MessagesPerSecond, "messages per second allowed per connection, 0 to disable")
+	fs.IntVar(&c.RateLimit.Burst, "rate_limit.burst", c.RateLimit.Burst, "messages a connection may send in a burst")
+	fs.StringVar(&c.Log.Level, "log.level", c.Log.Level, "log level: debug, info, warn or error")
+	fs.StringVar(&c.Log.Format, "log.format", c.Log.Format, "log format: text or json")
+	fs.StringVar(&c.Auth.JWTSecret, "auth.jwt_secret", c.Auth.JWTSecret, "secret of HS256-signed JWTs, disabled when empty")
+	fs.StringVar(&c.Auth.APIKeysFile, "auth.api_keys_file", c.Auth.APIKeysFile, "API key file, disabled when empty")
+	fs.DurationVar(&c.Auth.SessionTTL, "auth.session_ttl", c.Auth.SessionTTL, "lifetime of login sessions")
+	fs.BoolVar(&c.Metrics.Enabled, "metrics.enabled", c.Metrics.Enabled, "expose Prometheus metrics")
+	fs.StringVar(&c.Metrics.Path, "metrics.path", c.Metrics.Path, "path of the Prometheus metrics")
+	fs.StringVar(&c.Tracing.Exporter, "tracing.exporter", c.Tracing.Exporter, "trace exporter: none, stdout, file or otlp")
+	fs.StringVar(&c.Tracing.File, "tracing.file", c.Tracing.File, "file traces are written to by the file exporter")
+	fs.StringVar(&c.Tracing.Endpoint, "tracing.endpoint", c.Tracing.Endpoint, "OTLP gRPC collector address")
+	fs.BoolVar(&c.Tracing.Insecure, "tracing.insecure", c.Tracing.Insecure, "connect to the OTLP collector without TLS")
+	fs.Float64Var(&c.Tracing.SampleRatio, "tracing.sample_ratio", c.Tracing.SampleRatio, "fraction of traces sampled")
+	fs.StringVar(&c.Accounts.File, "accounts.file", c.Accounts.File, "user account file")
+	fs.Var((*listValue)(&c.Accounts.Reserved), "accounts.reserved", "comma-separated usernames that can't be registered")
+	fs.StringVar(&c.Admin.Addr, "admin.addr", c.Admin.Addr, "admin API listen address, disabled when empty")
+	fs.Var((*listValue)(&c.Admin.Users), "admin.users", "comma-separated administrator usernames")
+	fs.Var((*listValue)(&c.Admin.Groups), "admin.groups", "comma-separated groups whose members are administrators")
+	fs.StringVar(&c.Cluster.Mode, "cluster.mode", c.Cluster.Mode, "cluster mode: replicated or sharded")
+	fs.StringVar(&c.Cluster.Broker, "cluster.broker", c.Cluster.Broker, "broker shared by the nodes of the cluster: memory or redis")
+	fs.StringVar(&c.Cluster.RedisAddr, "cluster.redis_addr", c.Cluster.RedisAddr, "Redis address of the redis broker")
+	fs.StringVar(&c.Cluster.NodeID, "cluster.node_id", c.Cluster.NodeID, "identifier of the node in the cluster, generated when empty in replicated mode")
+	fs.StringVar(&c.Cluster.Addr, "cluster.addr", c.Cluster.Addr, "listen address of the calls of other nodes in sharded mode")
+	fs.Var((*listValue)(&c.Cluster.Peers), "cluster.peers", "comma-separated <node>=<url> peers in sharded mode")
+	fs.StringVar(&c.Cluster.PeersFile, "cluster.peers_file", c.Cluster.PeersFile, "peer file replacing cluster.peers, reloaded when changed")
+	fs.StringVar(&c.Cluster.Secret, "cluster.secret", c.Cluster.Secret, "secret shared by the nodes in sharded mode")
+	fs.StringVar(&c.Attachments.Dir, "attachments.dir", c.Attachments.Dir, "directory of uploaded files, uploads disabled when empty")
+	fs.Int64Var(&c.Attachments.MaxSize, "attachments.max_size", c.Attachments.MaxSize, "maximum size of an uploaded file in bytes")
+	fs.Var((*listValue)(&c.Attachments.AllowedTypes), "attachments.allowed_types", "comma-separated MIME types of uploaded files, type/* wildcards allowed")
+	fs.StringVar(&c.Journal.Dir, "journal.dir", c.Journal.Dir, "directory of the journal the state is rebuilt from on restart, kept in memory only when empty")
+	fs.IntVar(&c.Journal.CompactEvery, "journal.compact_every", c.Journal.CompactEvery, "number of journal records compacted into a snapshot")
+	fs.StringVar(&c.SessionPolicy, "session_policy", c.SessionPolicy, "duplicate session policy: reject or takeover")
+	fs.DurationVar(&c.ShutdownTimeout, "shutdown_timeout", c.ShutdownTimeout, "time given to in-flight commands to complete on shutdown")
+}
+
+// loadFile reads a YAML file. JSON being a subset of YAML, JSON files are
+// read the same way.
+func (c *Config) loadFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(c)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return nil
+}
+
+func envName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+type listValue []string
+
+func (l *listValue) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listValue) Set(value string) error {
+	*l = nil
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"practice-run/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type Suite struct {
+	suite.Suite
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) TestLoad() {
+	s.Run("defaults", func() {
+		// When
+		cfg, err := config.Load(nil)
+
+		// Then
+		s.NoError(err)
+		s.Equal(config.Default(), cfg)
+	})
+
+	s.Run("yaml file", func() {
+		// Given
+		path := s.writeFile("config.yaml", `
+http:
+  addr: ":80"
+websocket:
+  allowed_origins: ["https://chat.example.com"]
+auth:
+  session_ttl: 1h
+`)
+
+		// When
+		cfg, err := config.Load([]string{"-config", path})
+
+		// Then
+		s.NoError(err)
+		s.Equal(":80", cfg.HTTP.Addr)
+		s.Equal([]string{"https://chat.example.com"}, cfg.WebSocket.AllowedOrigins)
+		s.Equal(time.Hour, cfg.Auth.SessionTTL)
+		s.Equal(":9090", cfg.GRPC.Addr)
+	})
+
+	s.Run("json file", func() {
+		// Given
+		path := s.writeFile("config.json", `{"rate_limit": {"messages_per_second": 2.5, "burst": 5}}`)
+
+		// When
+		cfg, err := config.Load([]string{"-config", path})
+
+		// Then
+		s.NoError(err)
+		s.Equal(2.5, cfg.RateLimit.MessagesPerSecond)
+		s.Equal(5, cfg.RateLimit.Burst)
+	})
+
+	s.Run("config file from the environment", func() {
+		// Given
+		path := s.writeFile("config.yaml", `session_policy: takeover`)
+		s.T().Setenv("PRACTICE_RUN_CONFIG", path)
+
+		// When
+		cfg, err := config.Load(nil)
+
+		// Then
+		s.NoError(err)
+		s.Equal("takeover", cfg.SessionPolicy)
+	})
+
+	s.Run("environment overrides file", func() {
+		// Given
+		path := s.writeFile("config.yaml", "http:\n  addr: \":80\"\nlog:\n  level: warn\n")
+		s.T().Setenv("PRACTICE_RUN_HTTP_ADDR", ":81")
+		s.T().Setenv("PRACTICE_RUN_WEBSOCKET_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com")
+
+		// When
+		cfg, err := config.Load([]string{"-config", path})
+
+		// Then
+		s.NoError(err)
+		s.Equal(":81", cfg.HTTP.Addr)
+		s.Equal("warn", cfg.Log.Level)
+		s.Equal([]string{"https://a.example.com", "https://b.example.com"}, cfg.WebSocket.AllowedOrigins)
+	})
+
+	s.Run("flags override environment and file", func() {
+		// Given
+		path := s.writeFile("config.yaml", "http:\n  addr: \":80\"\n")
+		s.T().Setenv("PRACTICE_RUN_HTTP_ADDR", ":81")
+
+		// When
+		cfg, err := config.Load([]string{"-config", path, "-http.addr", ":82"})
+
+		// Then
+		s.NoError(err)
+		s.Equal(":82", cfg.HTTP.Addr)
+	})
+
+	s.Run("unknown file keys", func() {
+		// Given
+		path := s.writeFile("config.yaml", "http:\n  address: \":80\"\n")
+
+		// When
+		_, err := config.Load([]string{"-config", path})
+
+		// Then
+		s.Error(err)
+	})
+
+	s.Run("invalid environment values", func() {
+		// Given
+		s.T().Setenv("PRACTICE_RUN_RATE_LIMIT_BURST", "many")
+
+		// When
+		_, err := config.Load(nil)
+
+		// Then
+		s.ErrorContains(err, "PRACTICE_RUN_RATE_LIMIT_BURST")
+	})
+
+	s.Run("invalid values", func() {
+		// When
+		_, err := config.Load([]string{"-log.format", "xml", "-tls.cert_file", "cert.pem", "-websocket.ping_interval", "2m", "-cluster.broker", "kafka", "-cluster.mode", "sharded", "-attachments.max_size", "0", "-journal.dir", "journal", "-journal.compact_every", "0"})
+
+		// Then
+		s.ErrorContains(err, "log.format")
+		s.ErrorContains(err, "cluster.broker")
+		s.ErrorContains(err, "cluster.secret")
+		s.ErrorContains(err, "attachments.max_size")
+		s.ErrorContains(err, "journal.compact_every")
+		s.ErrorContains(err, "tls.key_file")
+		s.ErrorContains(err, "websocket.ping_interval")
+	})
+}
+
+func (s *Suite) writeFile(name, content string) string {
+	path := filepath.Join(s.T().TempDir(), name)
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
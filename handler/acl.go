@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"practice-run/chat"
+	"regexp"
+	"strings"
+)
+
+var ACLCommandRegex = regexp.MustCompile(`^/(?P<command>acl)\s+#(?P<roomName>\w+)(?:\s+(?P<remove>remove\s+)?(?P<rule>(?:allow|deny)\s+.+))?$`)
+
+type ACLCommandFactory struct{}
+
+func (f *ACLCommandFactory) CreateCommand(match []string) (Command, error) {
+	if match[4] == "" {
+		return &ViewACLCommand{RoomName: match[2]}, nil
+	}
+
+	rule, err := chat.ParseRule(match[4])
+	if err != nil {
+		return nil, err
+	}
+
+	return &EditACLCommand{RoomName: match[2], Rule: rule, Remove: match[3] != ""}, nil
+}
+
+type ViewACLCommand struct {
+	RoomName string
+}
+
+func (c *ViewACLCommand) Name() string {
+	return "view_acl"
+}
+
+func (c *ViewACLCommand) Room() string {
+	return c.RoomName
+}
+
+func (c *ViewACLCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	rules, err := service.GetACL(ctx, c.RoomName, m)
+	if err != nil {
+		return fmt.Errorf("failed to view acl: %w", err)
+	}
+
+	if len(rules) == 0 {
+		m.WriteMessage(fmt.Sprintf("#%s acl: no rules", c.RoomName))
+		return nil
+	}
+
+	lines := make([]string, 0, len(rules)+1)
+	lines = append(lines, fmt.Sprintf("#%s acl:", c.RoomName))
+	for _, rule := range rules {
+		lines = append(lines, rule.String())
+	}
+
+	m.WriteMessage(strings.Join(lines, "\n"))
+
+	return nil
+}
+
+type EditACLCommand struct {
+	RoomName string
+	Rule     chat.Rule
+	Remove   bool
+}
+
+func (c *EditACLCommand) Name() string {
+	return "edit_acl"
+}
+
+func (c *EditACLCommand) Room() string {
+	return c.RoomName
+}
+
+func (c *EditACLCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	if c.Remove {
+		err := service.RemoveACLRule(ctx, c.RoomName, m, c.Rule)
+		if err != nil {
+			return fmt.Errorf("failed to remove acl rule: %w", err)
+		}
+
+		m.WriteMessage(fmt.Sprintf("#%s acl: removed %s", c.RoomName, c.Rule))
+
+		return nil
+	}
+
+	err := service.AddACLRule(ctx, c.RoomName, m, c.Rule)
+	if err != nil {
+		return fmt.Errorf("failed to add acl rule: %w", err)
+	}
+
+	m.WriteMessage(fmt.Sprintf("#%s acl: added %s", c.RoomName, c.Rule))
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/chat"
+	"strings"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestACL() {
+	s.Run("view", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().GetACL(gomock.Any(), "room_1", gomock.Any()).Return([]chat.Rule{
+			{Effect: chat.EffectAllow, Permission: chat.PermissionManage, Subject: "@user_1"},
+			{Effect: chat.EffectDeny, Permission: chat.PermissionPost, Subject: chat.Everyone},
+		}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/acl #room_1`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("#room_1 acl:\nallow manage @user_1\ndeny post *", string(msg))
+	})
+
+	s.Run("add rule", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		rule := chat.Rule{Effect: chat.EffectAllow, Permission: chat.PermissionPost, Subject: "@release_bot"}
+		s.chatService.EXPECT().AddACLRule(gomock.Any(), "room_1", gomock.Any(), rule).Return(nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/acl #room_1 allow post @release_bot`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("#room_1 acl: added allow post @release_bot", string(msg))
+	})
+
+	s.Run("remove rule", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		rule := chat.Rule{Effect: chat.EffectDeny, Permission: chat.PermissionJoin, Subject: "%interns"}
+		s.chatService.EXPECT().RemoveACLRule(gomock.Any(), "room_1", gomock.Any(), rule).Return(nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/acl #room_1 remove deny join %interns`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("#room_1 acl: removed deny join %interns", string(msg))
+	})
+
+	s.Run("permission denied", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().AddACLRule(gomock.Any(), "room_1", gomock.Any(), gomock.Any()).Return(chat.ErrPermissionDenied)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/acl #room_1 deny post *`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("error: failed to add acl rule: permission denied", string(msg))
+	})
+
+	s.Run("invalid rule", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/acl #room_1 deny shout *`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.True(strings.HasPrefix(string(msg), "error: bad request"), string(msg))
+	})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"practice-run/chat"
+	"regexp"
+)
+
+var AnnounceCommandRegex = regexp.MustCompile(`^/(?P<command>announce)\s+(?:(?P<severity>info|warning|critical):\s+)?(?P<message>.+)$`)
+
+type AnnounceCommandFactory struct{}
+
+func (f *AnnounceCommandFactory) CreateCommand(match []string) (Command, error) {
+	severity, err := chat.ParseSeverity(match[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnnounceCommand{Severity: severity, Message: match[3]}, nil
+}
+
+// AnnounceCommand sends a system announcement to every connected user. Only
+// administrators may use it.
+type AnnounceCommand struct {
+	Severity chat.Severity
+	Message  string
+}
+
+func (c *AnnounceCommand) Name() string {
+	return "announce"
+}
+
+func (c *AnnounceCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	if !m.IsAdmin() {
+		return fmt.Errorf("failed to announce: %w", chat.ErrPermissionDenied)
+	}
+
+	err := service.Broadcast(ctx, &chat.SystemAnnouncementEvent{Message: c.Message, Severity: c.Severity})
+	if err != nil {
+		return fmt.Errorf("failed to announce: %w", err)
+	}
+
+	return nil
+}
+
+type SystemAnnouncementHandler struct{}
+
+func (h *SystemAnnouncementHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.SystemAnnouncementEvent)
+
+	if e.Severity == "" || e.Severity == chat.SeverityInfo {
+		m.WriteMessage(fmt.Sprintf("announcement: %s", e.Message))
+	} else {
+		m.WriteMessage(fmt.Sprintf("announcement [%s]: %s", e.Severity, e.Message))
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"practice-run/chat"
+	"practice-run/handler"
+	"slices"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/mock/gomock"
+)
+
+type mockAdmins []string
+
+func (a mockAdmins) IsAdmin(username string) bool {
+	return slices.Contains(a, username)
+}
+
+func (s *Suite) TestAnnounce() {
+	s.Run("broadcast announcements from admins", func() {
+		// Given
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, s.chatService, handler.Options{
+			Admins: mockAdmins{"admin"},
+		}))
+		defer server.Close()
+
+		s.chatService.EXPECT().Broadcast(gomock.Any(), &chat.SystemAnnouncementEvent{
+			Message:  "maintenance at noon",
+			Severity: chat.SeverityWarning,
+		}).Return(nil)
+
+		conn := s.createConnection(server, "admin")
+
+		// When
+		s.writeMessage(conn, `/announce warning: maintenance at noon`)
+		s.writeMessage(conn, `/ping`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Regexp(`^pong: `, string(msg))
+	})
+
+	s.Run("default to info severity", func() {
+		// Given
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, s.chatService, handler.Options{
+			Admins: mockAdmins{"admin"},
+		}))
+		defer server.Close()
+
+		s.chatService.EXPECT().Broadcast(gomock.Any(), &chat.SystemAnnouncementEvent{
+			Message:  "warning: maintenance at noon is postponed",
+			Severity: chat.SeverityInfo,
+		}).Return(nil)
+
+		conn := s.createConnection(server, "admin")
+
+		// When
+		s.writeMessage(conn, `/announce info: warning: maintenance at noon is postponed`)
+		s.writeMessage(conn, `/ping`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Regexp(`^pong: `, string(msg))
+	})
+
+	s.Run("reject announcements from other users", func() {
+		// Given
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, s.chatService, handler.Options{
+			Admins: mockAdmins{"admin"},
+		}))
+		defer server.Close()
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/announce maintenance at noon`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal(`error: failed to announce: permission denied`, string(msg))
+	})
+
+	s.Run("render severity", func() {
+		// Given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+			s.Require().NoError(err)
+
+			member := handler.NewChatMember("user_1", conn)
+			member.Notify(&chat.SystemAnnouncementEvent{Message: "maintenance at noon", Severity: chat.SeverityInfo})
+			member.Notify(&chat.SystemAnnouncementEvent{Message: "database down", Severity: chat.SeverityCritical})
+		}))
+		defer server.Close()
+
+		// When
+		cn, _, err := websocket.DefaultDialer.Dial(wsUrl(server), nil)
+		s.Require().NoError(err)
+		defer cn.Close()
+
+		// Then
+		_, raw, _ := cn.ReadMessage()
+		s.Equal("announcement: maintenance at noon", string(raw))
+
+		_, raw, _ = cn.ReadMessage()
+		s.Equal("announcement [critical]: database down", string(raw))
+	})
+}
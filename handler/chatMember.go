@@ -2,47 +2,92 @@ package handler
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"practice-run/chat"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+// writeTimeout bounds the writes to a connection, for clients no longer
+// reading from it not to block the members notifying them.
+const writeTimeout = time.Second
+
 type EventHandler interface {
 	Handle(event chat.Event, m *ChatMember) error
 }
 
 type ChatMember struct {
-	mu   sync.Mutex
-	conn *websocket.Conn
+	mu     sync.Mutex
+	conn   *websocket.Conn
+	closed bool
+	done   chan struct{} // closed by Close
+
+	idleTimeout time.Duration
+	pings       uint64
+	pending     map[string]pendingPing // pings awaiting a pong, by payload
 
 	username string
+	admin    bool
 	handlers map[string]EventHandler
+	logger   *slog.Logger
 }
 
 func NewChatMember(username string, conn *websocket.Conn) *ChatMember {
-	return &ChatMember{
+	m := &ChatMember{
 		username: username,
 		conn:     conn,
+		done:     make(chan struct{}),
+		pending:  map[string]pendingPing{},
+		logger:   slog.Default(),
 		handlers: map[string]EventHandler{
-			chat.MessageReceivedEventName: &MessageReceivedHandler{},
-			chat.MemberJoinedEventName:    &MemberJoinedHandler{},
-			chat.MemberLeftEventName:      &MemberLeftHandler{},
+			chat.MessageReceivedEventName:    &MessageReceivedHandler{},
+			chat.MessageEditedEventName:      &MessageEditedHandler{},
+			chat.MentionEventName:            &MentionHandler{},
+			chat.TypingEventName:             &TypingHandler{},
+			chat.ReadReceiptEventName:        &ReadReceiptHandler{},
+			chat.MessageDeletedEventName:     &MessageDeletedHandler{},
+			chat.ReactionAddedEventName:      &ReactionAddedHandler{},
+			chat.ReactionRemovedEventName:    &ReactionRemovedHandler{},
+			chat.MemberJoinedEventName:       &MemberJoinedHandler{},
+			chat.MemberLeftEventName:         &MemberLeftHandler{},
+			chat.InvitedEventName:            &InvitedHandler{},
+			chat.SessionTakenOverEventName:   &SessionTakenOverHandler{},
+			chat.ServerShutdownEventName:     &ServerShutdownHandler{},
+			chat.SystemAnnouncementEventName: &SystemAnnouncementHandler{},
+			chat.DisconnectedEventName:       &DisconnectedHandler{},
+			chat.RemovedEventName:            &RemovedHandler{},
+			chat.RoomDeletedEventName:        &RoomDeletedHandler{},
 		},
 	}
+
+	conn.SetPongHandler(m.handlePong)
+
+	return m
 }
 
 func (m *ChatMember) Username() string {
 	return m.username
 }
 
+// IsAdmin reports whether the member is an administrator.
+func (m *ChatMember) IsAdmin() bool {
+	return m.admin
+}
+
+func (m *ChatMember) RemoteAddr() string {
+	return m.conn.RemoteAddr().String()
+}
+
 func (m *ChatMember) ReadMessage() (string, error, bool) {
 	mt, raw, err := m.conn.ReadMessage()
 	if err != nil {
 		return "", fmt.Errorf("failed to read message: %w", err), false
 	}
 
+	m.extendReadDeadline()
+
 	if mt != websocket.TextMessage {
 		return "", fmt.Errorf("bad request: only text messages are supported"), true
 	}
@@ -54,21 +99,52 @@ func (m *ChatMember) WriteMessage(message string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.closed {
+		return
+	}
+
+	_ = m.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
 	err := m.conn.WriteMessage(websocket.TextMessage, []byte(message))
 	if err != nil {
-		log.Printf("Error: failed to write message to member %s: %v", m.username, err)
+		m.logger.Error("failed to write message", slog.Any("error", err))
+
+		// The connection can't be written to after a failed write: closing
+		// it makes the pending ReadMessage fail, which disconnects the member
+		m.closed = true
+		close(m.done)
+		_ = m.conn.Close()
 	}
 }
 
+// Close sends a close frame with the given code and reason and closes the
+// connection, which makes any pending ReadMessage fail. Later writes are
+// dropped.
+func (m *ChatMember) Close(code int, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil
+	}
+
+	m.closed = true
+	close(m.done)
+
+	_ = m.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeTimeout))
+
+	return m.conn.Close()
+}
+
 func (m *ChatMember) Notify(event chat.Event) {
 	handler, ok := m.handlers[event.Name()]
 	if !ok {
-		log.Printf("Error: failed to notify member %s: unknown event %s", m.username, event.Name())
+		m.logger.Error("failed to notify member: unknown event", slog.String("event", event.Name()))
 		return
 	}
 
 	err := handler.Handle(event, m)
 	if err != nil {
-		log.Printf("Error: failed to notify member %s: %v", m.username, err)
+		m.logger.Error("failed to notify member", slog.String("event", event.Name()), slog.Any("error", err))
 	}
 }
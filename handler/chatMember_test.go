@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"practice-run/chat"
 	"practice-run/handler"
+	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -24,10 +26,91 @@ func (s *Suite) TestChatMember() {
 
 		member.Notify(&chat.MessageReceivedEvent{
 			RoomName:   "room_1",
+			MessageID:  "0a1b2c3d4e5f",
 			SenderName: "member_1",
 			Message:    "hello",
 		})
 
+		member.Notify(&chat.MessageReceivedEvent{
+			RoomName:   "room_1",
+			MessageID:  "f5e4d3c2b1a0",
+			SenderName: "member_2",
+			Message:    "hi",
+			ParentID:   "0a1b2c3d4e5f",
+			Replies:    1,
+		})
+
+		member.Notify(&chat.MentionEvent{
+			RoomName:   "room_1",
+			MessageID:  "f5e4d3c2b1a0",
+			SenderName: "member_2",
+			Message:    "@test hi",
+			Mention:    "test",
+		})
+
+		member.Notify(&chat.MentionEvent{
+			RoomName:   "room_1",
+			MessageID:  "f5e4d3c2b1a0",
+			SenderName: "member_2",
+			Message:    "@here deploying",
+			ParentID:   "0a1b2c3d4e5f",
+			Mention:    chat.MentionHere,
+		})
+
+		member.Notify(&chat.TypingEvent{
+			RoomName:  "room_1",
+			Username:  "member_2",
+			ExpiresAt: time.Date(2024, 5, 1, 12, 0, 5, 0, time.UTC),
+		})
+
+		member.Notify(&chat.ReadReceiptEvent{
+			RoomName:  "room_1",
+			MessageID: "0a1b2c3d4e5f",
+			Username:  "member_2",
+		})
+
+		member.Notify(&chat.MessageEditedEvent{
+			RoomName:   "room_1",
+			MessageID:  "0a1b2c3d4e5f",
+			SenderName: "member_1",
+			Message:    "hello, world!",
+		})
+
+		member.Notify(&chat.MessageDeletedEvent{
+			RoomName:  "room_1",
+			MessageID: "0a1b2c3d4e5f",
+			DeletedBy: "member_2",
+		})
+
+		member.Notify(&chat.ReactionAddedEvent{
+			RoomName:  "room_1",
+			MessageID: "0a1b2c3d4e5f",
+			Username:  "member_2",
+			Emoji:     "👍",
+			Count:     1,
+		})
+
+		member.Notify(&chat.ReactionRemovedEvent{
+			RoomName:  "room_1",
+			MessageID: "0a1b2c3d4e5f",
+			Username:  "member_2",
+			Emoji:     "👍",
+		})
+
+		member.Notify(&chat.MessageReceivedEvent{
+			RoomName:   "room_1",
+			SenderName: "member_1",
+			Message:    "logs",
+			Attachments: []chat.Attachment{
+				{ID: "a1b2c3", Name: "server.log", Size: 18, Type: "text/plain", URL: "/attachments/a1b2c3"},
+				{
+					ID: "d4e5f6", Name: "screenshot.png", Size: 4096, Type: "image/png", URL: "/attachments/d4e5f6",
+					Width: 1024, Height: 512,
+					Thumbnail: &chat.Thumbnail{URL: "/attachments/d4e5f6/thumbnail", Width: 256, Height: 128},
+				},
+			},
+		})
+
 		member.Notify(&chat.MemberLeftEvent{
 			RoomName:   "room_1",
 			MemberName: "member_1",
@@ -39,7 +122,7 @@ func (s *Suite) TestChatMember() {
 	defer server.Close()
 
 	// When
-	cn, _, err := websocket.DefaultDialer.Dial(wsUrl(server, "member_1"), nil)
+	cn, _, err := websocket.DefaultDialer.Dial(wsUrl(server), nil)
 	s.Require().NoError(err)
 	defer cn.Close()
 
@@ -48,7 +131,39 @@ func (s *Suite) TestChatMember() {
 	s.Equal("#room_1: @member_1 joined", string(raw))
 
 	_, raw, _ = cn.ReadMessage()
-	s.Equal("#room_1: @member_1: hello", string(raw))
+	s.Equal("[0a1b2c3d4e5f] #room_1: @member_1: hello", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("[f5e4d3c2b1a0] #room_1: @member_2 replied to [0a1b2c3d4e5f] (1 replies): hi", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("! [f5e4d3c2b1a0] #room_1: @member_2 mentioned you: @test hi", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("! [f5e4d3c2b1a0] #room_1: @member_2 mentioned @here in [0a1b2c3d4e5f]: @here deploying", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("#room_1: @member_2 is typing until 2024-05-01T12:00:05Z", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("[0a1b2c3d4e5f] #room_1: @member_2 read your message", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("[0a1b2c3d4e5f] #room_1: @member_1 (edited): hello, world!", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("[0a1b2c3d4e5f] #room_1: message deleted by @member_2", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("[0a1b2c3d4e5f] #room_1: @member_2 reacted 👍 (1)", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("[0a1b2c3d4e5f] #room_1: @member_2 removed reaction 👍 (0)", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("#room_1: @member_1: logs\n"+
+		"  attachment a1b2c3: server.log (text/plain, 18 bytes) /attachments/a1b2c3\n"+
+		"  attachment d4e5f6: screenshot.png (image/png, 4096 bytes) /attachments/d4e5f6, 1024x512, thumbnail 256x128 /attachments/d4e5f6/thumbnail", string(raw))
 
 	_, raw, _ = cn.ReadMessage()
 	s.Equal("#room_1: @member_1 left", string(raw))
@@ -56,3 +171,36 @@ func (s *Suite) TestChatMember() {
 	_, raw, _ = cn.ReadMessage()
 	s.Equal("test message", string(raw))
 }
+
+func (s *Suite) TestChatMemberWriteTimeout() {
+	// Given
+	closed := make(chan time.Duration, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		s.Require().NoError(err)
+
+		member := handler.NewChatMember("test", conn)
+		message := strings.Repeat("x", 1<<20)
+
+		// When
+		start := time.Now()
+		for range 64 {
+			member.WriteMessage(message)
+		}
+		_ = member.Close(websocket.CloseNormalClosure, "")
+		closed <- time.Since(start)
+	}))
+	defer server.Close()
+
+	cn, _, err := websocket.DefaultDialer.Dial(wsUrl(server), nil)
+	s.Require().NoError(err)
+	defer cn.Close()
+
+	// Then
+	select {
+	case elapsed := <-closed:
+		s.Less(elapsed, 5*time.Second)
+	case <-time.After(10 * time.Second):
+		s.Fail("writes to a client not reading weren't timed out")
+	}
+}
@@ -22,8 +22,12 @@ func (c *CreateRoomCommand) Name() string {
 	return "create_room"
 }
 
+func (c *CreateRoomCommand) Room() string {
+	return c.RoomName
+}
+
 func (c *CreateRoomCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
-	_, err := service.CreateRoom(ctx, c.RoomName)
+	_, err := service.CreateRoom(ctx, c.RoomName, m.Username())
 	if err != nil {
 		return fmt.Errorf("failed to create room: %w", err)
 	}
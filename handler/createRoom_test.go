@@ -15,7 +15,7 @@ func (s *Suite) TestCreateRoom() {
 		server := httptest.NewServer(s.handler)
 		defer server.Close()
 
-		s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1").Return(&chat.Room{}, nil)
+		s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1", "user_1").Return(&chat.Room{}, nil)
 
 		conn := s.createConnection(server, "user_1")
 
@@ -34,7 +34,7 @@ func (s *Suite) TestCreateRoom() {
 		server := httptest.NewServer(s.handler)
 		defer server.Close()
 
-		s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1").Return(nil, errors.New("some error"))
+		s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1", "user_1").Return(nil, errors.New("some error"))
 
 		conn := s.createConnection(server, "user_1")
 
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"practice-run/chat"
+	"regexp"
+)
+
+var DeleteCommandRegex = regexp.MustCompile(`^/(?P<command>delete)\s+(?P<id>[0-9a-f]+)$`)
+
+type DeleteCommandFactory struct{}
+
+func (f *DeleteCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &DeleteCommand{MessageID: match[2]}, nil
+}
+
+type DeleteCommand struct {
+	MessageID string
+}
+
+func (c *DeleteCommand) Name() string {
+	return "delete_message"
+}
+
+func (c *DeleteCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	deleted, err := service.DeleteMessage(ctx, m, c.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	m.WriteMessage(fmt.Sprintf("%s#%s: message deleted by @%s", messageID(deleted.ID), deleted.Room, deleted.DeletedBy))
+
+	return nil
+}
+
+type MessageDeletedHandler struct{}
+
+func (h *MessageDeletedHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.MessageDeletedEvent)
+	m.WriteMessage(fmt.Sprintf("%s#%s: message deleted by @%s", messageID(e.MessageID), e.RoomName, e.DeletedBy))
+	return nil
+}
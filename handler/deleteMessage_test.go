@@ -0,0 +1,47 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/chat"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestDeleteMessage() {
+	s.Run("ok", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().DeleteMessage(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f").
+			Return(&chat.Message{ID: "0a1b2c3d4e5f", Room: "room_1", Sender: "user_2", DeletedBy: "user_1"}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/delete 0a1b2c3d4e5f`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("[0a1b2c3d4e5f] #room_1: message deleted by @user_1", string(msg))
+	})
+
+	s.Run("message not found", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().DeleteMessage(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f").Return(nil, chat.ErrMessageNotFound)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/delete 0a1b2c3d4e5f`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("error: failed to delete message: message not found", string(msg))
+	})
+}
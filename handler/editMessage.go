@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"practice-run/chat"
+	"regexp"
+)
+
+var EditCommandRegex = regexp.MustCompile(`^/(?P<command>edit)\s+(?P<id>[0-9a-f]+)\s+(?P<message>.+)$`)
+
+type EditCommandFactory struct{}
+
+func (f *EditCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &EditCommand{MessageID: match[2], Message: match[3]}, nil
+}
+
+type EditCommand struct {
+	MessageID string
+	Message   string
+}
+
+func (c *EditCommand) Name() string {
+	return "edit_message"
+}
+
+func (c *EditCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	edited, err := service.EditMessage(ctx, m, c.MessageID, c.Message)
+	if err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+
+	m.WriteMessage(fmt.Sprintf("%s#%s: @%s (edited): %s", messageID(edited.ID), edited.Room, edited.Sender, edited.Text))
+
+	return nil
+}
+
+type MessageEditedHandler struct{}
+
+func (h *MessageEditedHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.MessageEditedEvent)
+	m.WriteMessage(fmt.Sprintf("%s#%s: @%s (edited): %s", messageID(e.MessageID), e.RoomName, e.SenderName, e.Message))
+	return nil
+}
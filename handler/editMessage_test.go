@@ -0,0 +1,47 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/chat"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestEditMessage() {
+	s.Run("ok", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().EditMessage(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f", "hello, world!").
+			Return(&chat.Message{ID: "0a1b2c3d4e5f", Room: "room_1", Sender: "user_1", Text: "hello, world!"}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/edit 0a1b2c3d4e5f hello, world!`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("[0a1b2c3d4e5f] #room_1: @user_1 (edited): hello, world!", string(msg))
+	})
+
+	s.Run("message of another member", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().EditMessage(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f", "hello").Return(nil, chat.ErrPermissionDenied)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/edit 0a1b2c3d4e5f hello`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("error: failed to edit message: permission denied", string(msg))
+	})
+}
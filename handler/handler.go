@@ -2,69 +2,279 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"practice-run/account"
+	"practice-run/auth"
 	"practice-run/chat"
+	"practice-run/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("practice-run/handler")
+
 //go:generate mockgen -destination mocks/chat_service_mock.go -mock_names chatService=ChatService -package mocks . chatService
 type chatService interface {
-	CreateRoom(ctx context.Context, roomName string) (*chat.Room, error)
+	Connect(ctx context.Context, member chat.Member) error
+	Takeover(ctx context.Context, member chat.Member) (chat.Member, error)
+	Disconnect(ctx context.Context, member chat.Member) error
+	IsConnected(ctx context.Context, username string) bool
+	CreateRoom(ctx context.Context, roomName string, owner string) (*chat.Room, error)
 	AddMember(ctx context.Context, roomName string, member chat.Member) error
 	RemoveMember(ctx context.Context, roomName string, member chat.Member) error
-	SendMessage(ctx context.Context, roomName string, member chat.Member, message string) error
+	SendMessage(ctx context.Context, roomName string, member chat.Member, message string, attachments ...string) (*chat.Message, error)
+	EditMessage(ctx context.Context, member chat.Member, id string, text string) (*chat.Message, error)
+	DeleteMessage(ctx context.Context, member chat.Member, id string) (*chat.Message, error)
+	React(ctx context.Context, member chat.Member, id string, emoji string) (*chat.Message, error)
+	Unreact(ctx context.Context, member chat.Member, id string, emoji string) (*chat.Message, error)
+	History(ctx context.Context, roomName string, member chat.Member, limit int) ([]*chat.Message, error)
+	Reply(ctx context.Context, member chat.Member, parentID string, message string, attachments ...string) (*chat.Message, error)
+	Thread(ctx context.Context, member chat.Member, id string) ([]*chat.Message, error)
+	Follow(ctx context.Context, member chat.Member, id string) (*chat.Message, error)
+	Unfollow(ctx context.Context, member chat.Member, id string) (*chat.Message, error)
+	Typing(ctx context.Context, roomName string, member chat.Member) error
+	MarkRead(ctx context.Context, roomName string, member chat.Member, id string) (int, error)
+	Unread(ctx context.Context, username string) ([]chat.UnreadCount, error)
+	InviteMember(ctx context.Context, roomName string, inviter chat.Member, username string) error
+	GetACL(ctx context.Context, roomName string, member chat.Member) ([]chat.Rule, error)
+	AddACLRule(ctx context.Context, roomName string, member chat.Member, rule chat.Rule) error
+	RemoveACLRule(ctx context.Context, roomName string, member chat.Member, rule chat.Rule) error
+	Broadcast(ctx context.Context, event chat.Event) error
+	Search(ctx context.Context, member chat.Member, query string) ([]chat.SearchResult, error)
+}
+
+type admins interface {
+	IsAdmin(username string) bool
+}
+
+//go:generate mockgen -destination mocks/account_service_mock.go -mock_names accountService=AccountService -package mocks . accountService
+type accountService interface {
+	Register(ctx context.Context, username, password string) error
+	Login(ctx context.Context, username, password string) (*account.Session, error)
+}
+
+type Options struct {
+	SessionPolicy SessionPolicy
+	// MaxMessageSize is the maximum size in bytes of a client message. Larger
+	// messages close the connection. 0 means no limit.
+	MaxMessageSize int64
+	// RateLimit is the number of messages per second a connection may send,
+	// in bursts of up to RateLimitBurst messages. 0 means no limit.
+	RateLimit      float64
+	RateLimitBurst int
+	// PingInterval is the interval between pings sent to clients, and
+	// IdleTimeout the time after which a client from which nothing has been
+	// read, pongs included, is disconnected. 0 disables them.
+	PingInterval time.Duration
+	IdleTimeout  time.Duration
+	// Logger is the logger of the handler and its connections, slog.Default()
+	// if nil.
+	Logger *slog.Logger
+	// Admins decides which users are administrators. Nobody is if nil.
+	Admins admins
 }
 
 type WebSocketHandler struct {
-	upgrader    *websocket.Upgrader
-	chatService chatService
+	upgrader      *websocket.Upgrader
+	authenticator auth.Authenticator
+	chatService   chatService
+	options       Options
+
+	logger      *slog.Logger
+	connections atomic.Uint64 // last connection ID
+
+	mu       sync.Mutex
+	draining bool
+	inflight sync.WaitGroup // commands being executed
 }
 
-func NewWebSocketHandler(upgrader *websocket.Upgrader, chatService chatService) *WebSocketHandler {
-	return &WebSocketHandler{upgrader: upgrader, chatService: chatService}
+func NewWebSocketHandler(upgrader *websocket.Upgrader, authenticator auth.Authenticator, chatService chatService, options Options) *WebSocketHandler {
+	logger := options.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &WebSocketHandler{upgrader: upgrader, authenticator: authenticator, chatService: chatService, options: options, logger: logger}
 }
 
 func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Mocking the authentication
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		log.Printf("Debug: missing username")
-		http.Error(w, "missing username", http.StatusUnauthorized)
+	if h.isDraining() {
+		http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	logger := h.logger.With(slog.String("remote_addr", r.RemoteAddr))
+
+	token, subprotocol, err := auth.TokenFromRequest(r)
+	if err != nil {
+		logger.Debug("rejected connection", slog.Any("error", err))
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	username, err := h.authenticator.Authenticate(ctx, token)
+	if err != nil {
+		logger.Debug("rejected connection", slog.Any("error", err))
+		http.Error(w, auth.ErrInvalidCredentials.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	ctx, span := tracer.Start(
+		otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header)),
+		"websocket.connection",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("chat.username", username)),
+	)
+	defer span.End()
+
+	if h.options.SessionPolicy == RejectDuplicateSessions && h.chatService.IsConnected(ctx, username) {
+		logger.Debug("rejected connection", slog.String("username", username), slog.Any("error", chat.ErrAlreadyConnected))
+		http.Error(w, chat.ErrAlreadyConnected.Error(), http.StatusConflict)
 		return
 	}
 
-	conn, err := h.upgrader.Upgrade(w, r, nil)
+	var responseHeader http.Header
+	if subprotocol != "" {
+		responseHeader = http.Header{"Sec-Websocket-Protocol": {subprotocol}}
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, responseHeader)
 	if err != nil {
-		log.Printf("Error: failed to upgrade connection: %v", err)
+		logger.Error("failed to upgrade connection", slog.String("username", username), slog.Any("error", err))
 		return
 	}
 
+	logger = logger.With(slog.Uint64("conn_id", h.connections.Add(1)), slog.String("username", username))
+
+	if h.options.MaxMessageSize > 0 {
+		conn.SetReadLimit(h.options.MaxMessageSize)
+	}
+
 	member := NewChatMember(username, conn)
+	member.logger = logger
+	member.admin = h.options.Admins != nil && h.options.Admins.IsAdmin(username)
 
-	log.Printf("Debug: new connection from %s", username)
+	err = h.connect(ctx, member)
+	if err != nil {
+		logger.Debug("rejected connection", slog.Any("error", err))
+		_ = member.Close(websocket.ClosePolicyViolation, err.Error())
+		return
+	}
+	defer func() {
+		_ = h.chatService.Disconnect(ctx, member)
+	}()
+
+	member.KeepAlive(h.options.PingInterval, h.options.IdleTimeout)
+
+	logger.Debug("new connection")
+
+	// The rooms left by disconnecting, and those of taken over sessions, may
+	// have unread messages
+	counts, err := h.chatService.Unread(ctx, username)
+	if err != nil {
+		logger.Warn("failed to count unread messages", slog.Any("error", err))
+	} else if len(counts) > 0 {
+		member.WriteMessage(unreadMessage(counts))
+	}
+
+	limiter := newRateLimiter(h.options.RateLimit, h.options.RateLimitBurst)
 
 	for {
 		msg, err, ok := member.ReadMessage()
 		if !ok {
-			log.Printf("Error: connection closed: %v", err)
+			logger.Debug("connection closed", slog.Any("error", err))
+			reason := ""
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				reason = "idle timeout"
+			}
+			_ = member.Close(websocket.CloseNormalClosure, reason)
 			break
 		}
 
+		if !limiter.Allow() {
+			member.WriteMessage("error: rate limit exceeded")
+			continue
+		}
+
 		cmd, err := ParseMessage(msg)
 		if err != nil {
+			metrics.ParseFailures.Inc()
 			member.WriteMessage(fmt.Sprintf("error: bad request: failed to parse message: %v", err))
 			continue
 		}
 
-		err = cmd.Execute(ctx, member, h.chatService)
-		if err != nil {
-			log.Printf("Debug: failed to execute command %s %+v: %v", cmd.Name(), cmd, err)
-			member.WriteMessage(fmt.Sprintf("error: %v", err))
+		h.execute(ctx, cmd, member)
+	}
+}
+
+func (h *WebSocketHandler) execute(ctx context.Context, cmd Command, member *ChatMember) {
+	if !h.begin() {
+		member.WriteMessage("error: server shutting down")
+		return
+	}
+	defer h.inflight.Done()
+
+	ctx, span := tracer.Start(ctx, "command "+cmd.Name(), trace.WithAttributes(
+		attribute.String("chat.command", cmd.Name()),
+		attribute.String("chat.username", member.Username()),
+	))
+	defer span.End()
+
+	if cmd, ok := cmd.(RoomCommand); ok {
+		span.SetAttributes(attribute.String("chat.room", cmd.Room()))
+	}
+
+	start := time.Now()
+	err := cmd.Execute(ctx, member, h.chatService)
+	duration := time.Since(start)
+	metrics.CommandDuration.WithLabelValues(cmd.Name()).Observe(duration.Seconds())
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		metrics.Commands.WithLabelValues(cmd.Name(), metrics.ResultError).Inc()
+		member.logger.Debug("failed to execute command", append(commandAttributes(cmd), slog.Any("error", err))...)
+		member.WriteMessage(fmt.Sprintf("error: %v", err))
+		return
+	}
+
+	metrics.Commands.WithLabelValues(cmd.Name(), metrics.ResultOK).Inc()
+	member.logger.Debug("executed command", append(commandAttributes(cmd), slog.Duration("duration", duration))...)
+}
+
+// commandAttributes returns the attributes identifying cmd in logs.
+func commandAttributes(cmd Command) []any {
+	attributes := []any{slog.String("command", cmd.Name())}
+	if cmd, ok := cmd.(RoomCommand); ok {
+		attributes = append(attributes, slog.String("room", cmd.Room()))
+	}
+
+	return attributes
+}
+
+func (h *WebSocketHandler) connect(ctx context.Context, member *ChatMember) error {
+	if h.options.SessionPolicy == TakeOverSessions {
+		previous, err := h.chatService.Takeover(ctx, member)
+		if previous != nil {
+			member.logger.Debug("took over the previous session")
 		}
+		return err
 	}
+
+	return h.chatService.Connect(ctx, member)
 }
@@ -3,31 +3,50 @@ package handler_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"practice-run/auth"
 	"practice-run/handler"
 	"practice-run/handler/mocks"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
 )
 
 type Suite struct {
 	suite.Suite
-	ctrl        *gomock.Controller
-	chatService *mocks.ChatService
-	handler     *handler.WebSocketHandler
+	ctrl          *gomock.Controller
+	chatService   *mocks.ChatService
+	authenticator *auth.JWTAuthenticator
+	handler       *handler.WebSocketHandler
+	spans         *tracetest.SpanRecorder
 }
 
 func TestSuite(t *testing.T) {
 	suite.Run(t, new(Suite))
 }
 
+func (s *Suite) SetupSuite() {
+	s.spans = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.spans)))
+}
+
 func (s *Suite) SetupSubTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.chatService = mocks.NewChatService(s.ctrl)
-	s.handler = handler.NewWebSocketHandler(&websocket.Upgrader{}, s.chatService)
+	s.authenticator = auth.NewJWTAuthenticator([]byte("secret"))
+	s.handler = handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, s.chatService, handler.Options{})
+	s.spans.Reset()
+
+	s.chatService.EXPECT().IsConnected(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	s.chatService.EXPECT().Connect(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s.chatService.EXPECT().Disconnect(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s.chatService.EXPECT().Unread(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 }
 
 func (s *Suite) TearDownSubTest() {
@@ -41,7 +60,24 @@ func (s *Suite) TestAuthentication() {
 		defer server.Close()
 
 		// When
-		cn1, res, err := websocket.DefaultDialer.Dial(wsUrl(server, ""), nil)
+		cn1, res, err := websocket.DefaultDialer.Dial(wsUrl(server), nil)
+
+		// Then
+		s.Error(err)
+		s.Equal(http.StatusUnauthorized, res.StatusCode)
+		s.Nil(cn1)
+	})
+
+	s.Run("reject invalid tokens", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		other := auth.NewJWTAuthenticator([]byte("other secret"))
+		token, _ := other.Sign("user_1", time.Minute)
+
+		// When
+		cn1, res, err := websocket.DefaultDialer.Dial(wsUrl(server), bearer(token))
 
 		// Then
 		s.Error(err)
@@ -54,18 +90,40 @@ func (s *Suite) TestAuthentication() {
 		server := httptest.NewServer(s.handler)
 		defer server.Close()
 
+		token, _ := s.authenticator.Sign("user_1", time.Minute)
+
 		// When
-		conn, res, err := websocket.DefaultDialer.Dial(wsUrl(server, "user_1"), nil)
+		conn, res, err := websocket.DefaultDialer.Dial(wsUrl(server), bearer(token))
 
 		// Then
 		s.NoError(err)
 		s.Equal(http.StatusSwitchingProtocols, res.StatusCode)
 		s.NotNil(conn)
 	})
+
+	s.Run("accept tokens passed as subprotocol", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		token, _ := s.authenticator.Sign("user_1", time.Minute)
+		dialer := websocket.Dialer{Subprotocols: []string{auth.BearerSubprotocol, token}}
+
+		// When
+		conn, res, err := dialer.Dial(wsUrl(server), nil)
+
+		// Then
+		s.NoError(err)
+		s.Equal(http.StatusSwitchingProtocols, res.StatusCode)
+		s.Equal(auth.BearerSubprotocol, conn.Subprotocol())
+	})
 }
 
 func (s *Suite) createConnection(server *httptest.Server, userName string) *websocket.Conn {
-	conn, res, err := websocket.DefaultDialer.Dial(wsUrl(server, userName), nil)
+	token, err := s.authenticator.Sign(userName, time.Minute)
+	s.Require().NoError(err)
+
+	conn, res, err := websocket.DefaultDialer.Dial(wsUrl(server), bearer(token))
 
 	s.Require().NoError(err)
 	s.Require().Equal(http.StatusSwitchingProtocols, res.StatusCode)
@@ -79,6 +137,10 @@ func (s *Suite) writeMessage(conn *websocket.Conn, message string) {
 	s.NoError(err)
 }
 
-func wsUrl(server *httptest.Server, username string) string {
-	return strings.ReplaceAll(server.URL, "http", "ws") + "?username=" + username
+func wsUrl(server *httptest.Server) string {
+	return strings.ReplaceAll(server.URL, "http", "ws")
+}
+
+func bearer(token string) http.Header {
+	return http.Header{"Authorization": {"Bearer " + token}}
 }
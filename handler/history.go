@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"practice-run/chat"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var HistoryCommandRegex = regexp.MustCompile(`^/(?P<command>history)\s+#(?P<roomName>\w+)(?:\s+(?P<limit>\d+))?$`)
+
+type HistoryCommandFactory struct{}
+
+func (f *HistoryCommandFactory) CreateCommand(match []string) (Command, error) {
+	command := &HistoryCommand{RoomName: match[2]}
+
+	if match[3] != "" {
+		limit, err := strconv.Atoi(match[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit: %w", err)
+		}
+
+		command.Limit = limit
+	}
+
+	return command, nil
+}
+
+// HistoryCommand lists the last messages of a room, the server's default
+// number of them when Limit is 0.
+type HistoryCommand struct {
+	RoomName string
+	Limit    int
+}
+
+func (c *HistoryCommand) Name() string {
+	return "history"
+}
+
+func (c *HistoryCommand) Room() string {
+	return c.RoomName
+}
+
+func (c *HistoryCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	messages, err := service.History(ctx, c.RoomName, m, c.Limit)
+	if err != nil {
+		return fmt.Errorf("failed to get history: %w", err)
+	}
+
+	if len(messages) == 0 {
+		m.WriteMessage(fmt.Sprintf("#%s history: no messages", c.RoomName))
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("#%s history: %d messages", c.RoomName, len(messages))}
+	for _, message := range messages {
+		lines = append(lines, messageLines(message)...)
+	}
+
+	m.WriteMessage(strings.Join(lines, "\n"))
+
+	return nil
+}
+
+// messageLines describes a message of the history of a room or of a thread,
+// with its attachments, reactions and number of replies.
+func messageLines(message *chat.Message) []string {
+	sender := "@" + message.Sender
+	if !message.EditedAt.IsZero() {
+		sender += " (edited)"
+	}
+
+	lines := []string{fmt.Sprintf("[%s] %s %s: %s",
+		message.ID, message.SentAt.UTC().Format("2006-01-02 15:04"), sender, message.Text)}
+
+	for _, a := range message.Attachments {
+		lines = append(lines, attachmentLine(a))
+	}
+
+	if len(message.Reactions) > 0 {
+		reactions := make([]string, 0, len(message.Reactions))
+		for _, reaction := range message.Reactions {
+			reactions = append(reactions, fmt.Sprintf("%s %d (@%s)", reaction.Emoji, len(reaction.Users), strings.Join(reaction.Users, ", @")))
+		}
+
+		lines = append(lines, "  reactions: "+strings.Join(reactions, ", "))
+	}
+
+	if message.Replies > 0 {
+		lines = append(lines, fmt.Sprintf("  %d replies", message.Replies))
+	}
+
+	return lines
+}
@@ -0,0 +1,82 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/chat"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestHistory() {
+	s.Run("ok", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		sentAt := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+		s.chatService.EXPECT().History(gomock.Any(), "room_1", gomock.Any(), 2).Return([]*chat.Message{
+			{
+				ID: "0a1b2c3d4e5f", Room: "room_1", Sender: "release_bot", Text: "v1.2.3 released", SentAt: sentAt,
+				Reactions: []chat.Reaction{
+					{Emoji: "👍", Users: []string{"user_1", "user_2"}},
+					{Emoji: "🎉", Users: []string{"user_3"}},
+				},
+			},
+			{
+				ID: "f5e4d3c2b1a0", Room: "room_1", Sender: "user_1", Text: "see the logs", SentAt: sentAt.Add(time.Minute), EditedAt: sentAt.Add(2 * time.Minute),
+				Attachments: []chat.Attachment{{ID: "a1b2c3", Name: "server.log", Size: 18, Type: "text/plain", URL: "/attachments/a1b2c3"}},
+			},
+		}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/history #room_1 2`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("#room_1 history: 2 messages\n"+
+			"[0a1b2c3d4e5f] 2026-10-19 09:30 @release_bot: v1.2.3 released\n"+
+			"  reactions: 👍 2 (@user_1, @user_2), 🎉 1 (@user_3)\n"+
+			"[f5e4d3c2b1a0] 2026-10-19 09:31 @user_1 (edited): see the logs\n"+
+			"  attachment a1b2c3: server.log (text/plain, 18 bytes) /attachments/a1b2c3", string(msg))
+	})
+
+	s.Run("no messages", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().History(gomock.Any(), "room_1", gomock.Any(), 0).Return(nil, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/history #room_1`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("#room_1 history: no messages", string(msg))
+	})
+
+	s.Run("not a room member", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().History(gomock.Any(), "room_1", gomock.Any(), 0).Return(nil, chat.ErrNotRoomMember)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/history #room_1`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("error: failed to get history: not a room member", string(msg))
+	})
+}
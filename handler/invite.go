@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"practice-run/chat"
+	"regexp"
+)
+
+var InviteCommandRegex = regexp.MustCompile(`^/(?P<command>invite)\s+#(?P<roomName>\w+)\s+@(?P<username>\w+)$`)
+
+type InviteCommand struct {
+	RoomName string
+	Username string
+}
+
+type InviteCommandFactory struct{}
+
+func (f *InviteCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &InviteCommand{RoomName: match[2], Username: match[3]}, nil
+}
+
+func (c *InviteCommand) Name() string {
+	return "invite_member"
+}
+
+func (c *InviteCommand) Room() string {
+	return c.RoomName
+}
+
+func (c *InviteCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	err := service.InviteMember(ctx, c.RoomName, m, c.Username)
+	if err != nil {
+		return fmt.Errorf("failed to invite @%s: %w", c.Username, err)
+	}
+
+	m.WriteMessage(fmt.Sprintf("you've invited @%s to #%s", c.Username, c.RoomName))
+
+	return nil
+}
+
+type InvitedHandler struct{}
+
+func (h *InvitedHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.InvitedEvent)
+	m.WriteMessage(fmt.Sprintf("#%s: @%s invited you", e.RoomName, e.InviterName))
+	return nil
+}
@@ -0,0 +1,46 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http/httptest"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestInvite() {
+	s.Run("ok", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().InviteMember(gomock.Any(), "room_1", gomock.Any(), "user_2").Return(nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/invite #room_1 @user_2`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal(`you've invited @user_2 to #room_1`, string(msg))
+	})
+
+	s.Run("error", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().InviteMember(gomock.Any(), "room_1", gomock.Any(), "user_2").Return(errors.New("some error"))
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/invite #room_1 @user_2`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal(`error: failed to invite @user_2: some error`, string(msg))
+	})
+}
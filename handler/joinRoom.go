@@ -23,6 +23,10 @@ func (c *JoinRoomCommand) Name() string {
 	return "join_room"
 }
 
+func (c *JoinRoomCommand) Room() string {
+	return c.RoomName
+}
+
 func (c *JoinRoomCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
 	err := service.AddMember(ctx, c.RoomName, m)
 	if err != nil {
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// KeepAlive pings the client every pingInterval and closes the connection
+// when nothing, pongs included, has been read from it for idleTimeout. A zero
+// duration disables the corresponding behaviour. Pings stop when the member
+// is closed.
+func (m *ChatMember) KeepAlive(pingInterval, idleTimeout time.Duration) {
+	m.idleTimeout = idleTimeout
+	m.extendReadDeadline()
+
+	if pingInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = m.Ping(nil)
+			case <-m.done:
+				return
+			}
+		}
+	}()
+}
+
+// Ping sends a ping to the client. onPong, if not nil, is called with the
+// round-trip time once the matching pong has been read.
+func (m *ChatMember) Ping(onPong func(rtt time.Duration)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return fmt.Errorf("connection closed")
+	}
+
+	m.pings++
+	payload := strconv.FormatUint(m.pings, 10)
+	m.pending[payload] = pendingPing{sentAt: time.Now(), onPong: onPong}
+
+	err := m.conn.WriteControl(websocket.PingMessage, []byte(payload), time.Now().Add(writeTimeout))
+	if err != nil {
+		delete(m.pending, payload)
+		return fmt.Errorf("failed to send ping: %w", err)
+	}
+
+	return nil
+}
+
+type pendingPing struct {
+	sentAt time.Time
+	onPong func(rtt time.Duration)
+}
+
+// handlePong is called from the goroutine reading messages.
+func (m *ChatMember) handlePong(payload string) error {
+	m.extendReadDeadline()
+
+	m.mu.Lock()
+	ping, ok := m.pending[payload]
+	delete(m.pending, payload)
+	m.mu.Unlock()
+
+	if ok && ping.onPong != nil {
+		ping.onPong(time.Since(ping.sentAt))
+	}
+
+	return nil
+}
+
+func (m *ChatMember) extendReadDeadline() {
+	if m.idleTimeout <= 0 {
+		return
+	}
+
+	_ = m.conn.SetReadDeadline(time.Now().Add(m.idleTimeout))
+}
+
+var PingCommandRegex = regexp.MustCompile(`^/(?P<command>ping)$`)
+
+type PingCommandFactory struct{}
+
+func (f *PingCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &PingCommand{}, nil
+}
+
+type PingCommand struct{}
+
+func (c *PingCommand) Name() string {
+	return "ping"
+}
+
+func (c *PingCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	// The pong is read by the goroutine executing this command: reply from
+	// the pong handler rather than waiting for it.
+	return m.Ping(func(rtt time.Duration) {
+		m.WriteMessage(fmt.Sprintf("pong: %s", rtt.Round(time.Microsecond)))
+	})
+}
@@ -0,0 +1,97 @@
+package handler_test
+
+import (
+	"errors"
+	"net"
+	"net/http/httptest"
+	"practice-run/handler"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func (s *Suite) TestKeepAlive() {
+	s.Run("ping clients", func() {
+		// Given
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, s.chatService, handler.Options{
+			PingInterval: 10 * time.Millisecond,
+		}))
+		defer server.Close()
+
+		conn := s.createConnection(server, "user_1")
+
+		pinged := make(chan struct{}, 1)
+		conn.SetPingHandler(func(string) error {
+			select {
+			case pinged <- struct{}{}:
+			default:
+			}
+			return nil
+		})
+
+		// When
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		go func() {
+			_, _, _ = conn.ReadMessage()
+		}()
+
+		// Then
+		select {
+		case <-pinged:
+		case <-time.After(time.Second):
+			s.Fail("no ping received")
+		}
+	})
+
+	s.Run("keep answering clients alive", func() {
+		// Given
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, s.chatService, handler.Options{
+			PingInterval: 10 * time.Millisecond,
+			IdleTimeout:  50 * time.Millisecond,
+		}))
+		defer server.Close()
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		_ = conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		_, _, err := conn.ReadMessage()
+
+		// Then
+		var netErr net.Error
+		s.True(errors.As(err, &netErr) && netErr.Timeout(), "expected the client read to time out, got: %v", err)
+	})
+
+	s.Run("disconnect idle clients", func() {
+		// Given
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, s.chatService, handler.Options{
+			IdleTimeout: 50 * time.Millisecond,
+		}))
+		defer server.Close()
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, _, err := conn.ReadMessage()
+
+		// Then
+		s.True(websocket.IsCloseError(err, websocket.CloseNormalClosure), "expected normal close, got: %v", err)
+	})
+
+	s.Run("ping command", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/ping`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Regexp(`^pong: \d+(\.\d+)?[µm]?s$`, string(msg))
+	})
+}
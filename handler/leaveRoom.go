@@ -23,6 +23,10 @@ func (c *LeaveRoomCommand) Name() string {
 	return "leave_room"
 }
 
+func (c *LeaveRoomCommand) Room() string {
+	return c.RoomName
+}
+
 func (c *LeaveRoomCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
 	err := service.RemoveMember(ctx, c.RoomName, m)
 	if err != nil {
@@ -0,0 +1,55 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/chat"
+	"practice-run/handler"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestLimits() {
+	s.Run("rate limit", func() {
+		// Given
+		s.chatService.EXPECT().SendMessage(gomock.Any(), "room_1", gomock.Any(), "hello").Return(&chat.Message{}, nil)
+
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, s.chatService, handler.Options{
+			RateLimit:      0.001,
+			RateLimitBurst: 1,
+		}))
+		defer server.Close()
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/msg #room_1 hello`)
+		s.writeMessage(conn, `/msg #room_1 hello`)
+
+		_, msg1, _ := conn.ReadMessage()
+		_, msg2, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal(`#room_1: @user_1: hello`, string(msg1))
+		s.Equal(`error: rate limit exceeded`, string(msg2))
+	})
+
+	s.Run("max message size", func() {
+		// Given
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, s.chatService, handler.Options{
+			MaxMessageSize: 16,
+		}))
+		defer server.Close()
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/msg #room_1 `+strings.Repeat("a", 16))
+
+		_, _, err := conn.ReadMessage()
+
+		// Then
+		s.True(websocket.IsCloseError(err, websocket.CloseMessageTooBig), "expected message too big close, got: %v", err)
+	})
+}
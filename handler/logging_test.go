@@ -0,0 +1,76 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"practice-run/chat"
+	"practice-run/handler"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestLogging() {
+	s.Run("log commands with connection, user, room and command attributes", func() {
+		// Given
+		output := &syncBuffer{}
+		logger := slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, s.chatService, handler.Options{Logger: logger}))
+		defer server.Close()
+
+		s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1", "user_1").Return(&chat.Room{}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/create #room_1`)
+		_, _, _ = conn.ReadMessage()
+
+		// Then
+		var record map[string]any
+		s.Require().Eventually(func() bool {
+			record = output.find("executed command")
+			return record != nil
+		}, time.Second, 10*time.Millisecond)
+
+		s.Equal("DEBUG", record["level"])
+		s.Equal("user_1", record["username"])
+		s.Equal("create_room", record["command"])
+		s.Equal("room_1", record["room"])
+		s.NotEmpty(record["conn_id"])
+		s.NotEmpty(record["remote_addr"])
+	})
+}
+
+// syncBuffer collects JSON log records written concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// find returns the first record with the message msg, or nil.
+func (b *syncBuffer) find(msg string) map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, line := range bytes.Split(b.buf.Bytes(), []byte("\n")) {
+		var record map[string]any
+		if json.Unmarshal(line, &record) == nil && record["msg"] == msg {
+			return record
+		}
+	}
+
+	return nil
+}
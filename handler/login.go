@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"practice-run/auth"
+	"time"
+)
+
+type LoginHandler struct {
+	accountService accountService
+	logger         *slog.Logger
+}
+
+func NewLoginHandler(accountService accountService, logger *slog.Logger) *LoginHandler {
+	return &LoginHandler{accountService: accountService, logger: logger}
+}
+
+type loginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (h *LoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req credentials
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request: failed to parse body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.accountService.Login(r.Context(), req.Username, req.Password)
+	switch {
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		h.logger.Error("failed to log in user", slog.String("username", req.Username), slog.Any("error", err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(h.logger, w, http.StatusOK, loginResponse{Token: session.Token, ExpiresAt: session.ExpiresAt})
+}
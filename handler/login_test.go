@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"practice-run/account"
+	"practice-run/auth"
+	"practice-run/handler"
+	"practice-run/handler/mocks"
+	"strings"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestLogin() {
+	s.Run("ok", func() {
+		// Given
+		accountService := mocks.NewAccountService(s.ctrl)
+		accountService.EXPECT().Login(gomock.Any(), "user_1", "password").Return(&account.Session{
+			Token:     "token",
+			Username:  "user_1",
+			ExpiresAt: time.Now().Add(time.Minute),
+		}, nil)
+
+		server := httptest.NewServer(handler.NewLoginHandler(accountService, slog.Default()))
+		defer server.Close()
+
+		// When
+		res, err := http.Post(server.URL, "application/json", strings.NewReader(`{"username":"user_1","password":"password"}`))
+
+		// Then
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, res.StatusCode)
+
+		var body struct {
+			Token string `json:"token"`
+		}
+		s.NoError(json.NewDecoder(res.Body).Decode(&body))
+		s.Equal("token", body.Token)
+	})
+
+	s.Run("invalid credentials", func() {
+		// Given
+		accountService := mocks.NewAccountService(s.ctrl)
+		accountService.EXPECT().Login(gomock.Any(), "user_1", "password").Return(nil, auth.ErrInvalidCredentials)
+
+		server := httptest.NewServer(handler.NewLoginHandler(accountService, slog.Default()))
+		defer server.Close()
+
+		// When
+		res, err := http.Post(server.URL, "application/json", strings.NewReader(`{"username":"user_1","password":"password"}`))
+
+		// Then
+		s.Require().NoError(err)
+		s.Equal(http.StatusUnauthorized, res.StatusCode)
+	})
+
+	s.Run("malformed body", func() {
+		// Given
+		accountService := mocks.NewAccountService(s.ctrl)
+
+		server := httptest.NewServer(handler.NewLoginHandler(accountService, slog.Default()))
+		defer server.Close()
+
+		// When
+		res, err := http.Post(server.URL, "application/json", strings.NewReader(`{`))
+
+		// Then
+		s.Require().NoError(err)
+		s.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"fmt"
+	"practice-run/chat"
+	"strings"
+)
+
+type MentionHandler struct{}
+
+// Handle writes mentions with a leading "!", for clients to highlight them.
+func (h *MentionHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.MentionEvent)
+
+	mention := "you"
+	if e.Mention == chat.MentionHere || e.Mention == chat.MentionRoom {
+		mention = "@" + e.Mention
+	}
+
+	if e.ParentID != "" {
+		mention += fmt.Sprintf(" in [%s]", e.ParentID)
+	}
+
+	lines := []string{fmt.Sprintf("! %s#%s: @%s mentioned %s: %s", messageID(e.MessageID), e.RoomName, e.SenderName, mention, e.Message)}
+	for _, a := range e.Attachments {
+		lines = append(lines, attachmentLine(a))
+	}
+
+	m.WriteMessage(strings.Join(lines, "\n"))
+	return nil
+}
@@ -0,0 +1,56 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"practice-run/chat"
+	"practice-run/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestMetrics() {
+	s.Run("count commands by name and result", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		gomock.InOrder(
+			s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1", "user_1").Return(&chat.Room{}, nil),
+			s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1", "user_1").Return(nil, errors.New("some error")),
+		)
+
+		ok := testutil.ToFloat64(metrics.Commands.WithLabelValues("create_room", metrics.ResultOK))
+		failed := testutil.ToFloat64(metrics.Commands.WithLabelValues("create_room", metrics.ResultError))
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/create #room_1`)
+		_, _, _ = conn.ReadMessage()
+		s.writeMessage(conn, `/create #room_1`)
+		_, _, _ = conn.ReadMessage()
+
+		// Then
+		s.Equal(ok+1, testutil.ToFloat64(metrics.Commands.WithLabelValues("create_room", metrics.ResultOK)))
+		s.Equal(failed+1, testutil.ToFloat64(metrics.Commands.WithLabelValues("create_room", metrics.ResultError)))
+	})
+
+	s.Run("count parse failures", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		failures := testutil.ToFloat64(metrics.ParseFailures)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `hello`)
+		_, _, _ = conn.ReadMessage()
+
+		// Then
+		s.Equal(failures+1, testutil.ToFloat64(metrics.ParseFailures))
+	})
+}
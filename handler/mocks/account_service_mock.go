@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: practice-run/handler (interfaces: accountService)
+//
+// Generated by this command:
+//
+//	mockgen -destination mocks/account_service_mock.go -mock_names accountService=AccountService -package mocks . accountService
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	account "practice-run/account"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// AccountService is a mock of accountService interface.
+type AccountService struct {
+	ctrl     *gomock.Controller
+	recorder *AccountServiceMockRecorder
+	isgomock struct{}
+}
+
+// AccountServiceMockRecorder is the mock recorder for AccountService.
+type AccountServiceMockRecorder struct {
+	mock *AccountService
+}
+
+// NewAccountService creates a new mock instance.
+func NewAccountService(ctrl *gomock.Controller) *AccountService {
+	mock := &AccountService{ctrl: ctrl}
+	mock.recorder = &AccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *AccountService) EXPECT() *AccountServiceMockRecorder {
+	return m.recorder
+}
+
+// Login mocks base method.
+func (m *AccountService) Login(ctx context.Context, username, password string) (*account.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, username, password)
+	ret0, _ := ret[0].(*account.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *AccountServiceMockRecorder) Login(ctx, username, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*AccountService)(nil).Login), ctx, username, password)
+}
+
+// Register mocks base method.
+func (m *AccountService) Register(ctx context.Context, username, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, username, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register.
+func (mr *AccountServiceMockRecorder) Register(ctx, username, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*AccountService)(nil).Register), ctx, username, password)
+}
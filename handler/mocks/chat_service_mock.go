@@ -41,6 +41,20 @@ func (m *ChatService) EXPECT() *ChatServiceMockRecorder {
 	return m.recorder
 }
 
+// AddACLRule mocks base method.
+func (m *ChatService) AddACLRule(ctx context.Context, roomName string, member chat.Member, rule chat.Rule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddACLRule", ctx, roomName, member, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddACLRule indicates an expected call of AddACLRule.
+func (mr *ChatServiceMockRecorder) AddACLRule(ctx, roomName, member, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddACLRule", reflect.TypeOf((*ChatService)(nil).AddACLRule), ctx, roomName, member, rule)
+}
+
 // AddMember mocks base method.
 func (m *ChatService) AddMember(ctx context.Context, roomName string, member chat.Member) error {
 	m.ctrl.T.Helper()
@@ -55,19 +69,208 @@ func (mr *ChatServiceMockRecorder) AddMember(ctx, roomName, member any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*ChatService)(nil).AddMember), ctx, roomName, member)
 }
 
+// Broadcast mocks base method.
+func (m *ChatService) Broadcast(ctx context.Context, event chat.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Broadcast", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Broadcast indicates an expected call of Broadcast.
+func (mr *ChatServiceMockRecorder) Broadcast(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Broadcast", reflect.TypeOf((*ChatService)(nil).Broadcast), ctx, event)
+}
+
+// Connect mocks base method.
+func (m *ChatService) Connect(ctx context.Context, member chat.Member) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Connect", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Connect indicates an expected call of Connect.
+func (mr *ChatServiceMockRecorder) Connect(ctx, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*ChatService)(nil).Connect), ctx, member)
+}
+
 // CreateRoom mocks base method.
-func (m *ChatService) CreateRoom(ctx context.Context, roomName string) (*chat.Room, error) {
+func (m *ChatService) CreateRoom(ctx context.Context, roomName, owner string) (*chat.Room, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateRoom", ctx, roomName)
+	ret := m.ctrl.Call(m, "CreateRoom", ctx, roomName, owner)
 	ret0, _ := ret[0].(*chat.Room)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateRoom indicates an expected call of CreateRoom.
-func (mr *ChatServiceMockRecorder) CreateRoom(ctx, roomName any) *gomock.Call {
+func (mr *ChatServiceMockRecorder) CreateRoom(ctx, roomName, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRoom", reflect.TypeOf((*ChatService)(nil).CreateRoom), ctx, roomName, owner)
+}
+
+// DeleteMessage mocks base method.
+func (m *ChatService) DeleteMessage(ctx context.Context, member chat.Member, id string) (*chat.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, member, id)
+	ret0, _ := ret[0].(*chat.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *ChatServiceMockRecorder) DeleteMessage(ctx, member, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*ChatService)(nil).DeleteMessage), ctx, member, id)
+}
+
+// Disconnect mocks base method.
+func (m *ChatService) Disconnect(ctx context.Context, member chat.Member) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disconnect", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disconnect indicates an expected call of Disconnect.
+func (mr *ChatServiceMockRecorder) Disconnect(ctx, member any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRoom", reflect.TypeOf((*ChatService)(nil).CreateRoom), ctx, roomName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disconnect", reflect.TypeOf((*ChatService)(nil).Disconnect), ctx, member)
+}
+
+// EditMessage mocks base method.
+func (m *ChatService) EditMessage(ctx context.Context, member chat.Member, id, text string) (*chat.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditMessage", ctx, member, id, text)
+	ret0, _ := ret[0].(*chat.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditMessage indicates an expected call of EditMessage.
+func (mr *ChatServiceMockRecorder) EditMessage(ctx, member, id, text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditMessage", reflect.TypeOf((*ChatService)(nil).EditMessage), ctx, member, id, text)
+}
+
+// Follow mocks base method.
+func (m *ChatService) Follow(ctx context.Context, member chat.Member, id string) (*chat.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, member, id)
+	ret0, _ := ret[0].(*chat.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *ChatServiceMockRecorder) Follow(ctx, member, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*ChatService)(nil).Follow), ctx, member, id)
+}
+
+// GetACL mocks base method.
+func (m *ChatService) GetACL(ctx context.Context, roomName string, member chat.Member) ([]chat.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetACL", ctx, roomName, member)
+	ret0, _ := ret[0].([]chat.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetACL indicates an expected call of GetACL.
+func (mr *ChatServiceMockRecorder) GetACL(ctx, roomName, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetACL", reflect.TypeOf((*ChatService)(nil).GetACL), ctx, roomName, member)
+}
+
+// History mocks base method.
+func (m *ChatService) History(ctx context.Context, roomName string, member chat.Member, limit int) ([]*chat.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, roomName, member, limit)
+	ret0, _ := ret[0].([]*chat.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *ChatServiceMockRecorder) History(ctx, roomName, member, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*ChatService)(nil).History), ctx, roomName, member, limit)
+}
+
+// InviteMember mocks base method.
+func (m *ChatService) InviteMember(ctx context.Context, roomName string, inviter chat.Member, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteMember", ctx, roomName, inviter, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InviteMember indicates an expected call of InviteMember.
+func (mr *ChatServiceMockRecorder) InviteMember(ctx, roomName, inviter, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteMember", reflect.TypeOf((*ChatService)(nil).InviteMember), ctx, roomName, inviter, username)
+}
+
+// IsConnected mocks base method.
+func (m *ChatService) IsConnected(ctx context.Context, username string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsConnected", ctx, username)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsConnected indicates an expected call of IsConnected.
+func (mr *ChatServiceMockRecorder) IsConnected(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsConnected", reflect.TypeOf((*ChatService)(nil).IsConnected), ctx, username)
+}
+
+// MarkRead mocks base method.
+func (m *ChatService) MarkRead(ctx context.Context, roomName string, member chat.Member, id string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, roomName, member, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *ChatServiceMockRecorder) MarkRead(ctx, roomName, member, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*ChatService)(nil).MarkRead), ctx, roomName, member, id)
+}
+
+// React mocks base method.
+func (m *ChatService) React(ctx context.Context, member chat.Member, id, emoji string) (*chat.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "React", ctx, member, id, emoji)
+	ret0, _ := ret[0].(*chat.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// React indicates an expected call of React.
+func (mr *ChatServiceMockRecorder) React(ctx, member, id, emoji any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "React", reflect.TypeOf((*ChatService)(nil).React), ctx, member, id, emoji)
+}
+
+// RemoveACLRule mocks base method.
+func (m *ChatService) RemoveACLRule(ctx context.Context, roomName string, member chat.Member, rule chat.Rule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveACLRule", ctx, roomName, member, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveACLRule indicates an expected call of RemoveACLRule.
+func (mr *ChatServiceMockRecorder) RemoveACLRule(ctx, roomName, member, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveACLRule", reflect.TypeOf((*ChatService)(nil).RemoveACLRule), ctx, roomName, member, rule)
 }
 
 // RemoveMember mocks base method.
@@ -84,16 +287,146 @@ func (mr *ChatServiceMockRecorder) RemoveMember(ctx, roomName, member any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*ChatService)(nil).RemoveMember), ctx, roomName, member)
 }
 
+// Reply mocks base method.
+func (m *ChatService) Reply(ctx context.Context, member chat.Member, parentID, message string, attachments ...string) (*chat.Message, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, member, parentID, message}
+	for _, a := range attachments {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Reply", varargs...)
+	ret0, _ := ret[0].(*chat.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reply indicates an expected call of Reply.
+func (mr *ChatServiceMockRecorder) Reply(ctx, member, parentID, message any, attachments ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, member, parentID, message}, attachments...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reply", reflect.TypeOf((*ChatService)(nil).Reply), varargs...)
+}
+
+// Search mocks base method.
+func (m *ChatService) Search(ctx context.Context, member chat.Member, query string) ([]chat.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, member, query)
+	ret0, _ := ret[0].([]chat.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *ChatServiceMockRecorder) Search(ctx, member, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*ChatService)(nil).Search), ctx, member, query)
+}
+
 // SendMessage mocks base method.
-func (m *ChatService) SendMessage(ctx context.Context, roomName string, member chat.Member, message string) error {
+func (m *ChatService) SendMessage(ctx context.Context, roomName string, member chat.Member, message string, attachments ...string) (*chat.Message, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, roomName, member, message}
+	for _, a := range attachments {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SendMessage", varargs...)
+	ret0, _ := ret[0].(*chat.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *ChatServiceMockRecorder) SendMessage(ctx, roomName, member, message any, attachments ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, roomName, member, message}, attachments...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*ChatService)(nil).SendMessage), varargs...)
+}
+
+// Takeover mocks base method.
+func (m *ChatService) Takeover(ctx context.Context, member chat.Member) (chat.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Takeover", ctx, member)
+	ret0, _ := ret[0].(chat.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Takeover indicates an expected call of Takeover.
+func (mr *ChatServiceMockRecorder) Takeover(ctx, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Takeover", reflect.TypeOf((*ChatService)(nil).Takeover), ctx, member)
+}
+
+// Thread mocks base method.
+func (m *ChatService) Thread(ctx context.Context, member chat.Member, id string) ([]*chat.Message, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendMessage", ctx, roomName, member, message)
+	ret := m.ctrl.Call(m, "Thread", ctx, member, id)
+	ret0, _ := ret[0].([]*chat.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Thread indicates an expected call of Thread.
+func (mr *ChatServiceMockRecorder) Thread(ctx, member, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Thread", reflect.TypeOf((*ChatService)(nil).Thread), ctx, member, id)
+}
+
+// Typing mocks base method.
+func (m *ChatService) Typing(ctx context.Context, roomName string, member chat.Member) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Typing", ctx, roomName, member)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SendMessage indicates an expected call of SendMessage.
-func (mr *ChatServiceMockRecorder) SendMessage(ctx, roomName, member, message any) *gomock.Call {
+// Typing indicates an expected call of Typing.
+func (mr *ChatServiceMockRecorder) Typing(ctx, roomName, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Typing", reflect.TypeOf((*ChatService)(nil).Typing), ctx, roomName, member)
+}
+
+// Unfollow mocks base method.
+func (m *ChatService) Unfollow(ctx context.Context, member chat.Member, id string) (*chat.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", ctx, member, id)
+	ret0, _ := ret[0].(*chat.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *ChatServiceMockRecorder) Unfollow(ctx, member, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*ChatService)(nil).Unfollow), ctx, member, id)
+}
+
+// Unreact mocks base method.
+func (m *ChatService) Unreact(ctx context.Context, member chat.Member, id, emoji string) (*chat.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unreact", ctx, member, id, emoji)
+	ret0, _ := ret[0].(*chat.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unreact indicates an expected call of Unreact.
+func (mr *ChatServiceMockRecorder) Unreact(ctx, member, id, emoji any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unreact", reflect.TypeOf((*ChatService)(nil).Unreact), ctx, member, id, emoji)
+}
+
+// Unread mocks base method.
+func (m *ChatService) Unread(ctx context.Context, username string) ([]chat.UnreadCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unread", ctx, username)
+	ret0, _ := ret[0].([]chat.UnreadCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unread indicates an expected call of Unread.
+func (mr *ChatServiceMockRecorder) Unread(ctx, username any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*ChatService)(nil).SendMessage), ctx, roomName, member, message)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unread", reflect.TypeOf((*ChatService)(nil).Unread), ctx, username)
 }
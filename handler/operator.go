@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"fmt"
+	"practice-run/chat"
+
+	"github.com/gorilla/websocket"
+)
+
+// Renderers of the events sent to members acted upon by an operator.
+
+type DisconnectedHandler struct{}
+
+func (h *DisconnectedHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.DisconnectedEvent)
+
+	message := "disconnected by an administrator"
+	if e.Reason != "" {
+		message += ": " + e.Reason
+	}
+
+	m.WriteMessage(message)
+	return m.Close(websocket.ClosePolicyViolation, "disconnected by an administrator")
+}
+
+type RemovedHandler struct{}
+
+func (h *RemovedHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.RemovedEvent)
+	m.WriteMessage(fmt.Sprintf("#%s: you were removed by an administrator", e.RoomName))
+	return nil
+}
+
+type RoomDeletedHandler struct{}
+
+func (h *RoomDeletedHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.RoomDeletedEvent)
+	m.WriteMessage(fmt.Sprintf("#%s deleted", e.RoomName))
+	return nil
+}
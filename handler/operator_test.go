@@ -0,0 +1,47 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"practice-run/chat"
+	"practice-run/handler"
+
+	"github.com/gorilla/websocket"
+)
+
+func (s *Suite) TestOperatorEvents() {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		s.Require().NoError(err)
+
+		member := handler.NewChatMember("user_1", conn)
+
+		member.Notify(&chat.SystemAnnouncementEvent{Message: "maintenance at noon"})
+		member.Notify(&chat.RemovedEvent{RoomName: "room_1"})
+		member.Notify(&chat.RoomDeletedEvent{RoomName: "room_2"})
+		member.Notify(&chat.DisconnectedEvent{Reason: "spam"})
+	}))
+	defer server.Close()
+
+	// When
+	cn, _, err := websocket.DefaultDialer.Dial(wsUrl(server), nil)
+	s.Require().NoError(err)
+	defer cn.Close()
+
+	// Then
+	_, raw, _ := cn.ReadMessage()
+	s.Equal("announcement: maintenance at noon", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("#room_1: you were removed by an administrator", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("#room_2 deleted", string(raw))
+
+	_, raw, _ = cn.ReadMessage()
+	s.Equal("disconnected by an administrator: spam", string(raw))
+
+	_, _, err = cn.ReadMessage()
+	s.True(websocket.IsCloseError(err, websocket.ClosePolicyViolation), "expected policy violation close, got: %v", err)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// OriginChecker returns a websocket.Upgrader CheckOrigin function accepting
+// requests from the allowed origins, "*" allowing any. Requests without an
+// Origin header come from non-browser clients and are always accepted. With
+// no allowed origins, only same-origin requests are accepted.
+func OriginChecker(allowed []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		if len(allowed) == 0 {
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		}
+
+		return slices.ContainsFunc(allowed, func(a string) bool {
+			return a == "*" || strings.EqualFold(strings.TrimSuffix(a, "/"), origin)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/handler"
+)
+
+func (s *Suite) TestOriginChecker() {
+	s.Run("same origin by default", func() {
+		// Given
+		check := handler.OriginChecker(nil)
+		same := httptest.NewRequest("GET", "http://chat.example.com/ws", nil)
+		same.Header.Set("Origin", "https://chat.example.com")
+		other := httptest.NewRequest("GET", "http://chat.example.com/ws", nil)
+		other.Header.Set("Origin", "https://evil.example.com")
+
+		// Then
+		s.True(check(same))
+		s.False(check(other))
+	})
+
+	s.Run("allowlist", func() {
+		// Given
+		check := handler.OriginChecker([]string{"https://app.example.com"})
+		allowed := httptest.NewRequest("GET", "http://chat.example.com/ws", nil)
+		allowed.Header.Set("Origin", "https://app.example.com")
+		denied := httptest.NewRequest("GET", "http://chat.example.com/ws", nil)
+		denied.Header.Set("Origin", "https://chat.example.com")
+
+		// Then
+		s.True(check(allowed))
+		s.False(check(denied))
+	})
+
+	s.Run("any origin", func() {
+		// Given
+		check := handler.OriginChecker([]string{"*"})
+		r := httptest.NewRequest("GET", "http://chat.example.com/ws", nil)
+		r.Header.Set("Origin", "https://evil.example.com")
+
+		// Then
+		s.True(check(r))
+	})
+
+	s.Run("non-browser clients", func() {
+		// Given
+		check := handler.OriginChecker([]string{"https://app.example.com"})
+		r := httptest.NewRequest("GET", "http://chat.example.com/ws", nil)
+
+		// Then
+		s.True(check(r))
+	})
+}
@@ -11,6 +11,21 @@ var regexCommands = map[*regexp.Regexp]CommandFactory{
 	JoinRoomCommandRegex:    &JoinCommandFactory{},
 	LeaveRoomCommandRegex:   &LeaveCommandFactory{},
 	SendMessageCommandRegex: &SendMessageCommandFactory{},
+	InviteCommandRegex:      &InviteCommandFactory{},
+	ACLCommandRegex:         &ACLCommandFactory{},
+	PingCommandRegex:        &PingCommandFactory{},
+	AnnounceCommandRegex:    &AnnounceCommandFactory{},
+	SearchCommandRegex:      &SearchCommandFactory{},
+	EditCommandRegex:        &EditCommandFactory{},
+	DeleteCommandRegex:      &DeleteCommandFactory{},
+	ReactCommandRegex:       &ReactCommandFactory{},
+	HistoryCommandRegex:     &HistoryCommandFactory{},
+	ReplyCommandRegex:       &ReplyCommandFactory{},
+	ThreadCommandRegex:      &ThreadCommandFactory{},
+	FollowCommandRegex:      &FollowCommandFactory{},
+	TypingCommandRegex:      &TypingCommandFactory{},
+	ReadCommandRegex:        &ReadCommandFactory{},
+	UnreadCommandRegex:      &UnreadCommandFactory{},
 }
 
 type CommandFactory interface {
@@ -22,6 +37,12 @@ type Command interface {
 	Execute(ctx context.Context, m *ChatMember, service chatService) error
 }
 
+// RoomCommand is implemented by commands acting on a room.
+type RoomCommand interface {
+	Command
+	Room() string
+}
+
 func ParseMessage(msg string) (Command, error) {
 	for regex, factory := range regexCommands {
 		if match := regex.FindStringSubmatch(msg); len(match) > 0 {
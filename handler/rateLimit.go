@@ -0,0 +1,40 @@
+package handler
+
+import "time"
+
+// rateLimiter is a token bucket refilled at rate tokens per second, holding up
+// to burst tokens. A zero rate disables it. Each connection has its own,
+// used from the goroutine reading its messages.
+type rateLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (l *rateLimiter) Allow() bool {
+	if l.rate <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+
+	l.tokens--
+
+	return true
+}
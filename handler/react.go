@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"practice-run/chat"
+	"regexp"
+	"slices"
+)
+
+var ReactCommandRegex = regexp.MustCompile(`^/(?P<command>react|unreact)\s+(?P<id>[0-9a-f]+)\s+(?P<emoji>\S+)$`)
+
+type ReactCommandFactory struct{}
+
+func (f *ReactCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &ReactCommand{Remove: match[1] == "unreact", MessageID: match[2], Emoji: match[3]}, nil
+}
+
+type ReactCommand struct {
+	Remove    bool
+	MessageID string
+	Emoji     string
+}
+
+func (c *ReactCommand) Name() string {
+	if c.Remove {
+		return "unreact"
+	}
+
+	return "react"
+}
+
+func (c *ReactCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	if c.Remove {
+		message, err := service.Unreact(ctx, m, c.MessageID, c.Emoji)
+		if err != nil {
+			return fmt.Errorf("failed to remove reaction: %w", err)
+		}
+
+		m.WriteMessage(reactionRemovedLine(message.ID, message.Room, m.Username(), c.Emoji, reactionCount(message, c.Emoji)))
+
+		return nil
+	}
+
+	message, err := service.React(ctx, m, c.MessageID, c.Emoji)
+	if err != nil {
+		return fmt.Errorf("failed to react: %w", err)
+	}
+
+	m.WriteMessage(reactionAddedLine(message.ID, message.Room, m.Username(), c.Emoji, reactionCount(message, c.Emoji)))
+
+	return nil
+}
+
+func reactionCount(message *chat.Message, emoji string) int {
+	i := slices.IndexFunc(message.Reactions, func(r chat.Reaction) bool { return r.Emoji == emoji })
+	if i < 0 {
+		return 0
+	}
+
+	return len(message.Reactions[i].Users)
+}
+
+func reactionAddedLine(id, roomName, username, emoji string, count int) string {
+	return fmt.Sprintf("%s#%s: @%s reacted %s (%d)", messageID(id), roomName, username, emoji, count)
+}
+
+func reactionRemovedLine(id, roomName, username, emoji string, count int) string {
+	return fmt.Sprintf("%s#%s: @%s removed reaction %s (%d)", messageID(id), roomName, username, emoji, count)
+}
+
+type ReactionAddedHandler struct{}
+
+func (h *ReactionAddedHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.ReactionAddedEvent)
+	m.WriteMessage(reactionAddedLine(e.MessageID, e.RoomName, e.Username, e.Emoji, e.Count))
+	return nil
+}
+
+type ReactionRemovedHandler struct{}
+
+func (h *ReactionRemovedHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.ReactionRemovedEvent)
+	m.WriteMessage(reactionRemovedLine(e.MessageID, e.RoomName, e.Username, e.Emoji, e.Count))
+	return nil
+}
@@ -0,0 +1,69 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/chat"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestReact() {
+	s.Run("react", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().React(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f", "👍").Return(&chat.Message{
+			ID:        "0a1b2c3d4e5f",
+			Room:      "room_1",
+			Reactions: []chat.Reaction{{Emoji: "👍", Users: []string{"user_2", "user_1"}}},
+		}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/react 0a1b2c3d4e5f 👍`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("[0a1b2c3d4e5f] #room_1: @user_1 reacted 👍 (2)", string(msg))
+	})
+
+	s.Run("unreact", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().Unreact(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f", "👍").
+			Return(&chat.Message{ID: "0a1b2c3d4e5f", Room: "room_1"}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/unreact 0a1b2c3d4e5f 👍`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("[0a1b2c3d4e5f] #room_1: @user_1 removed reaction 👍 (0)", string(msg))
+	})
+
+	s.Run("already reacted", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().React(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f", "👍").Return(nil, chat.ErrAlreadyReacted)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/react 0a1b2c3d4e5f 👍`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("error: failed to react: already reacted", string(msg))
+	})
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"practice-run/chat"
+	"regexp"
+	"strings"
+)
+
+var (
+	ReadCommandRegex   = regexp.MustCompile(`^/(?P<command>read)\s+#(?P<roomName>\w+)\s+(?P<id>[0-9a-f]+)$`)
+	UnreadCommandRegex = regexp.MustCompile(`^/(?P<command>unread)$`)
+)
+
+type ReadCommandFactory struct{}
+
+func (f *ReadCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &ReadCommand{RoomName: match[2], MessageID: match[3]}, nil
+}
+
+// ReadCommand marks the messages of a room up to MessageID as read.
+type ReadCommand struct {
+	RoomName  string
+	MessageID string
+}
+
+func (c *ReadCommand) Name() string {
+	return "read"
+}
+
+func (c *ReadCommand) Room() string {
+	return c.RoomName
+}
+
+func (c *ReadCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	unread, err := service.MarkRead(ctx, c.RoomName, m, c.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to mark as read: %w", err)
+	}
+
+	m.WriteMessage(fmt.Sprintf("#%s: %d unread", c.RoomName, unread))
+
+	return nil
+}
+
+type UnreadCommandFactory struct{}
+
+func (f *UnreadCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &UnreadCommand{}, nil
+}
+
+// UnreadCommand lists the rooms with unread messages.
+type UnreadCommand struct{}
+
+func (c *UnreadCommand) Name() string {
+	return "unread"
+}
+
+func (c *UnreadCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	counts, err := service.Unread(ctx, m.Username())
+	if err != nil {
+		return fmt.Errorf("failed to count unread messages: %w", err)
+	}
+
+	if len(counts) == 0 {
+		m.WriteMessage("unread: no messages")
+		return nil
+	}
+
+	m.WriteMessage(unreadMessage(counts))
+
+	return nil
+}
+
+func unreadMessage(counts []chat.UnreadCount) string {
+	lines := make([]string, 0, len(counts)+1)
+	lines = append(lines, fmt.Sprintf("unread: %d rooms", len(counts)))
+	for _, count := range counts {
+		lines = append(lines, fmt.Sprintf("#%s: %d unread", count.Room, count.Count))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type ReadReceiptHandler struct{}
+
+func (h *ReadReceiptHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.ReadReceiptEvent)
+	m.WriteMessage(fmt.Sprintf("%s#%s: @%s read your message", messageID(e.MessageID), e.RoomName, e.Username))
+	return nil
+}
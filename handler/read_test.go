@@ -0,0 +1,95 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/chat"
+	"practice-run/handler"
+	"practice-run/handler/mocks"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestRead() {
+	s.Run("ok", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().MarkRead(gomock.Any(), "room_1", gomock.Any(), "0a1b2c3d4e5f").Return(2, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/read #room_1 0a1b2c3d4e5f`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("#room_1: 2 unread", string(msg))
+	})
+
+	s.Run("message not found", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().MarkRead(gomock.Any(), "room_1", gomock.Any(), "0a1b2c3d4e5f").Return(0, chat.ErrMessageNotFound)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/read #room_1 0a1b2c3d4e5f`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("error: failed to mark as read: message not found", string(msg))
+	})
+}
+
+func (s *Suite) TestUnread() {
+	s.Run("no unread messages", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/unread`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("unread: no messages", string(msg))
+	})
+
+	s.Run("list rooms with unread messages", func() {
+		// Given
+		chatService := mocks.NewChatService(s.ctrl)
+		chatService.EXPECT().IsConnected(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+		chatService.EXPECT().Connect(gomock.Any(), gomock.Any()).Return(nil)
+		chatService.EXPECT().Disconnect(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		gomock.InOrder(
+			chatService.EXPECT().Unread(gomock.Any(), "user_1").Return(nil, nil),
+			chatService.EXPECT().Unread(gomock.Any(), "user_1").Return([]chat.UnreadCount{
+				{Room: "room_1", Count: 3},
+				{Room: "room_2", Count: 1},
+			}, nil),
+		)
+
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, chatService, handler.Options{}))
+		defer server.Close()
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/unread`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("unread: 2 rooms\n#room_1: 3 unread\n#room_2: 1 unread", string(msg))
+	})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"practice-run/account"
+)
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type RegisterHandler struct {
+	accountService accountService
+	logger         *slog.Logger
+}
+
+func NewRegisterHandler(accountService accountService, logger *slog.Logger) *RegisterHandler {
+	return &RegisterHandler{accountService: accountService, logger: logger}
+}
+
+func (h *RegisterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req credentials
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request: failed to parse body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.accountService.Register(r.Context(), req.Username, req.Password)
+	switch {
+	case errors.Is(err, account.ErrInvalidUsername), errors.Is(err, account.ErrInvalidPassword):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, account.ErrUserAlreadyExists), errors.Is(err, account.ErrUsernameReserved):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		h.logger.Error("failed to register user", slog.String("username", req.Username), slog.Any("error", err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Debug("registered user", slog.String("username", req.Username))
+
+	writeJSON(h.logger, w, http.StatusCreated, map[string]string{"username": req.Username})
+}
+
+func writeJSON(logger *slog.Logger, w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Error("failed to write response", slog.Any("error", err))
+	}
+}
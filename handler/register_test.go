@@ -0,0 +1,80 @@
+package handler_test
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"practice-run/account"
+	"practice-run/handler"
+	"practice-run/handler/mocks"
+	"strings"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestRegister() {
+	s.Run("ok", func() {
+		// Given
+		accountService := mocks.NewAccountService(s.ctrl)
+		accountService.EXPECT().Register(gomock.Any(), "user_1", "password").Return(nil)
+
+		server := httptest.NewServer(handler.NewRegisterHandler(accountService, slog.Default()))
+		defer server.Close()
+
+		// When
+		res, err := http.Post(server.URL, "application/json", strings.NewReader(`{"username":"user_1","password":"password"}`))
+
+		// Then
+		s.Require().NoError(err)
+		s.Equal(http.StatusCreated, res.StatusCode)
+	})
+
+	s.Run("username taken", func() {
+		// Given
+		accountService := mocks.NewAccountService(s.ctrl)
+		accountService.EXPECT().Register(gomock.Any(), "user_1", "password").Return(account.ErrUserAlreadyExists)
+
+		server := httptest.NewServer(handler.NewRegisterHandler(accountService, slog.Default()))
+		defer server.Close()
+
+		// When
+		res, err := http.Post(server.URL, "application/json", strings.NewReader(`{"username":"user_1","password":"password"}`))
+
+		// Then
+		s.Require().NoError(err)
+		s.Equal(http.StatusConflict, res.StatusCode)
+	})
+
+	s.Run("invalid username", func() {
+		// Given
+		accountService := mocks.NewAccountService(s.ctrl)
+		accountService.EXPECT().Register(gomock.Any(), "user 1", "password").Return(account.ErrInvalidUsername)
+
+		server := httptest.NewServer(handler.NewRegisterHandler(accountService, slog.Default()))
+		defer server.Close()
+
+		// When
+		res, err := http.Post(server.URL, "application/json", strings.NewReader(`{"username":"user 1","password":"password"}`))
+
+		// Then
+		s.Require().NoError(err)
+		s.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+
+	s.Run("error", func() {
+		// Given
+		accountService := mocks.NewAccountService(s.ctrl)
+		accountService.EXPECT().Register(gomock.Any(), "user_1", "password").Return(errors.New("some error"))
+
+		server := httptest.NewServer(handler.NewRegisterHandler(accountService, slog.Default()))
+		defer server.Close()
+
+		// When
+		res, err := http.Post(server.URL, "application/json", strings.NewReader(`{"username":"user_1","password":"password"}`))
+
+		// Then
+		s.Require().NoError(err)
+		s.Equal(http.StatusInternalServerError, res.StatusCode)
+	})
+}
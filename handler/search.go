@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var SearchCommandRegex = regexp.MustCompile(`^/(?P<command>search)\s+(?P<query>.+)$`)
+
+type SearchCommandFactory struct{}
+
+func (f *SearchCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &SearchCommand{Query: match[2]}, nil
+}
+
+type SearchCommand struct {
+	Query string
+}
+
+func (c *SearchCommand) Name() string {
+	return "search"
+}
+
+func (c *SearchCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	results, err := service.Search(ctx, m, c.Query)
+	if err != nil {
+		return fmt.Errorf("failed to search: %w", err)
+	}
+
+	if len(results) == 0 {
+		m.WriteMessage("search: no results")
+		return nil
+	}
+
+	lines := make([]string, 0, len(results)+1)
+	lines = append(lines, fmt.Sprintf("search: %d results", len(results)))
+	for _, result := range results {
+		message := result.Message
+		lines = append(lines, fmt.Sprintf("[%s] %s #%s @%s: %s",
+			message.ID, message.SentAt.UTC().Format("2006-01-02 15:04"), message.Room, message.Sender, message.Text))
+	}
+
+	m.WriteMessage(strings.Join(lines, "\n"))
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/chat"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestSearch() {
+	s.Run("results", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		sentAt := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+		s.chatService.EXPECT().Search(gomock.Any(), gomock.Any(), `"release notes" from:@user_2`).Return([]chat.SearchResult{
+			{Message: &chat.Message{ID: "a1b2c3", Room: "room_1", Sender: "user_2", Text: "release notes are out", SentAt: sentAt}, Score: 2},
+			{Message: &chat.Message{ID: "d4e5f6", Room: "room_2", Sender: "user_2", Text: "see the release notes", SentAt: sentAt.Add(-time.Hour)}, Score: 1},
+		}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/search "release notes" from:@user_2`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("search: 2 results\n"+
+			"[a1b2c3] 2026-10-19 09:30 #room_1 @user_2: release notes are out\n"+
+			"[d4e5f6] 2026-10-19 08:30 #room_2 @user_2: see the release notes", string(msg))
+	})
+
+	s.Run("no results", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().Search(gomock.Any(), gomock.Any(), "deploy").Return(nil, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/search deploy`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("search: no results", string(msg))
+	})
+
+	s.Run("invalid query", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().Search(gomock.Any(), gomock.Any(), "after:yesterday").Return(nil, chat.ErrInvalidQuery)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/search after:yesterday`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("error: failed to search: invalid search query", string(msg))
+	})
+}
@@ -5,32 +5,69 @@ import (
 	"fmt"
 	"practice-run/chat"
 	"regexp"
+	"strings"
 )
 
 var SendMessageCommandRegex = regexp.MustCompile(`^/(?P<command>msg)\s+#(?P<roomName>\w+)\s+(?P<message>.+)$`)
 
+// attachmentPrefix prefixes the words of a message referring to an attachment
+// by ID, which are removed from its text.
+const attachmentPrefix = "attach:"
+
 type SendMessageCommand struct {
-	RoomName string
-	Message  string
+	RoomName    string
+	Message     string
+	Attachments []string
 }
 
 type SendMessageCommandFactory struct{}
 
 func (f *SendMessageCommandFactory) CreateCommand(match []string) (Command, error) {
-	return &SendMessageCommand{RoomName: match[2], Message: match[3]}, nil
+	message, attachments := parseAttachments(match[3])
+
+	return &SendMessageCommand{RoomName: match[2], Message: message, Attachments: attachments}, nil
+}
+
+// parseAttachments removes the words referring to attachments from message,
+// and returns it along with their IDs.
+func parseAttachments(message string) (string, []string) {
+	var words, attachments []string
+	for _, word := range strings.Fields(message) {
+		if id, ok := strings.CutPrefix(word, attachmentPrefix); ok && id != "" {
+			attachments = append(attachments, id)
+			continue
+		}
+
+		words = append(words, word)
+	}
+
+	if len(attachments) == 0 {
+		return message, nil
+	}
+
+	return strings.Join(words, " "), attachments
 }
 
 func (c *SendMessageCommand) Name() string {
 	return "send_message"
 }
 
+func (c *SendMessageCommand) Room() string {
+	return c.RoomName
+}
+
 func (c *SendMessageCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
-	err := service.SendMessage(ctx, c.RoomName, m, c.Message)
+	sent, err := service.SendMessage(ctx, c.RoomName, m, c.Message, c.Attachments...)
 	if err != nil {
 		return fmt.Errorf("failed to send message: %w", err)
 	}
 
-	m.WriteMessage(fmt.Sprintf("#%s: @%s: %s", c.RoomName, m.Username(), c.Message))
+	lines := []string{fmt.Sprintf("%s#%s: @%s: %s", messageID(sent.ID), c.RoomName, m.Username(), c.Message)}
+	for _, id := range c.Attachments {
+		lines = append(lines, fmt.Sprintf("  attachment %s", id))
+	}
+
+	m.WriteMessage(strings.Join(lines, "\n"))
 
 	return nil
 }
@@ -39,6 +76,36 @@ type MessageReceivedHandler struct{}
 
 func (h *MessageReceivedHandler) Handle(event chat.Event, m *ChatMember) error {
 	e := event.(*chat.MessageReceivedEvent)
-	m.WriteMessage(fmt.Sprintf("#%s: @%s: %s", e.RoomName, e.SenderName, e.Message))
+
+	sender := "@" + e.SenderName
+	if e.ParentID != "" {
+		sender += fmt.Sprintf(" replied to [%s] (%d replies)", e.ParentID, e.Replies)
+	}
+
+	lines := []string{fmt.Sprintf("%s#%s: %s: %s", messageID(e.MessageID), e.RoomName, sender, e.Message)}
+	for _, a := range e.Attachments {
+		lines = append(lines, attachmentLine(a))
+	}
+
+	m.WriteMessage(strings.Join(lines, "\n"))
 	return nil
 }
+
+func attachmentLine(a chat.Attachment) string {
+	line := fmt.Sprintf("  attachment %s: %s (%s, %d bytes) %s", a.ID, a.Name, a.Type, a.Size, a.URL)
+	if a.Thumbnail != nil {
+		line += fmt.Sprintf(", %dx%d, thumbnail %dx%d %s", a.Width, a.Height, a.Thumbnail.Width, a.Thumbnail.Height, a.Thumbnail.URL)
+	}
+
+	return line
+}
+
+// messageID prefixes the messages written to members with the ID of the chat
+// message they're about, which commands refer to it by.
+func messageID(id string) string {
+	if id == "" {
+		return ""
+	}
+
+	return "[" + id + "] "
+}
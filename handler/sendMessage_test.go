@@ -3,6 +3,7 @@ package handler_test
 import (
 	"errors"
 	"net/http/httptest"
+	"practice-run/chat"
 
 	"go.uber.org/mock/gomock"
 )
@@ -14,7 +15,7 @@ func (s *Suite) TestSendMessage() {
 		defer server.Close()
 
 		s.chatService.EXPECT().AddMember(gomock.Any(), "room_1", gomock.Any()).Return(nil)
-		s.chatService.EXPECT().SendMessage(gomock.Any(), "room_1", gomock.Any(), "hello, world!").Return(nil)
+		s.chatService.EXPECT().SendMessage(gomock.Any(), "room_1", gomock.Any(), "hello, world!").Return(&chat.Message{ID: "0a1b2c3d4e5f"}, nil)
 
 		conn := s.createConnection(server, "user_1")
 
@@ -27,7 +28,7 @@ func (s *Suite) TestSendMessage() {
 
 		// Then
 		s.Equal(`you've joined #room_1`, string(msg1))
-		s.Equal(`#room_1: @user_1: hello, world!`, string(msg2))
+		s.Equal(`[0a1b2c3d4e5f] #room_1: @user_1: hello, world!`, string(msg2))
 	})
 
 	s.Run("error", func() {
@@ -36,7 +37,7 @@ func (s *Suite) TestSendMessage() {
 		defer server.Close()
 
 		s.chatService.EXPECT().AddMember(gomock.Any(), "room_1", gomock.Any()).Return(nil)
-		s.chatService.EXPECT().SendMessage(gomock.Any(), "room_1", gomock.Any(), "hello, world!").Return(errors.New("some error"))
+		s.chatService.EXPECT().SendMessage(gomock.Any(), "room_1", gomock.Any(), "hello, world!").Return(nil, errors.New("some error"))
 
 		conn := s.createConnection(server, "user_1")
 
@@ -51,4 +52,22 @@ func (s *Suite) TestSendMessage() {
 		s.Equal(`you've joined #room_1`, string(msg1))
 		s.Equal(`error: failed to send message: some error`, string(msg2))
 	})
+
+	s.Run("with attachments", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().SendMessage(gomock.Any(), "room_1", gomock.Any(), "see the logs", "a1b2c3", "d4e5f6").Return(&chat.Message{ID: "0a1b2c3d4e5f"}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/msg #room_1 see attach:a1b2c3 the logs attach:d4e5f6`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("[0a1b2c3d4e5f] #room_1: @user_1: see the logs\n  attachment a1b2c3\n  attachment d4e5f6", string(msg))
+	})
 }
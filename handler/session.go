@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"practice-run/chat"
+)
+
+// SessionPolicy decides what happens when a user connects while another
+// connection for the same username is alive.
+type SessionPolicy int
+
+const (
+	// RejectDuplicateSessions refuses the new connection.
+	RejectDuplicateSessions SessionPolicy = iota
+	// TakeOverSessions closes the old connection and moves its room
+	// memberships to the new one.
+	TakeOverSessions
+)
+
+// CloseSessionTakenOver is the close code of connections replaced by a new
+// connection for the same user.
+const CloseSessionTakenOver = 4000
+
+type SessionTakenOverHandler struct{}
+
+func (h *SessionTakenOverHandler) Handle(event chat.Event, m *ChatMember) error {
+	return m.Close(CloseSessionTakenOver, "session taken over by a new connection")
+}
@@ -0,0 +1,99 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"practice-run/chat"
+	"practice-run/handler"
+	"practice-run/handler/mocks"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestSessionPolicy() {
+	s.Run("reject duplicate sessions", func() {
+		// Given
+		chatService := mocks.NewChatService(s.ctrl)
+		chatService.EXPECT().IsConnected(gomock.Any(), "user_1").Return(true)
+
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, chatService, handler.Options{SessionPolicy: handler.RejectDuplicateSessions}))
+		defer server.Close()
+
+		token, _ := s.authenticator.Sign("user_1", time.Minute)
+
+		// When
+		conn, res, err := websocket.DefaultDialer.Dial(wsUrl(server), bearer(token))
+
+		// Then
+		s.Error(err)
+		s.Equal(http.StatusConflict, res.StatusCode)
+		s.Nil(conn)
+	})
+
+	s.Run("take over duplicate sessions", func() {
+		// Given
+		chatService := mocks.NewChatService(s.ctrl)
+		chatService.EXPECT().Takeover(gomock.Any(), gomock.Any()).Return(nil, nil)
+		chatService.EXPECT().Disconnect(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		chatService.EXPECT().Unread(gomock.Any(), "user_1").Return(nil, nil)
+
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, chatService, handler.Options{SessionPolicy: handler.TakeOverSessions}))
+		defer server.Close()
+
+		token, _ := s.authenticator.Sign("user_1", time.Minute)
+
+		// When
+		conn, res, err := websocket.DefaultDialer.Dial(wsUrl(server), bearer(token))
+
+		// Then
+		s.NoError(err)
+		s.Equal(http.StatusSwitchingProtocols, res.StatusCode)
+		s.NotNil(conn)
+	})
+
+	s.Run("report the unread messages of taken over sessions", func() {
+		// Given
+		chatService := mocks.NewChatService(s.ctrl)
+		chatService.EXPECT().Takeover(gomock.Any(), gomock.Any()).Return(nil, nil)
+		chatService.EXPECT().Disconnect(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		chatService.EXPECT().Unread(gomock.Any(), "user_1").Return([]chat.UnreadCount{{Room: "room_1", Count: 3}}, nil)
+
+		server := httptest.NewServer(handler.NewWebSocketHandler(&websocket.Upgrader{}, s.authenticator, chatService, handler.Options{SessionPolicy: handler.TakeOverSessions}))
+		defer server.Close()
+
+		token, _ := s.authenticator.Sign("user_1", time.Minute)
+
+		// When
+		conn, _, err := websocket.DefaultDialer.Dial(wsUrl(server), bearer(token))
+		s.Require().NoError(err)
+		defer conn.Close()
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("unread: 1 rooms\n#room_1: 3 unread", string(msg))
+	})
+
+	s.Run("close taken over sessions", func() {
+		// Given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+			s.Require().NoError(err)
+
+			handler.NewChatMember("user_1", conn).Notify(&chat.SessionTakenOverEvent{})
+		}))
+		defer server.Close()
+
+		// When
+		cn, _, err := websocket.DefaultDialer.Dial(wsUrl(server), nil)
+		s.Require().NoError(err)
+		defer cn.Close()
+
+		_, _, err = cn.ReadMessage()
+
+		// Then
+		s.True(websocket.IsCloseError(err, handler.CloseSessionTakenOver), "expected session taken over close, got: %v", err)
+	})
+}
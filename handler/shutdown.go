@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"practice-run/chat"
+
+	"github.com/gorilla/websocket"
+)
+
+// Shutdown stops accepting connections and commands, and waits for the
+// commands being executed to complete or for ctx to be done. Connections are
+// closed by broadcasting a chat.ServerShutdownEvent afterwards.
+func (h *WebSocketHandler) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.draining = true
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// begin registers a command about to be executed, unless the handler is
+// shutting down.
+func (h *WebSocketHandler) begin() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.draining {
+		return false
+	}
+
+	h.inflight.Add(1)
+
+	return true
+}
+
+func (h *WebSocketHandler) isDraining() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.draining
+}
+
+type ServerShutdownHandler struct{}
+
+func (h *ServerShutdownHandler) Handle(event chat.Event, m *ChatMember) error {
+	m.WriteMessage("server is shutting down")
+	return m.Close(websocket.CloseGoingAway, "server shutting down")
+}
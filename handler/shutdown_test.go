@@ -0,0 +1,128 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"practice-run/chat"
+	"practice-run/handler"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestShutdown() {
+	s.Run("reject new connections", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		err := s.handler.Shutdown(context.Background())
+		s.Require().NoError(err)
+
+		token, _ := s.authenticator.Sign("user_1", time.Minute)
+
+		// When
+		conn, res, err := websocket.DefaultDialer.Dial(wsUrl(server), bearer(token))
+
+		// Then
+		s.Error(err)
+		s.Equal(http.StatusServiceUnavailable, res.StatusCode)
+		s.Nil(conn)
+	})
+
+	s.Run("wait for in-flight commands", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1", "user_1").DoAndReturn(func(_ context.Context, _, _ string) (*chat.Room, error) {
+			close(started)
+			<-release
+			return &chat.Room{}, nil
+		})
+
+		conn := s.createConnection(server, "user_1")
+
+		err := conn.WriteMessage(websocket.TextMessage, []byte(`/create #room_1`))
+		s.Require().NoError(err)
+		<-started
+
+		// When
+		done := make(chan error)
+		go func() {
+			done <- s.handler.Shutdown(context.Background())
+		}()
+
+		// Then
+		select {
+		case <-done:
+			s.Fail("shutdown returned before the command completed")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		s.NoError(<-done)
+
+		_, msg, _ := conn.ReadMessage()
+		s.Equal(`#room_1 created`, string(msg))
+	})
+
+	s.Run("give up waiting when the context is done", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1", "user_1").DoAndReturn(func(_ context.Context, _, _ string) (*chat.Room, error) {
+			close(started)
+			<-release
+			return &chat.Room{}, nil
+		})
+
+		conn := s.createConnection(server, "user_1")
+
+		err := conn.WriteMessage(websocket.TextMessage, []byte(`/create #room_1`))
+		s.Require().NoError(err)
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// When
+		err = s.handler.Shutdown(ctx)
+
+		// Then
+		s.ErrorIs(err, context.DeadlineExceeded)
+	})
+
+	s.Run("notify members and close their connections", func() {
+		// Given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+			s.Require().NoError(err)
+
+			handler.NewChatMember("user_1", conn).Notify(&chat.ServerShutdownEvent{})
+		}))
+		defer server.Close()
+
+		// When
+		cn, _, err := websocket.DefaultDialer.Dial(wsUrl(server), nil)
+		s.Require().NoError(err)
+		defer cn.Close()
+
+		_, msg, err := cn.ReadMessage()
+		s.Require().NoError(err)
+
+		_, _, err = cn.ReadMessage()
+
+		// Then
+		s.Equal("server is shutting down", string(msg))
+		s.True(websocket.IsCloseError(err, websocket.CloseGoingAway), "expected going away close, got: %v", err)
+	})
+}
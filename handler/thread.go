@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ReplyCommandRegex  = regexp.MustCompile(`^/(?P<command>reply)\s+(?P<id>[0-9a-f]+)\s+(?P<message>.+)$`)
+	ThreadCommandRegex = regexp.MustCompile(`^/(?P<command>thread)\s+(?P<id>[0-9a-f]+)$`)
+	FollowCommandRegex = regexp.MustCompile(`^/(?P<command>follow|unfollow)\s+(?P<id>[0-9a-f]+)$`)
+)
+
+type ReplyCommandFactory struct{}
+
+func (f *ReplyCommandFactory) CreateCommand(match []string) (Command, error) {
+	message, attachments := parseAttachments(match[3])
+
+	return &ReplyCommand{ParentID: match[2], Message: message, Attachments: attachments}, nil
+}
+
+type ReplyCommand struct {
+	ParentID    string
+	Message     string
+	Attachments []string
+}
+
+func (c *ReplyCommand) Name() string {
+	return "reply"
+}
+
+func (c *ReplyCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	sent, err := service.Reply(ctx, m, c.ParentID, c.Message, c.Attachments...)
+	if err != nil {
+		return fmt.Errorf("failed to reply: %w", err)
+	}
+
+	lines := []string{fmt.Sprintf("%s#%s: @%s replied to [%s]: %s", messageID(sent.ID), sent.Room, m.Username(), sent.ParentID, c.Message)}
+	for _, id := range c.Attachments {
+		lines = append(lines, fmt.Sprintf("  attachment %s", id))
+	}
+
+	m.WriteMessage(strings.Join(lines, "\n"))
+
+	return nil
+}
+
+type ThreadCommandFactory struct{}
+
+func (f *ThreadCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &ThreadCommand{MessageID: match[2]}, nil
+}
+
+type ThreadCommand struct {
+	MessageID string
+}
+
+func (c *ThreadCommand) Name() string {
+	return "thread"
+}
+
+func (c *ThreadCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	thread, err := service.Thread(ctx, m, c.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	root := thread[0]
+
+	lines := []string{fmt.Sprintf("#%s thread [%s]: %d replies", root.Room, root.ID, len(thread)-1)}
+	for _, message := range thread {
+		lines = append(lines, messageLines(message)...)
+	}
+
+	m.WriteMessage(strings.Join(lines, "\n"))
+
+	return nil
+}
+
+type FollowCommandFactory struct{}
+
+func (f *FollowCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &FollowCommand{Unfollow: match[1] == "unfollow", MessageID: match[2]}, nil
+}
+
+// FollowCommand changes whether the member is notified of the replies of a
+// thread.
+type FollowCommand struct {
+	Unfollow  bool
+	MessageID string
+}
+
+func (c *FollowCommand) Name() string {
+	if c.Unfollow {
+		return "unfollow"
+	}
+
+	return "follow"
+}
+
+func (c *FollowCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	if c.Unfollow {
+		root, err := service.Unfollow(ctx, m, c.MessageID)
+		if err != nil {
+			return fmt.Errorf("failed to unfollow thread: %w", err)
+		}
+
+		m.WriteMessage(fmt.Sprintf("you've unfollowed the thread [%s] of #%s", root.ID, root.Room))
+
+		return nil
+	}
+
+	root, err := service.Follow(ctx, m, c.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to follow thread: %w", err)
+	}
+
+	m.WriteMessage(fmt.Sprintf("you're following the thread [%s] of #%s", root.ID, root.Room))
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/chat"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestReply() {
+	s.Run("ok", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().Reply(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f", "see the logs", "a1b2c3").
+			Return(&chat.Message{ID: "f5e4d3c2b1a0", Room: "room_1", ParentID: "0a1b2c3d4e5f"}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/reply 0a1b2c3d4e5f see the logs attach:a1b2c3`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("[f5e4d3c2b1a0] #room_1: @user_1 replied to [0a1b2c3d4e5f]: see the logs\n  attachment a1b2c3", string(msg))
+	})
+
+	s.Run("message not found", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().Reply(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f", "go ahead").Return(nil, chat.ErrMessageNotFound)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/reply 0a1b2c3d4e5f go ahead`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("error: failed to reply: message not found", string(msg))
+	})
+}
+
+func (s *Suite) TestThread() {
+	s.Run("ok", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		sentAt := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+		s.chatService.EXPECT().Thread(gomock.Any(), gomock.Any(), "f5e4d3c2b1a0").Return([]*chat.Message{
+			{ID: "0a1b2c3d4e5f", Room: "room_1", Sender: "user_1", Text: "deploy?", SentAt: sentAt, Replies: 1},
+			{ID: "f5e4d3c2b1a0", Room: "room_1", Sender: "user_2", Text: "go ahead", SentAt: sentAt.Add(time.Minute), ParentID: "0a1b2c3d4e5f"},
+		}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/thread f5e4d3c2b1a0`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("#room_1 thread [0a1b2c3d4e5f]: 1 replies\n"+
+			"[0a1b2c3d4e5f] 2026-10-19 09:30 @user_1: deploy?\n"+
+			"  1 replies\n"+
+			"[f5e4d3c2b1a0] 2026-10-19 09:31 @user_2: go ahead", string(msg))
+	})
+}
+
+func (s *Suite) TestFollow() {
+	s.Run("follow", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().Follow(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f").Return(&chat.Message{ID: "0a1b2c3d4e5f", Room: "room_1"}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/follow 0a1b2c3d4e5f`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("you're following the thread [0a1b2c3d4e5f] of #room_1", string(msg))
+	})
+
+	s.Run("unfollow", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().Unfollow(gomock.Any(), gomock.Any(), "0a1b2c3d4e5f").Return(&chat.Message{ID: "0a1b2c3d4e5f", Room: "room_1"}, nil)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/unfollow 0a1b2c3d4e5f`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("you've unfollowed the thread [0a1b2c3d4e5f] of #room_1", string(msg))
+	})
+}
@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"practice-run/chat"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestTracing() {
+	s.Run("span per command within the connection span", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		var commandSpan trace.SpanContext
+		s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1", "user_1").DoAndReturn(func(ctx context.Context, _, _ string) (*chat.Room, error) {
+			commandSpan = trace.SpanContextFromContext(ctx)
+			return &chat.Room{}, nil
+		})
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/create #room_1`)
+		_, _, _ = conn.ReadMessage()
+		_ = conn.Close()
+
+		// Then
+		connection := s.waitForSpan("websocket.connection")
+		command := s.waitForSpan("command create_room")
+		s.Equal(commandSpan.SpanID(), command.SpanContext().SpanID())
+		s.Equal(connection.SpanContext().SpanID(), command.Parent().SpanID())
+	})
+
+	s.Run("record failed commands", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().CreateRoom(gomock.Any(), "room_1", "user_1").Return(nil, errors.New("some error"))
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/create #room_1`)
+		_, _, _ = conn.ReadMessage()
+
+		// Then
+		command := s.waitForSpan("command create_room")
+		s.Equal(codes.Error, command.Status().Code)
+	})
+}
+
+// waitForSpan waits until a span named name has ended, and returns it.
+func (s *Suite) waitForSpan(name string) sdktrace.ReadOnlySpan {
+	var found sdktrace.ReadOnlySpan
+	s.Require().Eventually(func() bool {
+		for _, span := range s.spans.Ended() {
+			if span.Name() == name {
+				found = span
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	return found
+}
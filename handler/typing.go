@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"practice-run/chat"
+	"regexp"
+	"time"
+)
+
+var TypingCommandRegex = regexp.MustCompile(`^/(?P<command>typing)\s+#(?P<roomName>\w+)$`)
+
+type TypingCommandFactory struct{}
+
+func (f *TypingCommandFactory) CreateCommand(match []string) (Command, error) {
+	return &TypingCommand{RoomName: match[2]}, nil
+}
+
+// TypingCommand signals that the member is typing a message. Nothing is
+// written back unless it fails, clients sending it as the member types.
+type TypingCommand struct {
+	RoomName string
+}
+
+func (c *TypingCommand) Name() string {
+	return "typing"
+}
+
+func (c *TypingCommand) Room() string {
+	return c.RoomName
+}
+
+func (c *TypingCommand) Execute(ctx context.Context, m *ChatMember, service chatService) error {
+	err := service.Typing(ctx, c.RoomName, m)
+	if err != nil {
+		return fmt.Errorf("failed to signal typing: %w", err)
+	}
+
+	return nil
+}
+
+// TypingHandler writes until when the member is shown typing, for clients to
+// stop showing it then, or as soon as it sends a message.
+type TypingHandler struct{}
+
+func (h *TypingHandler) Handle(event chat.Event, m *ChatMember) error {
+	e := event.(*chat.TypingEvent)
+	m.WriteMessage(fmt.Sprintf("#%s: @%s is typing until %s", e.RoomName, e.Username, e.ExpiresAt.UTC().Format(time.RFC3339)))
+	return nil
+}
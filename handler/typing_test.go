@@ -0,0 +1,48 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"practice-run/chat"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *Suite) TestTyping() {
+	s.Run("ok", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		typed := make(chan struct{})
+		s.chatService.EXPECT().Typing(gomock.Any(), "room_1", gomock.Any()).DoAndReturn(func(any, any, any) error {
+			close(typed)
+			return nil
+		})
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/typing #room_1`)
+
+		// Then
+		<-typed
+	})
+
+	s.Run("not a room member", func() {
+		// Given
+		server := httptest.NewServer(s.handler)
+		defer server.Close()
+
+		s.chatService.EXPECT().Typing(gomock.Any(), "room_1", gomock.Any()).Return(chat.ErrNotRoomMember)
+
+		conn := s.createConnection(server, "user_1")
+
+		// When
+		s.writeMessage(conn, `/typing #room_1`)
+
+		_, msg, _ := conn.ReadMessage()
+
+		// Then
+		s.Equal("error: failed to signal typing: not a room member", string(msg))
+	})
+}
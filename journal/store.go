@@ -0,0 +1,150 @@
+// Package journal keeps the changes of the state of the chat service on disk,
+// for it to be rebuilt when the server restarts.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+const (
+	logFile      = "journal.log"
+	snapshotFile = "snapshot.json"
+)
+
+// Store keeps records in a directory: the records appended since the last
+// compaction in journal.log, one per line, and the snapshot they follow in
+// snapshot.json. Records are appended without syncing them, which survives
+// crashes of the process but not of the system.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+	log *os.File
+}
+
+func Open(dir string) (*Store, error) {
+	err := os.MkdirAll(dir, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	log, err := os.OpenFile(filepath.Join(dir, logFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	return &Store{dir: dir, log: log}, nil
+}
+
+// Append appends record, which must not contain newlines, to the log.
+func (s *Store) Append(record []byte) error {
+	if bytes.IndexByte(record, '\n') != -1 {
+		return errors.New("record contains a newline")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.log.Write(append(slices.Clip(record), '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to append to journal: %w", err)
+	}
+
+	return nil
+}
+
+// Load returns the last snapshot, nil if there's none, and the records
+// appended since, in order. A last record truncated by a crash is left out.
+func (s *Store) Load() ([]byte, [][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, err := os.ReadFile(filepath.Join(s.dir, snapshotFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	log, err := os.Open(filepath.Join(s.dir, logFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	defer log.Close()
+
+	var records [][]byte
+
+	reader := bufio.NewReader(log)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read journal: %w", err)
+		}
+
+		records = append(records, bytes.TrimSuffix(line, []byte("\n")))
+	}
+
+	return snapshot, records, nil
+}
+
+// Compact replaces the snapshot by snapshot, and empties the log. The
+// snapshot is written to a temporary file renamed over the previous one, so
+// that a crash leaves either of them. A crash before the log is emptied
+// leaves the records the snapshot already includes, which are replayed again.
+func (s *Store) Compact(snapshot []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.dir, snapshotFile)
+
+	tmp, err := os.CreateTemp(s.dir, snapshotFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(snapshot); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := s.log.Truncate(0); err != nil {
+		return fmt.Errorf("failed to compact journal: %w", err)
+	}
+
+	return nil
+}
+
+// Close syncs the log and closes it.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.log.Sync(); err != nil {
+		_ = s.log.Close()
+		return fmt.Errorf("failed to sync journal: %w", err)
+	}
+
+	return s.log.Close()
+}
@@ -0,0 +1,105 @@
+package journal_test
+
+import (
+	"os"
+	"path/filepath"
+	"practice-run/journal"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StoreSuite struct {
+	suite.Suite
+	dir   string
+	store *journal.Store
+}
+
+func TestStore(t *testing.T) {
+	suite.Run(t, new(StoreSuite))
+}
+
+func (s *StoreSuite) SetupSubTest() {
+	s.dir = s.T().TempDir()
+
+	store, err := journal.Open(s.dir)
+	s.Require().NoError(err)
+
+	s.store = store
+	s.T().Cleanup(func() {
+		_ = s.store.Close()
+	})
+}
+
+func (s *StoreSuite) TestLoad() {
+	s.Run("empty journal", func() {
+		// When
+		snapshot, records, err := s.store.Load()
+
+		// Then
+		s.NoError(err)
+		s.Nil(snapshot)
+		s.Empty(records)
+	})
+
+	s.Run("load the records appended before reopening", func() {
+		// Given
+		s.Require().NoError(s.store.Append([]byte(`{"n":1}`)))
+		s.Require().NoError(s.store.Append([]byte(`{"n":2}`)))
+		s.Require().NoError(s.store.Close())
+
+		// When
+		reopened, err := journal.Open(s.dir)
+		s.Require().NoError(err)
+		s.store = reopened
+		_, records, err := reopened.Load()
+
+		// Then
+		s.NoError(err)
+		s.Equal([][]byte{[]byte(`{"n":1}`), []byte(`{"n":2}`)}, records)
+	})
+
+	s.Run("leave out a truncated last record", func() {
+		// Given
+		s.Require().NoError(s.store.Append([]byte(`{"n":1}`)))
+		log, err := os.OpenFile(filepath.Join(s.dir, "journal.log"), os.O_WRONLY|os.O_APPEND, 0)
+		s.Require().NoError(err)
+		_, _ = log.WriteString(`{"n":`)
+		_ = log.Close()
+
+		// When
+		_, records, err := s.store.Load()
+
+		// Then
+		s.NoError(err)
+		s.Equal([][]byte{[]byte(`{"n":1}`)}, records)
+	})
+}
+
+func (s *StoreSuite) TestAppend() {
+	s.Run("reject records with newlines", func() {
+		// When
+		err := s.store.Append([]byte("{\n}"))
+
+		// Then
+		s.Error(err)
+	})
+}
+
+func (s *StoreSuite) TestCompact() {
+	s.Run("replace the records by the snapshot", func() {
+		// Given
+		s.Require().NoError(s.store.Append([]byte(`{"n":1}`)))
+
+		// When
+		err := s.store.Compact([]byte(`{"rooms":[]}`))
+		s.Require().NoError(s.store.Append([]byte(`{"n":2}`)))
+
+		// Then
+		s.NoError(err)
+		snapshot, records, err := s.store.Load()
+		s.NoError(err)
+		s.Equal([]byte(`{"rooms":[]}`), snapshot)
+		s.Equal([][]byte{[]byte(`{"n":2}`)}, records)
+	})
+}
@@ -1,21 +1,239 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"practice-run/attachment"
+	"practice-run/chat"
+	"practice-run/config"
+	"practice-run/peer"
 	"practice-run/provider"
+	"syscall"
 )
 
 func main() {
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal("Config: ", err)
+	}
+
+	logger := provider.Logger(cfg)
+
+	shutdownTracing, err := provider.Tracing(cfg)
+	if err != nil {
+		fatal(logger, "failed to set up tracing", err)
+	}
+
+	accountService, err := provider.AccountService(cfg)
+	if err != nil {
+		fatal(logger, "failed to open accounts", err)
+	}
+
+	var (
+		peers      map[string]string
+		peerClient *peer.Client
+	)
+	if cfg.Cluster.Mode == "sharded" {
+		peers, err = provider.Peers(cfg)
+		if err != nil {
+			fatal(logger, "failed to load peers", err)
+		}
+
+		peerClient = provider.PeerClient(cfg, peers)
+	}
+
+	var (
+		attachmentStore  *attachment.Store
+		attachmentOption chat.Option
+	)
+	if cfg.Attachments.Dir != "" {
+		attachmentStore, err = provider.AttachmentStore(cfg)
+		if err != nil {
+			fatal(logger, "failed to open attachment store", err)
+		}
+
+		attachmentOption = provider.AttachmentOption(attachmentStore)
+	}
+
+	var journalOption chat.Option
+	if cfg.Journal.Dir != "" {
+		journalStore, err := provider.JournalStore(cfg)
+		if err != nil {
+			fatal(logger, "failed to open journal", err)
+		}
+
+		journalOption = provider.JournalOption(cfg, journalStore)
+	}
+
+	chatService := provider.ChatService(logger, accountService, provider.ClusterOption(cfg, peerClient, peers), attachmentOption, journalOption)
+
+	clusterCtx, leaveCluster := context.WithCancel(context.Background())
+	defer leaveCluster()
+
+	err = chatService.Start(clusterCtx)
+	if err != nil {
+		fatal(logger, "failed to start chat service", err)
+	}
+
+	var peerServer *http.Server
+	if peerClient != nil {
+		peerServer = &http.Server{Addr: cfg.Cluster.Addr, Handler: provider.PeerHandler(cfg, logger, chatService)}
+		go serve(cfg, logger, peerServer)
+
+		go provider.WatchPeers(clusterCtx, cfg, logger, peers, peerClient, chatService)
+	}
+
+	authenticator, err := provider.Authenticator(cfg, accountService)
+	if err != nil {
+		fatal(logger, "failed to set up authentication", err)
+	}
+
+	grpcServer, err := provider.GRPCServer(cfg, logger, chatService, authenticator)
+	if err != nil {
+		fatal(logger, "failed to set up gRPC server", err)
+	}
+
+	go func() {
+		lis, err := net.Listen("tcp", cfg.GRPC.Addr)
+		if err != nil {
+			fatal(logger, "failed to listen", err)
+		}
+
+		err = grpcServer.Serve(lis)
+		if err != nil {
+			fatal(logger, "failed to serve gRPC", err)
+		}
+	}()
+
+	admins := provider.Admins(cfg, accountService)
+
+	wsHandler := provider.WebSocketHandler(cfg, logger, chatService, authenticator, admins)
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = fmt.Fprintf(w, "Practice Run")
 	})
 
-	http.Handle("/ws", provider.WebSocketHandler())
+	mux.Handle("POST /register", provider.RegisterHandler(logger, accountService))
+	mux.Handle("POST /login", provider.LoginHandler(logger, accountService))
+	mux.Handle("/ws", wsHandler)
 
-	err := http.ListenAndServe(":8080", nil)
+	if attachmentStore != nil {
+		attachmentHandler := provider.AttachmentHandler(logger, attachmentStore, chatService, authenticator)
+		mux.Handle("POST /attachments", attachmentHandler)
+		mux.Handle("GET /attachments/{id}", attachmentHandler)
+		mux.Handle("GET /attachments/{id}/thumbnail", attachmentHandler)
+	}
+
+	if cfg.Metrics.Enabled {
+		metricsHandler, err := provider.MetricsHandler(chatService)
+		if err != nil {
+			fatal(logger, "failed to set up metrics", err)
+		}
+
+		mux.Handle("GET "+cfg.Metrics.Path, metricsHandler)
+	}
+
+	httpServer := &http.Server{Addr: cfg.HTTP.Addr, Handler: mux}
+	go serve(cfg, logger, httpServer)
+
+	var adminServer *http.Server
+	if cfg.Admin.Addr != "" {
+		adminServer = &http.Server{Addr: cfg.Admin.Addr, Handler: provider.AdminHandler(logger, chatService, authenticator, admins)}
+		go serve(cfg, logger, adminServer)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	<-ctx.Done()
+	stop()
+
+	logger.Info("shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting connections and commands, and let the running ones complete
+	err = wsHandler.Shutdown(ctx)
 	if err != nil {
-		log.Fatal("ListenAndServe: ", err)
+		logger.Error("in-flight commands did not complete", slog.Any("error", err))
 	}
+
+	// Notify every member of this node and close their connections
+	chatService.BroadcastLocal(ctx, &chat.ServerShutdownEvent{})
+
+	err = httpServer.Shutdown(ctx)
+	if err != nil {
+		logger.Error("failed to shut down HTTP server", slog.Any("error", err))
+	}
+
+	if adminServer != nil {
+		err = adminServer.Shutdown(ctx)
+		if err != nil {
+			logger.Error("failed to shut down admin server", slog.Any("error", err))
+		}
+	}
+
+	if peerServer != nil {
+		err = peerServer.Shutdown(ctx)
+		if err != nil {
+			logger.Error("failed to shut down peer server", slog.Any("error", err))
+		}
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+
+	// Tell the other nodes this one is leaving, and compact the journal
+	err = chatService.Stop(ctx)
+	if err != nil {
+		logger.Error("failed to stop chat service", slog.Any("error", err))
+	}
+
+	leaveCluster()
+
+	err = shutdownTracing(ctx)
+	if err != nil {
+		logger.Error("failed to flush traces", slog.Any("error", err))
+	}
+
+	// Accounts are written synchronously, and rooms recorded in the journal
+	// when configured: there is no other state to flush.
+}
+
+// serve serves HTTP, over TLS when configured, until the server is shut down.
+func serve(cfg *config.Config, logger *slog.Logger, server *http.Server) {
+	var err error
+	if cfg.TLS.Enabled() {
+		err = server.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal(logger, "failed to serve HTTP", err)
+	}
+}
+
+// fatal logs err, the cause of msg, and exits.
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, slog.Any("error", err))
+	os.Exit(1)
 }
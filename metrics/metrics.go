@@ -0,0 +1,50 @@
+// Package metrics defines the Prometheus metrics of the server, registered
+// with the default registry.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "practice_run"
+
+// Command results.
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+var (
+	MessagesSent = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_sent_total",
+		Help:      "Messages sent to rooms.",
+	})
+
+	Commands = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "commands_total",
+		Help:      "Commands executed, by name and result.",
+	}, []string{"command", "result"})
+
+	CommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "command_duration_seconds",
+		Help:      "Time taken to execute commands, by name.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"command"})
+
+	ParseFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "parse_failures_total",
+		Help:      "Client messages that couldn't be parsed as a command.",
+	})
+
+	BroadcastDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "broadcast_duration_seconds",
+		Help:      "Time taken to deliver an event to every recipient.",
+		Buckets:   []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25},
+	})
+)
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// State is the state of the chat service at the time of a scrape.
+type State struct {
+	Connections int
+	RoomMembers map[string]int // member count by room name
+}
+
+var (
+	connectionsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "connections"), "Connected members.", nil, nil)
+	roomsDesc       = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "rooms"), "Rooms.", nil, nil)
+	roomMembersDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "room_members"), "Members of each room.", []string{"room"}, nil)
+)
+
+// StateCollector reports the gauges computed from the service state, read
+// on every scrape rather than kept up to date.
+type StateCollector struct {
+	state func() State
+}
+
+func NewStateCollector(state func() State) *StateCollector {
+	return &StateCollector{state: state}
+}
+
+func (c *StateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- connectionsDesc
+	ch <- roomsDesc
+	ch <- roomMembersDesc
+}
+
+func (c *StateCollector) Collect(ch chan<- prometheus.Metric) {
+	state := c.state()
+
+	ch <- prometheus.MustNewConstMetric(connectionsDesc, prometheus.GaugeValue, float64(state.Connections))
+	ch <- prometheus.MustNewConstMetric(roomsDesc, prometheus.GaugeValue, float64(len(state.RoomMembers)))
+
+	for room, members := range state.RoomMembers {
+		ch <- prometheus.MustNewConstMetric(roomMembersDesc, prometheus.GaugeValue, float64(members), room)
+	}
+}
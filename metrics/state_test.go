@@ -0,0 +1,49 @@
+package metrics_test
+
+import (
+	"practice-run/metrics"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+type Suite struct {
+	suite.Suite
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) TestStateCollector() {
+	s.Run("gauges", func() {
+		// Given
+		collector := metrics.NewStateCollector(func() metrics.State {
+			return metrics.State{
+				Connections: 3,
+				RoomMembers: map[string]int{"room_1": 2, "room_2": 0},
+			}
+		})
+
+		expected := `
+# HELP practice_run_connections Connected members.
+# TYPE practice_run_connections gauge
+practice_run_connections 3
+# HELP practice_run_room_members Members of each room.
+# TYPE practice_run_room_members gauge
+practice_run_room_members{room="room_1"} 2
+practice_run_room_members{room="room_2"} 0
+# HELP practice_run_rooms Rooms.
+# TYPE practice_run_rooms gauge
+practice_run_rooms 2
+`
+
+		// When
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected))
+
+		// Then
+		s.NoError(err)
+	})
+}
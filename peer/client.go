@@ -0,0 +1,85 @@
+// Package peer carries the calls between the nodes of a sharded cluster over
+// HTTP.
+package peer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// CallPath is the path of the endpoint serving peer calls.
+const CallPath = "/cluster/call"
+
+// maxPayloadSize bounds the size of calls and results, which may carry the
+// whole state of a room.
+const maxPayloadSize = 16 << 20
+
+// Client calls the nodes of a sharded cluster, authenticating with the secret
+// they share.
+type Client struct {
+	mu    sync.RWMutex
+	peers map[string]string // base URL of each node
+
+	secret string
+	http   *http.Client
+}
+
+func NewClient(peers map[string]string, secret string) *Client {
+	return &Client{
+		peers:  peers,
+		secret: secret,
+		http:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SetPeers replaces the base URL of each node.
+func (c *Client) SetPeers(peers map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.peers = peers
+}
+
+func (c *Client) Call(ctx context.Context, node string, payload []byte) ([]byte, error) {
+	c.mu.RLock()
+	url, ok := c.peers[node]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown node %s", node)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+CallPath, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.secret)
+	req.Header.Set("Content-Type", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxPayloadSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", res.StatusCode, bytes.TrimSpace(body))
+	}
+
+	return body, nil
+}
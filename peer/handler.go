@@ -0,0 +1,66 @@
+package peer
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+type chatService interface {
+	HandlePeerCall(ctx context.Context, payload []byte) ([]byte, error)
+}
+
+// Handler serves the calls of the other nodes of a sharded cluster, which
+// must authenticate with the secret they share.
+type Handler struct {
+	chatService chatService
+	secret      string
+	logger      *slog.Logger
+}
+
+func NewHandler(chatService chatService, secret string, logger *slog.Logger) *Handler {
+	return &Handler{chatService: chatService, secret: secret, logger: logger}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != CallPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.secret)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "failed to read call", http.StatusBadRequest)
+		return
+	}
+
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+	result, err := h.chatService.HandlePeerCall(ctx, payload)
+	if err != nil {
+		h.logger.Warn("failed to handle peer call", slog.String("remote_addr", r.RemoteAddr), slog.Any("error", err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(result)
+}
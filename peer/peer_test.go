@@ -0,0 +1,179 @@
+package peer_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"practice-run/peer"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type Suite struct {
+	suite.Suite
+	chatService *echoService
+	server      *httptest.Server
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSubTest() {
+	s.chatService = &echoService{}
+	s.server = httptest.NewServer(peer.NewHandler(s.chatService, "secret", slog.Default()))
+	s.T().Cleanup(s.server.Close)
+}
+
+// echoService returns the calls it handles, and fails those equal to "fail".
+type echoService struct{}
+
+func (e *echoService) HandlePeerCall(ctx context.Context, payload []byte) ([]byte, error) {
+	if string(payload) == "fail" {
+		return nil, errors.New("invalid call")
+	}
+
+	return payload, nil
+}
+
+func (s *Suite) TestCall() {
+	s.Run("ok", func() {
+		// Given
+		client := peer.NewClient(map[string]string{"node_2": s.server.URL}, "secret")
+
+		// When
+		result, err := client.Call(context.Background(), "node_2", []byte(`{"method":"deliver"}`))
+
+		// Then
+		s.NoError(err)
+		s.Equal(`{"method":"deliver"}`, string(result))
+	})
+
+	s.Run("wrong secret", func() {
+		// Given
+		client := peer.NewClient(map[string]string{"node_2": s.server.URL}, "guess")
+
+		// When
+		_, err := client.Call(context.Background(), "node_2", []byte(`{}`))
+
+		// Then
+		s.ErrorContains(err, "401")
+	})
+
+	s.Run("unknown node", func() {
+		// Given
+		client := peer.NewClient(map[string]string{"node_2": s.server.URL}, "secret")
+
+		// When
+		_, err := client.Call(context.Background(), "node_3", []byte(`{}`))
+
+		// Then
+		s.ErrorContains(err, "unknown node node_3")
+	})
+
+	s.Run("failed call", func() {
+		// Given
+		client := peer.NewClient(map[string]string{"node_2": s.server.URL}, "secret")
+
+		// When
+		_, err := client.Call(context.Background(), "node_2", []byte("fail"))
+
+		// Then
+		s.ErrorContains(err, "invalid call")
+	})
+
+	s.Run("only serve posts", func() {
+		// When
+		res, err := http.Get(s.server.URL + peer.CallPath)
+
+		// Then
+		s.Require().NoError(err)
+		defer res.Body.Close()
+		s.Equal(http.StatusMethodNotAllowed, res.StatusCode)
+	})
+}
+
+func (s *Suite) TestPeers() {
+	s.Run("parse entries", func() {
+		// When
+		peers, err := peer.ParsePeers([]string{"node_1=http://10.0.0.1:9091/", "node_2=http://10.0.0.2:9091"})
+
+		// Then
+		s.NoError(err)
+		s.Equal(map[string]string{"node_1": "http://10.0.0.1:9091", "node_2": "http://10.0.0.2:9091"}, peers)
+	})
+
+	s.Run("invalid entries", func() {
+		// When
+		_, err := peer.ParsePeers([]string{"node_1"})
+
+		// Then
+		s.ErrorContains(err, "expected <node>=<url>")
+	})
+
+	s.Run("load file", func() {
+		// Given
+		path := s.writeFile("# nodes\nnode_1 http://10.0.0.1:9091\n\nnode_2 http://10.0.0.2:9091\n")
+
+		// When
+		peers, err := peer.LoadPeers(path)
+
+		// Then
+		s.NoError(err)
+		s.Equal(map[string]string{"node_1": "http://10.0.0.1:9091", "node_2": "http://10.0.0.2:9091"}, peers)
+	})
+
+	s.Run("invalid file", func() {
+		// Given
+		path := s.writeFile("node_1\n")
+
+		// When
+		_, err := peer.LoadPeers(path)
+
+		// Then
+		s.ErrorContains(err, "line 1")
+	})
+
+	s.Run("watch file", func() {
+		// Given
+		path := s.writeFile("node_1 http://10.0.0.1:9091\n")
+		peers, _ := peer.LoadPeers(path)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var mu sync.Mutex
+		var changes []map[string]string
+		go peer.WatchPeers(ctx, path, 10*time.Millisecond, peers, slog.Default(), func(peers map[string]string) {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, peers)
+		})
+
+		// When
+		s.Require().NoError(os.WriteFile(path, []byte("node_1 http://10.0.0.1:9091\nnode_2 http://10.0.0.2:9091\n"), 0o600))
+
+		// Then
+		s.Eventually(func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(changes) == 1
+		}, time.Second, 10*time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		s.Equal([]map[string]string{{"node_1": "http://10.0.0.1:9091", "node_2": "http://10.0.0.2:9091"}}, changes)
+	})
+}
+
+func (s *Suite) writeFile(content string) string {
+	path := filepath.Join(s.T().TempDir(), "peers")
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
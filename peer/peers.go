@@ -0,0 +1,99 @@
+package peer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"strings"
+	"time"
+)
+
+// ParsePeers parses peers given as <node>=<url> entries.
+func ParsePeers(entries []string) (map[string]string, error) {
+	peers := make(map[string]string, len(entries))
+
+	for _, entry := range entries {
+		node, url, ok := strings.Cut(entry, "=")
+		if !ok || node == "" || url == "" {
+			return nil, fmt.Errorf("invalid peer %q: expected <node>=<url>", entry)
+		}
+
+		peers[node] = strings.TrimSuffix(url, "/")
+	}
+
+	return peers, nil
+}
+
+// LoadPeers reads a peer file. Each non-empty line holds a node identifier
+// and its base URL, separated by whitespace; lines starting with # are
+// ignored.
+func LoadPeers(path string) (map[string]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read peer file: %w", err)
+	}
+
+	peers := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid peer file: line %d: expected <node> <url>", line)
+		}
+
+		peers[fields[0]] = strings.TrimSuffix(fields[1], "/")
+	}
+
+	return peers, nil
+}
+
+// WatchPeers reads the peer file every interval until ctx is done, calling
+// onChange with its content whenever it changes. Invalid files are ignored.
+func WatchPeers(ctx context.Context, path string, interval time.Duration, peers map[string]string, logger *slog.Logger, onChange func(map[string]string)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		loaded, err := LoadPeers(path)
+		if err != nil {
+			logger.Error("failed to reload peers", slog.Any("error", err))
+			continue
+		}
+
+		if maps.Equal(loaded, peers) {
+			continue
+		}
+
+		peers = loaded
+
+		logger.Info("peers changed", slog.Int("peers", len(peers)))
+
+		onChange(peers)
+	}
+}
+
+// Nodes returns the identifiers of peers.
+func Nodes(peers map[string]string) []string {
+	nodes := make([]string, 0, len(peers))
+	for node := range peers {
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"practice-run/attachment"
+	"practice-run/auth"
+	"practice-run/chat"
+	"practice-run/config"
+)
+
+func AttachmentStore(cfg *config.Config) (*attachment.Store, error) {
+	return attachment.NewStore(cfg.Attachments.Dir, cfg.Attachments.MaxSize, cfg.Attachments.AllowedTypes)
+}
+
+// AttachmentOption lets messages refer to the attachments of store.
+func AttachmentOption(store *attachment.Store) chat.Option {
+	return chat.WithAttachmentResolver(attachmentResolver{store: store})
+}
+
+func AttachmentHandler(logger *slog.Logger, store *attachment.Store, chatService *chat.Service, authenticator auth.Authenticator) *attachment.Handler {
+	return attachment.NewHandler(store, chatService, authenticator, logger.With(slog.String("component", "attachments")))
+}
+
+type attachmentResolver struct {
+	store *attachment.Store
+}
+
+func (r attachmentResolver) Attachment(_ context.Context, username string, id string) (*chat.Attachment, bool, error) {
+	a, err := r.store.Get(id)
+	if errors.Is(err, attachment.ErrNotFound) {
+		return nil, false, chat.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	resolved := &chat.Attachment{
+		ID:     a.ID,
+		Name:   a.Name,
+		Size:   a.Size,
+		Type:   a.Type,
+		URL:    attachment.URL(a.ID),
+		Width:  a.Width,
+		Height: a.Height,
+	}
+	if a.Thumbnail != nil {
+		resolved.Thumbnail = &chat.Thumbnail{URL: attachment.ThumbnailURL(a.ID), Width: a.Thumbnail.Width, Height: a.Thumbnail.Height}
+	}
+
+	return resolved, a.Uploader == username, nil
+}
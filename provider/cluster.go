@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"practice-run/broker"
+	"practice-run/chat"
+	"practice-run/config"
+	"practice-run/peer"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// peersReloadInterval is the interval at which the peer file is checked for
+// changes.
+const peersReloadInterval = 10 * time.Second
+
+// ClusterOption makes the chat service a node of the configured cluster. In
+// sharded mode, peerClient calls the other peers.
+func ClusterOption(cfg *config.Config, peerClient *peer.Client, peers map[string]string) chat.Option {
+	if cfg.Cluster.Mode == "sharded" {
+		return chat.WithSharding(peerClient, cfg.Cluster.NodeID, peer.Nodes(peers))
+	}
+
+	return chat.WithBroker(Broker(cfg), NodeID(cfg))
+}
+
+// Broker returns the broker the nodes of the cluster share.
+func Broker(cfg *config.Config) chat.Broker {
+	if cfg.Cluster.Broker == "redis" {
+		return broker.NewRedis(redis.NewClient(&redis.Options{Addr: cfg.Cluster.RedisAddr}))
+	}
+
+	return broker.NewInProcess()
+}
+
+// NodeID returns the configured node identifier or, when empty, one made of
+// the host name and a random suffix.
+func NodeID(cfg *config.Config) string {
+	if cfg.Cluster.NodeID != "" {
+		return cfg.Cluster.NodeID
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "node"
+	}
+
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	return hostname + "-" + hex.EncodeToString(suffix)
+}
+
+// Peers returns the nodes of a sharded cluster with their base URL, read from
+// the peer file when configured.
+func Peers(cfg *config.Config) (map[string]string, error) {
+	if cfg.Cluster.PeersFile != "" {
+		return peer.LoadPeers(cfg.Cluster.PeersFile)
+	}
+
+	return peer.ParsePeers(cfg.Cluster.Peers)
+}
+
+func PeerClient(cfg *config.Config, peers map[string]string) *peer.Client {
+	return peer.NewClient(peers, cfg.Cluster.Secret)
+}
+
+func PeerHandler(cfg *config.Config, logger *slog.Logger, chatService *chat.Service) *peer.Handler {
+	return peer.NewHandler(chatService, cfg.Cluster.Secret, logger.With(slog.String("component", "peer")))
+}
+
+// WatchPeers applies the changes of the peer file, if any, to the peer client
+// and the chat service, until ctx is done.
+func WatchPeers(ctx context.Context, cfg *config.Config, logger *slog.Logger, peers map[string]string, peerClient *peer.Client, chatService *chat.Service) {
+	if cfg.Cluster.PeersFile == "" {
+		return
+	}
+
+	peer.WatchPeers(ctx, cfg.Cluster.PeersFile, peersReloadInterval, peers, logger.With(slog.String("component", "peer")), func(peers map[string]string) {
+		peerClient.SetPeers(peers)
+		chatService.SetPeers(ctx, peer.Nodes(peers))
+	})
+}
@@ -0,0 +1,16 @@
+package provider
+
+import (
+	"practice-run/chat"
+	"practice-run/config"
+	"practice-run/journal"
+)
+
+func JournalStore(cfg *config.Config) (*journal.Store, error) {
+	return journal.Open(cfg.Journal.Dir)
+}
+
+// JournalOption records the state of the chat service in store.
+func JournalOption(cfg *config.Config, store *journal.Store) chat.Option {
+	return chat.WithJournal(store, cfg.Journal.CompactEvery)
+}
@@ -1,22 +1,204 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"practice-run/account"
+	"practice-run/admin"
+	"practice-run/auth"
 	"practice-run/chat"
+	"practice-run/config"
 	"practice-run/handler"
+	"practice-run/metrics"
+	"practice-run/rpc"
+	"practice-run/rpc/pb"
 
 	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
-func ChatService() *chat.Service {
-	return chat.NewService()
+// Logger builds the logger described by the configuration and makes it the
+// default.
+func Logger(cfg *config.Config) *slog.Logger {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(cfg.Log.Level))
+
+	options := &slog.HandlerOptions{Level: level}
+
+	var logHandler slog.Handler = slog.NewTextHandler(os.Stderr, options)
+	if cfg.Log.Format == "json" {
+		logHandler = slog.NewJSONHandler(os.Stderr, options)
+	}
+
+	logger := slog.New(logHandler)
+
+	slog.SetDefault(logger)
+
+	return logger
+}
+
+// ChatService returns a chat service configured by the given options, such
+// as a cluster option making it a node of a cluster. Nil options are ignored.
+func ChatService(logger *slog.Logger, accountService *account.Service, options ...chat.Option) *chat.Service {
+	serviceOptions := []chat.Option{chat.WithLogger(logger.With(slog.String("component", "chat")))}
+	if accountService != nil {
+		serviceOptions = append(serviceOptions, chat.WithGroupResolver(accountService))
+	}
+
+	for _, option := range options {
+		if option != nil {
+			serviceOptions = append(serviceOptions, option)
+		}
+	}
+
+	return chat.NewService(serviceOptions...)
 }
 
-func WebSocketHandler() *handler.WebSocketHandler {
+// MetricsHandler serves the metrics of the default Prometheus registry, in
+// which it registers the gauges computed from the state of this node, leaving
+// out the rooms owned by the other nodes of a sharded cluster.
+func MetricsHandler(chatService *chat.Service) (http.Handler, error) {
+	collector := metrics.NewStateCollector(func() metrics.State {
+		ctx := context.Background()
+
+		state := metrics.State{
+			Connections: len(chatService.ConnectedMembers(ctx)),
+			RoomMembers: map[string]int{},
+		}
+
+		for _, room := range chatService.LocalRooms(ctx) {
+			state.RoomMembers[room.Name] = room.Members
+		}
+
+		return state
+	})
+
+	err := prometheus.Register(collector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register metrics: %w", err)
+	}
+
+	return promhttp.Handler(), nil
+}
+
+func AccountService(cfg *config.Config) (*account.Service, error) {
+	store, err := account.OpenFileStore(cfg.Accounts.File)
+	if err != nil {
+		return nil, err
+	}
+
+	accountService := account.NewService(store, cfg.Auth.SessionTTL)
+	accountService.Reserve(cfg.Accounts.Reserved...)
+	accountService.Reserve(cfg.Admin.Users...)
+
+	return accountService, nil
+}
+
+// Authenticator accepts the session tokens issued by the account service, as
+// well as JWTs and API keys when configured. The usernames of API keys are
+// reserved from registration.
+func Authenticator(cfg *config.Config, accountService *account.Service) (auth.Authenticator, error) {
+	chain := auth.Chain{accountService}
+
+	if cfg.Auth.JWTSecret != "" {
+		chain = append(chain, auth.NewJWTAuthenticator([]byte(cfg.Auth.JWTSecret)))
+	}
+
+	if cfg.Auth.APIKeysFile != "" {
+		apiKeys, err := auth.LoadAPIKeys(cfg.Auth.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+
+		accountService.Reserve(apiKeys.Usernames()...)
+		chain = append(chain, apiKeys)
+	}
+
+	return chain, nil
+}
+
+// WebSocketHandler serves chat clients, admins being allowed to use admin
+// commands if not nil.
+func WebSocketHandler(cfg *config.Config, logger *slog.Logger, chatService *chat.Service, authenticator auth.Authenticator, admins *auth.Admins) *handler.WebSocketHandler {
+	sessionPolicy := handler.RejectDuplicateSessions
+	if cfg.SessionPolicy == "takeover" {
+		sessionPolicy = handler.TakeOverSessions
+	}
+
+	options := handler.Options{
+		SessionPolicy:  sessionPolicy,
+		MaxMessageSize: cfg.WebSocket.MaxMessageSize,
+		RateLimit:      cfg.RateLimit.MessagesPerSecond,
+		RateLimitBurst: cfg.RateLimit.Burst,
+		PingInterval:   cfg.WebSocket.PingInterval,
+		IdleTimeout:    cfg.WebSocket.IdleTimeout,
+		Logger:         logger.With(slog.String("component", "websocket")),
+	}
+	if admins != nil {
+		options.Admins = admins
+	}
+
 	return handler.NewWebSocketHandler(
 		&websocket.Upgrader{
-			ReadBufferSize:  1024,
-			WriteBufferSize: 1024,
+			ReadBufferSize:  cfg.WebSocket.ReadBufferSize,
+			WriteBufferSize: cfg.WebSocket.WriteBufferSize,
+			CheckOrigin:     handler.OriginChecker(cfg.WebSocket.AllowedOrigins),
 		},
-		ChatService(),
+		authenticator,
+		chatService,
+		options,
 	)
 }
+
+// Admins are the users listed in the configuration and the members of the
+// listed groups, as stored in the accounts file.
+func Admins(cfg *config.Config, accountService *account.Service) *auth.Admins {
+	var groups auth.GroupResolver
+	if accountService != nil {
+		groups = accountService
+	}
+
+	return auth.NewAdmins(cfg.Admin.Users, cfg.Admin.Groups, groups)
+}
+
+func AdminHandler(logger *slog.Logger, chatService *chat.Service, authenticator auth.Authenticator, admins *auth.Admins) *admin.Handler {
+	return admin.NewHandler(chatService, authenticator, admins, logger.With(slog.String("component", "admin")))
+}
+
+func RegisterHandler(logger *slog.Logger, accountService *account.Service) *handler.RegisterHandler {
+	return handler.NewRegisterHandler(accountService, logger.With(slog.String("component", "accounts")))
+}
+
+func LoginHandler(logger *slog.Logger, accountService *account.Service) *handler.LoginHandler {
+	return handler.NewLoginHandler(accountService, logger.With(slog.String("component", "accounts")))
+}
+
+func GRPCServer(cfg *config.Config, logger *slog.Logger, chatService *chat.Service, authenticator auth.Authenticator) (*grpc.Server, error) {
+	interceptor := rpc.NewAuthInterceptor(authenticator)
+
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(interceptor.Unary),
+		grpc.StreamInterceptor(interceptor.Stream),
+	}
+
+	if cfg.TLS.Enabled() {
+		creds, err := credentials.NewServerTLSFromFile(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls certificate: %w", err)
+		}
+
+		options = append(options, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(options...)
+
+	pb.RegisterChatServer(server, rpc.NewServer(chatService, logger.With(slog.String("component", "grpc"))))
+
+	return server, nil
+}
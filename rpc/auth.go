@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"context"
+	"practice-run/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type usernameKey struct{}
+
+// AuthInterceptor authenticates callers with the bearer token found in the
+// "authorization" metadata, using the same authenticator as the WebSocket
+// endpoint so that both transports share their users.
+type AuthInterceptor struct {
+	authenticator auth.Authenticator
+}
+
+func NewAuthInterceptor(authenticator auth.Authenticator) *AuthInterceptor {
+	return &AuthInterceptor{authenticator: authenticator}
+}
+
+func (i *AuthInterceptor) Unary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := i.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (i *AuthInterceptor) Stream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := i.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+func (i *AuthInterceptor) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, auth.ErrMissingCredentials.Error())
+	}
+
+	token, err := auth.ParseBearer(values[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	username, err := i.authenticator.Authenticate(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, auth.ErrInvalidCredentials.Error())
+	}
+
+	return context.WithValue(ctx, usernameKey{}, username), nil
+}
+
+func usernameFromContext(ctx context.Context) string {
+	username, _ := ctx.Value(usernameKey{}).(string)
+	return username
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,230 @@
+package rpc
+
+import (
+	"log/slog"
+	"practice-run/chat"
+	"practice-run/rpc/pb"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// streamOutboxSize is the number of events a stream may have waiting to be
+// sent before it's ended.
+const streamOutboxSize = 256
+
+type StreamMember struct {
+	stream grpc.ServerStreamingServer[pb.Event]
+	// outbox holds the events waiting to be sent, for slow clients not to
+	// block the chat service notifying them
+	outbox chan *pb.Event
+
+	username string
+	logger   *slog.Logger
+
+	closeOnce sync.Once
+	done      chan struct{}
+	err       error
+}
+
+// NewStreamMember returns a member sending its events on stream until it's
+// done.
+func NewStreamMember(username string, stream grpc.ServerStreamingServer[pb.Event], logger *slog.Logger) *StreamMember {
+	m := &StreamMember{
+		username: username,
+		logger:   logger,
+		stream:   stream,
+		outbox:   make(chan *pb.Event, streamOutboxSize),
+		done:     make(chan struct{}),
+	}
+
+	go m.run()
+
+	return m
+}
+
+// Done is closed when the stream must be ended, because the session has been
+// taken over or the server is shutting down. Err then returns the status the
+// stream ends with.
+func (m *StreamMember) Done() <-chan struct{} {
+	return m.done
+}
+
+func (m *StreamMember) Err() error {
+	<-m.done
+	return m.err
+}
+
+func (m *StreamMember) close(err error) {
+	m.closeOnce.Do(func() {
+		m.err = err
+		close(m.done)
+	})
+}
+
+func (m *StreamMember) Username() string {
+	return m.username
+}
+
+func (m *StreamMember) RemoteAddr() string {
+	p, ok := peer.FromContext(m.stream.Context())
+	if !ok {
+		return ""
+	}
+
+	return p.Addr.String()
+}
+
+// Send queues event to be sent on the stream. Streams of clients not keeping
+// up are ended rather than blocking the sender.
+func (m *StreamMember) Send(event *pb.Event) {
+	select {
+	case m.outbox <- event:
+	case <-m.done:
+	default:
+		m.logger.Error("failed to send event: too many events waiting")
+		m.close(status.Error(codes.ResourceExhausted, "too many events waiting to be sent"))
+	}
+}
+
+func (m *StreamMember) run() {
+	for {
+		select {
+		case event := <-m.outbox:
+			err := m.stream.Send(event)
+			if err != nil {
+				m.logger.Error("failed to send event", slog.Any("error", err))
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
+
+func (m *StreamMember) Notify(event chat.Event) {
+	switch e := event.(type) {
+	case *chat.MessageReceivedEvent:
+		m.Send(&pb.Event{Event: &pb.Event_MessageReceived{MessageReceived: &pb.MessageReceived{
+			RoomName:    e.RoomName,
+			MessageId:   e.MessageID,
+			SenderName:  e.SenderName,
+			Message:     e.Message,
+			Attachments: attachments(e.Attachments),
+			ParentId:    e.ParentID,
+			Replies:     int32(e.Replies),
+		}}})
+	case *chat.MentionEvent:
+		m.Send(&pb.Event{Event: &pb.Event_Mention{Mention: &pb.Mention{
+			RoomName:    e.RoomName,
+			MessageId:   e.MessageID,
+			SenderName:  e.SenderName,
+			Message:     e.Message,
+			Attachments: attachments(e.Attachments),
+			ParentId:    e.ParentID,
+			Mention:     e.Mention,
+		}}})
+	case *chat.MessageEditedEvent:
+		m.Send(&pb.Event{Event: &pb.Event_MessageEdited{MessageEdited: &pb.MessageEdited{
+			RoomName:   e.RoomName,
+			MessageId:  e.MessageID,
+			SenderName: e.SenderName,
+			Message:    e.Message,
+		}}})
+	case *chat.MessageDeletedEvent:
+		m.Send(&pb.Event{Event: &pb.Event_MessageDeleted{MessageDeleted: &pb.MessageDeleted{
+			RoomName:  e.RoomName,
+			MessageId: e.MessageID,
+			DeletedBy: e.DeletedBy,
+		}}})
+	case *chat.ReactionAddedEvent:
+		m.Send(&pb.Event{Event: &pb.Event_ReactionAdded{ReactionAdded: &pb.ReactionAdded{
+			RoomName:  e.RoomName,
+			MessageId: e.MessageID,
+			Username:  e.Username,
+			Emoji:     e.Emoji,
+			Count:     int32(e.Count),
+		}}})
+	case *chat.ReactionRemovedEvent:
+		m.Send(&pb.Event{Event: &pb.Event_ReactionRemoved{ReactionRemoved: &pb.ReactionRemoved{
+			RoomName:  e.RoomName,
+			MessageId: e.MessageID,
+			Username:  e.Username,
+			Emoji:     e.Emoji,
+			Count:     int32(e.Count),
+		}}})
+	case *chat.TypingEvent:
+		m.Send(&pb.Event{Event: &pb.Event_Typing{Typing: &pb.Typing{
+			RoomName:  e.RoomName,
+			Username:  e.Username,
+			ExpiresAt: e.ExpiresAt.UnixMilli(),
+		}}})
+	case *chat.ReadReceiptEvent:
+		m.Send(&pb.Event{Event: &pb.Event_ReadReceipt{ReadReceipt: &pb.ReadReceipt{
+			RoomName:  e.RoomName,
+			MessageId: e.MessageID,
+			Username:  e.Username,
+		}}})
+	case *chat.MemberJoinedEvent:
+		m.Send(&pb.Event{Event: &pb.Event_MemberJoined{MemberJoined: &pb.MemberJoined{
+			RoomName:   e.RoomName,
+			MemberName: e.MemberName,
+		}}})
+	case *chat.MemberLeftEvent:
+		m.Send(&pb.Event{Event: &pb.Event_MemberLeft{MemberLeft: &pb.MemberLeft{
+			RoomName:   e.RoomName,
+			MemberName: e.MemberName,
+		}}})
+	case *chat.InvitedEvent:
+		m.Send(&pb.Event{Event: &pb.Event_Invited{Invited: &pb.Invited{
+			RoomName:    e.RoomName,
+			InviterName: e.InviterName,
+		}}})
+	case *chat.SessionTakenOverEvent:
+		m.close(status.Error(codes.Aborted, "session taken over by a new connection"))
+	case *chat.ServerShutdownEvent:
+		m.close(status.Error(codes.Unavailable, "server shutting down"))
+	case *chat.DisconnectedEvent:
+		m.close(status.Error(codes.Aborted, "disconnected by an administrator"))
+	case *chat.SystemAnnouncementEvent:
+		severity, _ := chat.ParseSeverity(string(e.Severity))
+		m.Send(&pb.Event{Event: &pb.Event_SystemAnnouncement{SystemAnnouncement: &pb.SystemAnnouncement{
+			Message:  e.Message,
+			Severity: string(severity),
+		}}})
+	case *chat.RemovedEvent:
+		m.Send(&pb.Event{Event: &pb.Event_Removed{Removed: &pb.Removed{
+			RoomName: e.RoomName,
+		}}})
+	case *chat.RoomDeletedEvent:
+		m.Send(&pb.Event{Event: &pb.Event_RoomDeleted{RoomDeleted: &pb.RoomDeleted{
+			RoomName: e.RoomName,
+		}}})
+	default:
+		m.logger.Error("failed to notify member: unknown event", slog.String("event", event.Name()))
+	}
+}
+
+func attachments(attachments []chat.Attachment) []*pb.Attachment {
+	var messages []*pb.Attachment
+	for _, a := range attachments {
+		message := &pb.Attachment{
+			Id:     a.ID,
+			Name:   a.Name,
+			Size:   a.Size,
+			Type:   a.Type,
+			Url:    a.URL,
+			Width:  int32(a.Width),
+			Height: int32(a.Height),
+		}
+		if a.Thumbnail != nil {
+			message.Thumbnail = &pb.Thumbnail{Url: a.Thumbnail.URL, Width: int32(a.Thumbnail.Width), Height: int32(a.Thumbnail.Height)}
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages
+}
@@ -0,0 +1,289 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"practice-run/chat"
+	"practice-run/rpc/pb"
+	"regexp"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate protoc --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative chat.proto
+
+var roomNameRegex = regexp.MustCompile(`^\w+$`)
+
+type chatService interface {
+	Connect(ctx context.Context, member chat.Member) error
+	Disconnect(ctx context.Context, member chat.Member) error
+	CreateRoom(ctx context.Context, roomName string, owner string) (*chat.Room, error)
+	AddMember(ctx context.Context, roomName string, member chat.Member) error
+	RemoveMember(ctx context.Context, roomName string, member chat.Member) error
+	SendMessage(ctx context.Context, roomName string, member chat.Member, message string, attachments ...string) (*chat.Message, error)
+	Typing(ctx context.Context, roomName string, member chat.Member) error
+	MarkRead(ctx context.Context, roomName string, member chat.Member, id string) (int, error)
+	Unread(ctx context.Context, username string) ([]chat.UnreadCount, error)
+}
+
+type Server struct {
+	pb.UnimplementedChatServer
+
+	chatService chatService
+	logger      *slog.Logger
+
+	mu       sync.Mutex
+	sessions map[string]*StreamMember
+}
+
+func NewServer(chatService chatService, logger *slog.Logger) *Server {
+	return &Server{
+		chatService: chatService,
+		logger:      logger,
+		sessions:    make(map[string]*StreamMember),
+	}
+}
+
+func (s *Server) CreateRoom(ctx context.Context, req *pb.CreateRoomRequest) (*pb.CreateRoomResponse, error) {
+	if !roomNameRegex.MatchString(req.GetRoomName()) {
+		return nil, status.Error(codes.InvalidArgument, "invalid room name")
+	}
+
+	_, err := s.chatService.CreateRoom(ctx, req.GetRoomName(), usernameFromContext(ctx))
+	if err != nil {
+		return nil, statusError(fmt.Errorf("failed to create room: %w", err))
+	}
+
+	return &pb.CreateRoomResponse{}, nil
+}
+
+func (s *Server) JoinRoom(ctx context.Context, req *pb.JoinRoomRequest) (*pb.JoinRoomResponse, error) {
+	member, err := s.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.chatService.AddMember(ctx, req.GetRoomName(), member)
+	if err != nil {
+		return nil, statusError(fmt.Errorf("failed to join room: %w", err))
+	}
+
+	return &pb.JoinRoomResponse{}, nil
+}
+
+func (s *Server) LeaveRoom(ctx context.Context, req *pb.LeaveRoomRequest) (*pb.LeaveRoomResponse, error) {
+	member, err := s.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.chatService.RemoveMember(ctx, req.GetRoomName(), member)
+	if err != nil {
+		return nil, statusError(fmt.Errorf("failed to leave room: %w", err))
+	}
+
+	return &pb.LeaveRoomResponse{}, nil
+}
+
+func (s *Server) SendMessage(ctx context.Context, req *pb.SendMessageRequest) (*pb.SendMessageResponse, error) {
+	if req.GetMessage() == "" && len(req.GetAttachments()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty message")
+	}
+
+	member, err := s.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := s.chatService.SendMessage(ctx, req.GetRoomName(), member, req.GetMessage(), req.GetAttachments()...)
+	if err != nil {
+		return nil, statusError(fmt.Errorf("failed to send message: %w", err))
+	}
+
+	return &pb.SendMessageResponse{MessageId: sent.ID}, nil
+}
+
+func (s *Server) Typing(ctx context.Context, req *pb.TypingRequest) (*pb.TypingResponse, error) {
+	member, err := s.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.chatService.Typing(ctx, req.GetRoomName(), member)
+	if err != nil {
+		return nil, statusError(fmt.Errorf("failed to signal typing: %w", err))
+	}
+
+	return &pb.TypingResponse{}, nil
+}
+
+func (s *Server) MarkRead(ctx context.Context, req *pb.MarkReadRequest) (*pb.MarkReadResponse, error) {
+	member, err := s.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.chatService.MarkRead(ctx, req.GetRoomName(), member, req.GetMessageId())
+	if err != nil {
+		return nil, statusError(fmt.Errorf("failed to mark as read: %w", err))
+	}
+
+	return &pb.MarkReadResponse{Unread: int32(unread)}, nil
+}
+
+func (s *Server) Unread(ctx context.Context, _ *pb.UnreadRequest) (*pb.UnreadResponse, error) {
+	counts, err := s.chatService.Unread(ctx, usernameFromContext(ctx))
+	if err != nil {
+		return nil, statusError(fmt.Errorf("failed to count unread messages: %w", err))
+	}
+
+	res := &pb.UnreadResponse{}
+	for _, count := range counts {
+		res.Rooms = append(res.Rooms, &pb.UnreadCount{RoomName: count.Room, Count: int32(count.Count)})
+	}
+
+	return res, nil
+}
+
+func (s *Server) Connect(stream grpc.BidiStreamingServer[pb.Command, pb.Event]) error {
+	ctx := stream.Context()
+
+	username := usernameFromContext(ctx)
+	member := NewStreamMember(username, stream, s.logger.With(slog.String("username", username)))
+	defer member.close(nil)
+
+	err := s.connect(ctx, member)
+	if err != nil {
+		return err
+	}
+	defer s.disconnect(ctx, member)
+
+	commands := make(chan *pb.Command)
+	errs := make(chan error, 1)
+
+	go func() {
+		for {
+			cmd, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case commands <- cmd:
+			case <-member.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-member.Done():
+			return member.Err()
+		case err := <-errs:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case cmd := <-commands:
+			name, err := s.execute(ctx, cmd)
+			if err != nil {
+				member.Send(&pb.Event{Event: &pb.Event_CommandFailed{CommandFailed: &pb.CommandFailed{
+					Command: name,
+					Error:   status.Convert(err).Message(),
+				}}})
+			}
+		}
+	}
+}
+
+// execute runs a command received on the Connect stream through the unary
+// handlers, so that both transports behave identically.
+func (s *Server) execute(ctx context.Context, cmd *pb.Command) (string, error) {
+	var err error
+
+	switch c := cmd.GetCommand().(type) {
+	case *pb.Command_CreateRoom:
+		_, err = s.CreateRoom(ctx, c.CreateRoom)
+		return "create_room", err
+	case *pb.Command_JoinRoom:
+		_, err = s.JoinRoom(ctx, c.JoinRoom)
+		return "join_room", err
+	case *pb.Command_LeaveRoom:
+		_, err = s.LeaveRoom(ctx, c.LeaveRoom)
+		return "leave_room", err
+	case *pb.Command_SendMessage:
+		_, err = s.SendMessage(ctx, c.SendMessage)
+		return "send_message", err
+	case *pb.Command_Typing:
+		_, err = s.Typing(ctx, c.Typing)
+		return "typing", err
+	case *pb.Command_MarkRead:
+		_, err = s.MarkRead(ctx, c.MarkRead)
+		return "mark_read", err
+	default:
+		return "unknown", status.Error(codes.InvalidArgument, "unsupported command")
+	}
+}
+
+// connect registers the member with the chat service, which rejects users
+// already connected through any transport.
+func (s *Server) connect(ctx context.Context, member *StreamMember) error {
+	err := s.chatService.Connect(ctx, member)
+	if err != nil {
+		return statusError(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[member.Username()] = member
+
+	return nil
+}
+
+func (s *Server) disconnect(ctx context.Context, member *StreamMember) {
+	s.mu.Lock()
+	if s.sessions[member.Username()] == member {
+		delete(s.sessions, member.Username())
+	}
+	s.mu.Unlock()
+
+	_ = s.chatService.Disconnect(ctx, member)
+}
+
+// session returns the member backing the caller's Connect stream. Room
+// membership is bound to the stream, since that is where events are delivered.
+func (s *Server) session(ctx context.Context) (*StreamMember, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member, ok := s.sessions[usernameFromContext(ctx)]
+	if !ok {
+		return nil, status.Error(codes.FailedPrecondition, "not connected: open a Connect stream first")
+	}
+
+	return member, nil
+}
+
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, chat.ErrRoomNotFound), errors.Is(err, chat.ErrAttachmentNotFound), errors.Is(err, chat.ErrMessageNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, chat.ErrRoomAlreadyExists), errors.Is(err, chat.ErrMemberAlreadyExists), errors.Is(err, chat.ErrAlreadyConnected):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, chat.ErrNotRoomMember), errors.Is(err, chat.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, chat.ErrRoomMigrating):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,406 @@
+package rpc_test
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"practice-run/auth"
+	"practice-run/chat"
+	"practice-run/rpc"
+	"practice-run/rpc/pb"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type Suite struct {
+	suite.Suite
+	authenticator *auth.JWTAuthenticator
+	server        *grpc.Server
+	client        pb.ChatClient
+	chatService   *chat.Service
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSubTest() {
+	lis := bufconn.Listen(1024 * 1024)
+
+	s.authenticator = auth.NewJWTAuthenticator([]byte("secret"))
+	interceptor := rpc.NewAuthInterceptor(s.authenticator)
+
+	s.server = grpc.NewServer(
+		grpc.UnaryInterceptor(interceptor.Unary),
+		grpc.StreamInterceptor(interceptor.Stream),
+	)
+	s.chatService = chat.NewService()
+	pb.RegisterChatServer(s.server, rpc.NewServer(s.chatService, slog.Default()))
+
+	go func() {
+		_ = s.server.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	s.Require().NoError(err)
+
+	s.client = pb.NewChatClient(conn)
+}
+
+func (s *Suite) TearDownSubTest() {
+	s.server.Stop()
+}
+
+func (s *Suite) TestAuthentication() {
+	s.Run("reject unauthenticated requests", func() {
+		// When
+		_, err := s.client.CreateRoom(context.Background(), &pb.CreateRoomRequest{RoomName: "room_1"})
+
+		// Then
+		s.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	s.Run("reject invalid tokens", func() {
+		// Given
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid")
+
+		// When
+		_, err := s.client.CreateRoom(ctx, &pb.CreateRoomRequest{RoomName: "room_1"})
+
+		// Then
+		s.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	s.Run("accept authenticated requests", func() {
+		// When
+		_, err := s.client.CreateRoom(s.userContext("user_1"), &pb.CreateRoomRequest{RoomName: "room_1"})
+
+		// Then
+		s.NoError(err)
+	})
+}
+
+func (s *Suite) TestCreateRoom() {
+	s.Run("room already exists", func() {
+		// Given
+		ctx := s.userContext("user_1")
+		_, _ = s.client.CreateRoom(ctx, &pb.CreateRoomRequest{RoomName: "room_1"})
+
+		// When
+		_, err := s.client.CreateRoom(ctx, &pb.CreateRoomRequest{RoomName: "room_1"})
+
+		// Then
+		s.Equal(codes.AlreadyExists, status.Code(err))
+	})
+
+	s.Run("invalid room name", func() {
+		// When
+		_, err := s.client.CreateRoom(s.userContext("user_1"), &pb.CreateRoomRequest{RoomName: "room 1"})
+
+		// Then
+		s.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func (s *Suite) TestJoinRoom() {
+	s.Run("must connect before joining", func() {
+		// Given
+		ctx := s.userContext("user_1")
+		_, _ = s.client.CreateRoom(ctx, &pb.CreateRoomRequest{RoomName: "room_1"})
+
+		// When
+		_, err := s.client.JoinRoom(ctx, &pb.JoinRoomRequest{RoomName: "room_1"})
+
+		// Then
+		s.Equal(codes.FailedPrecondition, status.Code(err))
+	})
+
+	s.Run("room not found", func() {
+		// Given
+		ctx := s.userContext("user_1")
+		s.connect(ctx)
+
+		// When
+		_, err := s.client.JoinRoom(ctx, &pb.JoinRoomRequest{RoomName: "room_1"})
+
+		// Then
+		s.Equal(codes.NotFound, status.Code(err))
+	})
+}
+
+func (s *Suite) TestConnect() {
+	s.Run("receive room events", func() {
+		// Given
+		ctx1 := s.userContext("user_1")
+		ctx2 := s.userContext("user_2")
+		stream1 := s.connect(ctx1)
+		s.connect(ctx2)
+
+		_, err := s.client.CreateRoom(ctx1, &pb.CreateRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+		_, err = s.client.JoinRoom(ctx1, &pb.JoinRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+
+		// When
+		_, err = s.client.JoinRoom(ctx2, &pb.JoinRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+		_, err = s.client.SendMessage(ctx2, &pb.SendMessageRequest{RoomName: "room_1", Message: "hello"})
+		s.Require().NoError(err)
+		_, err = s.client.LeaveRoom(ctx2, &pb.LeaveRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+
+		// Then
+		event, err := stream1.Recv()
+		s.Require().NoError(err)
+		s.Equal("user_2", event.GetMemberJoined().GetMemberName())
+
+		event, err = stream1.Recv()
+		s.Require().NoError(err)
+		s.Equal("hello", event.GetMessageReceived().GetMessage())
+		s.Equal("user_2", event.GetMessageReceived().GetSenderName())
+
+		event, err = stream1.Recv()
+		s.Require().NoError(err)
+		s.Equal("user_2", event.GetMemberLeft().GetMemberName())
+	})
+
+	s.Run("receive invitations", func() {
+		// Given
+		ctx1 := s.userContext("user_1")
+		ctx2 := s.userContext("user_2")
+		s.connect(ctx1)
+		stream2 := s.connect(ctx2)
+		_, err := s.client.CreateRoom(ctx1, &pb.CreateRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+		_, err = s.client.JoinRoom(ctx1, &pb.JoinRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+
+		// When
+		err = s.chatService.InviteMember(context.Background(), "room_1", &namedMember{username: "user_1"}, "user_2")
+		s.Require().NoError(err)
+
+		// Then
+		event, err := stream2.Recv()
+		s.Require().NoError(err)
+		s.Equal("room_1", event.GetInvited().GetRoomName())
+		s.Equal("user_1", event.GetInvited().GetInviterName())
+	})
+
+	s.Run("execute commands sent on the stream", func() {
+		// Given
+		ctx1 := s.userContext("user_1")
+		ctx2 := s.userContext("user_2")
+		stream1 := s.connect(ctx1)
+		stream2 := s.connect(ctx2)
+
+		_, err := s.client.CreateRoom(ctx1, &pb.CreateRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+		_, err = s.client.JoinRoom(ctx1, &pb.JoinRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+
+		// When
+		s.Require().NoError(stream2.Send(&pb.Command{Command: &pb.Command_JoinRoom{
+			JoinRoom: &pb.JoinRoomRequest{RoomName: "room_1"},
+		}}))
+
+		// Then
+		event, err := stream1.Recv()
+		s.Require().NoError(err)
+		s.Equal("user_2", event.GetMemberJoined().GetMemberName())
+	})
+
+	s.Run("signal typing on the stream", func() {
+		// Given
+		ctx1 := s.userContext("user_1")
+		ctx2 := s.userContext("user_2")
+		stream1 := s.connect(ctx1)
+		stream2 := s.connect(ctx2)
+
+		_, err := s.client.CreateRoom(ctx1, &pb.CreateRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+		_, err = s.client.JoinRoom(ctx1, &pb.JoinRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+		_, err = s.client.JoinRoom(ctx2, &pb.JoinRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+		_, err = stream1.Recv()
+		s.Require().NoError(err)
+
+		// When
+		s.Require().NoError(stream2.Send(&pb.Command{Command: &pb.Command_Typing{
+			Typing: &pb.TypingRequest{RoomName: "room_1"},
+		}}))
+
+		// Then
+		event, err := stream1.Recv()
+		s.Require().NoError(err)
+		s.Equal("room_1", event.GetTyping().GetRoomName())
+		s.Equal("user_2", event.GetTyping().GetUsername())
+		s.Positive(event.GetTyping().GetExpiresAt())
+	})
+
+	s.Run("mark messages as read", func() {
+		// Given
+		ctx1 := s.userContext("user_1")
+		ctx2 := s.userContext("user_2")
+		stream1 := s.connect(ctx1)
+		s.connect(ctx2)
+
+		_, err := s.client.CreateRoom(ctx1, &pb.CreateRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+		_, err = s.client.JoinRoom(ctx1, &pb.JoinRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+		_, err = s.client.JoinRoom(ctx2, &pb.JoinRoomRequest{RoomName: "room_1"})
+		s.Require().NoError(err)
+		sent, err := s.client.SendMessage(ctx1, &pb.SendMessageRequest{RoomName: "room_1", Message: "hello"})
+		s.Require().NoError(err)
+		_, err = s.client.SendMessage(ctx1, &pb.SendMessageRequest{RoomName: "room_1", Message: "anyone?"})
+		s.Require().NoError(err)
+		_, err = stream1.Recv()
+		s.Require().NoError(err)
+
+		// When
+		res, err := s.client.MarkRead(ctx2, &pb.MarkReadRequest{RoomName: "room_1", MessageId: sent.GetMessageId()})
+
+		// Then
+		s.Require().NoError(err)
+		s.Equal(int32(1), res.GetUnread())
+		unread, err := s.client.Unread(ctx2, &pb.UnreadRequest{})
+		s.Require().NoError(err)
+		s.Len(unread.GetRooms(), 1)
+		s.Equal("room_1", unread.GetRooms()[0].GetRoomName())
+		s.Equal(int32(1), unread.GetRooms()[0].GetCount())
+		event, err := stream1.Recv()
+		s.Require().NoError(err)
+		s.Equal(sent.GetMessageId(), event.GetReadReceipt().GetMessageId())
+		s.Equal("user_2", event.GetReadReceipt().GetUsername())
+	})
+
+	s.Run("report failed commands", func() {
+		// Given
+		stream := s.connect(s.userContext("user_1"))
+
+		// When
+		s.Require().NoError(stream.Send(&pb.Command{Command: &pb.Command_SendMessage{
+			SendMessage: &pb.SendMessageRequest{RoomName: "room_1", Message: "hello"},
+		}}))
+
+		// Then
+		event, err := stream.Recv()
+		s.Require().NoError(err)
+		s.Equal("send_message", event.GetCommandFailed().GetCommand())
+		s.Equal("failed to send message: room not found", event.GetCommandFailed().GetError())
+	})
+
+	s.Run("reject duplicate connections", func() {
+		// Given
+		ctx := s.userContext("user_1")
+		s.connect(ctx)
+
+		// When
+		stream, err := s.client.Connect(ctx)
+		s.Require().NoError(err)
+		_, err = stream.Recv()
+
+		// Then
+		s.Equal(codes.AlreadyExists, status.Code(err))
+	})
+
+	s.Run("end streams on server shutdown", func() {
+		// Given
+		stream := s.connect(s.userContext("user_1"))
+
+		// When
+		err := s.chatService.Broadcast(context.Background(), &chat.ServerShutdownEvent{})
+		s.Require().NoError(err)
+		_, err = stream.Recv()
+
+		// Then
+		s.Equal(codes.Unavailable, status.Code(err))
+	})
+}
+
+func (s *Suite) TestStreamMember() {
+	s.Run("end the streams of clients not keeping up", func() {
+		// Given
+		stream := &blockedStream{}
+		member := rpc.NewStreamMember("user_1", stream, slog.Default())
+
+		// When
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range 1000 {
+				member.Notify(&chat.MemberJoinedEvent{RoomName: "room_1", MemberName: "user_2"})
+			}
+		}()
+
+		// Then
+		s.Eventually(func() bool {
+			select {
+			case <-done:
+				return true
+			default:
+				return false
+			}
+		}, time.Second, 10*time.Millisecond)
+		s.Equal(codes.ResourceExhausted, status.Code(member.Err()))
+	})
+}
+
+// namedMember stands for a member of a room acting through the chat service.
+type namedMember struct {
+	username string
+}
+
+func (m *namedMember) Username() string {
+	return m.username
+}
+
+func (m *namedMember) Notify(chat.Event) {}
+
+// blockedStream is a stream whose sends never complete, as those to a client
+// not reading its events.
+type blockedStream struct {
+	grpc.ServerStream
+}
+
+func (s *blockedStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *blockedStream) Send(*pb.Event) error {
+	select {}
+}
+
+// connect opens a Connect stream and waits until the server has registered it.
+func (s *Suite) connect(ctx context.Context) grpc.BidiStreamingClient[pb.Command, pb.Event] {
+	stream, err := s.client.Connect(ctx)
+	s.Require().NoError(err)
+
+	s.Require().Eventually(func() bool {
+		_, err := s.client.LeaveRoom(ctx, &pb.LeaveRoomRequest{RoomName: "__probe__"})
+		return status.Code(err) != codes.FailedPrecondition
+	}, time.Second, 10*time.Millisecond)
+
+	return stream
+}
+
+func (s *Suite) userContext(username string) context.Context {
+	token, err := s.authenticator.Sign(username, time.Minute)
+	s.Require().NoError(err)
+
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
@@ -0,0 +1,46 @@
+package test
+
+func (s *Suite) TestACL() {
+	s.Run("read-only room", func() {
+		owner := NewClient(s, "owner")
+		bot := NewClient(s, "release_bot")
+		client := NewClient(s, "user_1")
+
+		owner.CreateRoom("releases")
+		owner.WriteMessage("/acl #releases deny post *")
+		owner.ExpectMessage("#releases acl: added deny post *")
+		owner.WriteMessage("/acl #releases allow post @release_bot")
+		owner.ExpectMessage("#releases acl: added allow post @release_bot")
+
+		bot.JoinRoom("releases")
+		client.JoinRoom("releases")
+		bot.ExpectMessage("#releases: @user_1 joined")
+
+		client.SendMessage("releases", "hello")
+		client.ExpectErrorMessage()
+
+		bot.SendMessage("releases", "v1.2.3 released")
+		bot.ExpectChatMessage("#releases: @release_bot: v1.2.3 released")
+		client.ExpectChatMessage("#releases: @release_bot: v1.2.3 released")
+	})
+
+	s.Run("private room", func() {
+		owner := NewClient(s, "owner")
+		client := NewClient(s, "user_1")
+
+		owner.CreateRoom("private")
+		owner.WriteMessage("/acl #private deny join *")
+		owner.ExpectMessage("#private acl: added deny join *")
+		owner.WriteMessage("/acl #private allow join @owner")
+		owner.ExpectMessage("#private acl: added allow join @owner")
+		owner.JoinRoom("private")
+
+		client.JoinRoomRaw("private")
+		client.ExpectErrorMessage()
+
+		owner.WriteMessage("/invite #private @user_1")
+		owner.ExpectMessage("#private: @user_1 joined")
+		owner.ExpectMessage("you've invited @user_1 to #private")
+		client.ExpectMessage("#private: @owner invited you")
+	})
+}
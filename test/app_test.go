@@ -1,7 +1,11 @@
 package test
 
 import (
+	"fmt"
+	"log/slog"
 	"net/http/httptest"
+	"practice-run/auth"
+	"practice-run/config"
 	"practice-run/provider"
 	"sync"
 	"testing"
@@ -11,7 +15,8 @@ import (
 
 type Suite struct {
 	suite.Suite
-	server *httptest.Server
+	authenticator *auth.JWTAuthenticator
+	server        *httptest.Server
 }
 
 func TestSuite(t *testing.T) {
@@ -19,7 +24,8 @@ func TestSuite(t *testing.T) {
 }
 
 func (s *Suite) SetupSubTest() {
-	s.server = httptest.NewServer(provider.WebSocketHandler())
+	s.authenticator = auth.NewJWTAuthenticator([]byte("secret"))
+	s.server = httptest.NewServer(provider.WebSocketHandler(config.Default(), slog.Default(), provider.ChatService(slog.Default(), nil, nil), s.authenticator, nil))
 }
 
 func (s *Suite) TearDownSubTest() {
@@ -40,21 +46,21 @@ func (s *Suite) TestApp() {
 		client1.ExpectMessage("#room_1: @user_2 joined")
 
 		client1.SendMessage("room_1", "hello")
-		client1.ExpectMessage("#room_1: @user_1: hello")
-		client2.ExpectMessage("#room_1: @user_1: hello")
+		client1.ExpectChatMessage("#room_1: @user_1: hello")
+		client2.ExpectChatMessage("#room_1: @user_1: hello")
 
 		client2.SendMessage("room_1", "hi")
-		client1.ExpectMessage("#room_1: @user_2: hi")
-		client2.ExpectMessage("#room_1: @user_2: hi")
+		client1.ExpectChatMessage("#room_1: @user_2: hi")
+		client2.ExpectChatMessage("#room_1: @user_2: hi")
 
 		client3.JoinRoom("room_1")
 		client1.ExpectMessage("#room_1: @user_3 joined")
 		client2.ExpectMessage("#room_1: @user_3 joined")
 
 		client3.SendMessage("room_1", "hey")
-		client1.ExpectMessage("#room_1: @user_3: hey")
-		client2.ExpectMessage("#room_1: @user_3: hey")
-		client3.ExpectMessage("#room_1: @user_3: hey")
+		client1.ExpectChatMessage("#room_1: @user_3: hey")
+		client2.ExpectChatMessage("#room_1: @user_3: hey")
+		client3.ExpectChatMessage("#room_1: @user_3: hey")
 
 		client2.LeaveRoom("room_1")
 		client1.ExpectMessage("#room_1: @user_2 left")
@@ -100,25 +106,25 @@ func (s *Suite) TestApp() {
 			"#room_1: @user_3: hey",
 		}
 
-		c1m1 := client1.ReadMessage()
-		c1m2 := client1.ReadMessage()
-		c1m3 := client1.ReadMessage()
+		_, c1m1 := client1.ReadChatMessage()
+		_, c1m2 := client1.ReadChatMessage()
+		_, c1m3 := client1.ReadChatMessage()
 
 		s.Contains(expectedMessages, c1m1)
 		s.Contains(expectedMessages, c1m2)
 		s.Contains(expectedMessages, c1m3)
 
-		c2m1 := client2.ReadMessage()
-		c2m2 := client2.ReadMessage()
-		c2m3 := client2.ReadMessage()
+		_, c2m1 := client2.ReadChatMessage()
+		_, c2m2 := client2.ReadChatMessage()
+		_, c2m3 := client2.ReadChatMessage()
 
 		s.Contains(expectedMessages, c2m1)
 		s.Contains(expectedMessages, c2m2)
 		s.Contains(expectedMessages, c2m3)
 
-		c3m1 := client3.ReadMessage()
-		c3m2 := client3.ReadMessage()
-		c3m3 := client3.ReadMessage()
+		_, c3m1 := client3.ReadChatMessage()
+		_, c3m2 := client3.ReadChatMessage()
+		_, c3m3 := client3.ReadChatMessage()
 
 		s.Contains(expectedMessages, c3m1)
 		s.Contains(expectedMessages, c3m2)
@@ -163,4 +169,28 @@ func (s *Suite) TestApp() {
 		client.SendMessage("room_2", "hello")
 		client.ExpectErrorMessage()
 	})
+	s.Run("edit and delete messages", func() {
+		client1 := NewClient(s, "user_1")
+		client2 := NewClient(s, "user_2")
+
+		client1.CreateRoom("room_1")
+		client1.JoinRoom("room_1")
+		client2.JoinRoom("room_1")
+		client1.ExpectMessage("#room_1: @user_2 joined")
+
+		client2.SendMessage("room_1", "helo")
+		id := client2.ExpectChatMessage("#room_1: @user_2: helo")
+		client1.ExpectChatMessage("#room_1: @user_2: helo")
+
+		client1.WriteMessage(fmt.Sprintf("/edit %s hello", id))
+		client1.ExpectErrorMessage()
+
+		client2.WriteMessage(fmt.Sprintf("/edit %s hello", id))
+		client2.ExpectMessage(fmt.Sprintf("[%s] #room_1: @user_2 (edited): hello", id))
+		client1.ExpectMessage(fmt.Sprintf("[%s] #room_1: @user_2 (edited): hello", id))
+
+		client1.WriteMessage(fmt.Sprintf("/delete %s", id))
+		client1.ExpectMessage(fmt.Sprintf("[%s] #room_1: message deleted by @user_1", id))
+		client2.ExpectMessage(fmt.Sprintf("[%s] #room_1: message deleted by @user_1", id))
+	})
 }
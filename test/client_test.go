@@ -2,12 +2,18 @@ package test
 
 import (
 	"fmt"
+	"net/http"
+	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+// messageIDRegex matches the ID prefixing chat messages.
+var messageIDRegex = regexp.MustCompile(`^\[([0-9a-f]+)\] `)
+
 type Client struct {
 	s        *Suite
 	userName string
@@ -19,7 +25,12 @@ type Client struct {
 func NewClient(s *Suite, userName string) *Client {
 	s.T().Helper()
 
-	conn, _, err := websocket.DefaultDialer.Dial(strings.ReplaceAll(s.server.URL, "http", "ws")+"?username="+userName, nil)
+	token, err := s.authenticator.Sign(userName, time.Minute)
+	s.Require().NoError(err)
+
+	header := http.Header{"Authorization": {"Bearer " + token}}
+
+	conn, _, err := websocket.DefaultDialer.Dial(strings.ReplaceAll(s.server.URL, "http", "ws"), header)
 
 	s.Require().NoError(err)
 
@@ -60,6 +71,30 @@ func (c *Client) ExpectMessage(expected string) {
 	c.s.Require().Equal(expected, c.ReadMessage())
 }
 
+// ReadChatMessage reads a chat message, and returns its ID and the message
+// without it.
+func (c *Client) ReadChatMessage() (string, string) {
+	c.s.T().Helper()
+
+	msg := c.ReadMessage()
+
+	match := messageIDRegex.FindStringSubmatch(msg)
+	c.s.Require().NotNil(match, "expected chat message, got: %s", msg)
+
+	return match[1], strings.TrimPrefix(msg, match[0])
+}
+
+// ExpectChatMessage expects a chat message, whatever its ID, and returns its
+// ID.
+func (c *Client) ExpectChatMessage(expected string) string {
+	c.s.T().Helper()
+
+	id, msg := c.ReadChatMessage()
+	c.s.Require().Equal(expected, msg)
+
+	return id
+}
+
 func (c *Client) ExpectErrorMessage() {
 	msg := c.ReadMessage()
 	c.s.Require().True(strings.HasPrefix(msg, "error: "), "expected error message, got: %s", msg)
@@ -102,3 +137,13 @@ func (c *Client) SendMessage(roomName, message string) {
 	c.s.T().Helper()
 	c.WriteMessage(fmt.Sprintf(`/msg #%s %s`, roomName, message))
 }
+
+func (c *Client) ExpectClose(code int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.s.T().Helper()
+
+	_, _, err := c.conn.ReadMessage()
+	c.s.Require().True(websocket.IsCloseError(err, code), "expected close %d, got: %v", code, err)
+}
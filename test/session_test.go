@@ -0,0 +1,67 @@
+package test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"practice-run/config"
+	"practice-run/handler"
+	"practice-run/provider"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func (s *Suite) TestSessions() {
+	s.Run("reject duplicate sessions", func() {
+		client := NewClient(s, "user_1")
+		client.CreateRoom("room_1")
+
+		token, _ := s.authenticator.Sign("user_1", time.Minute)
+		_, res, err := websocket.DefaultDialer.Dial(strings.ReplaceAll(s.server.URL, "http", "ws"), http.Header{"Authorization": {"Bearer " + token}})
+
+		s.Error(err)
+		s.Equal(http.StatusConflict, res.StatusCode)
+	})
+
+	s.Run("take over duplicate sessions", func() {
+		cfg := config.Default()
+		cfg.SessionPolicy = "takeover"
+
+		s.server.Close()
+		s.server = httptest.NewServer(provider.WebSocketHandler(cfg, slog.Default(), provider.ChatService(slog.Default(), nil, nil), s.authenticator, nil))
+
+		client1 := NewClient(s, "user_1")
+		client2 := NewClient(s, "user_2")
+
+		client1.CreateRoom("room_1")
+		client1.JoinRoom("room_1")
+		client2.JoinRoom("room_1")
+		client1.ExpectMessage("#room_1: @user_2 joined")
+
+		client1b := NewClient(s, "user_1")
+		client1.ExpectClose(handler.CloseSessionTakenOver)
+
+		client2.SendMessage("room_1", "hello")
+		client2.ExpectChatMessage("#room_1: @user_2: hello")
+		client1b.ExpectChatMessage("#room_1: @user_2: hello")
+
+		client1b.SendMessage("room_1", "hi")
+		client1b.ExpectChatMessage("#room_1: @user_1: hi")
+		client2.ExpectChatMessage("#room_1: @user_1: hi")
+	})
+
+	s.Run("leave rooms on disconnect", func() {
+		client1 := NewClient(s, "user_1")
+		client2 := NewClient(s, "user_2")
+
+		client1.CreateRoom("room_1")
+		client1.JoinRoom("room_1")
+		client2.JoinRoom("room_1")
+		client1.ExpectMessage("#room_1: @user_2 joined")
+
+		s.Require().NoError(client2.conn.Close())
+		client1.ExpectMessage("#room_1: @user_2 left")
+	})
+}